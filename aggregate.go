@@ -0,0 +1,190 @@
+package structql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+type (
+	// Aggregator represents aggregate function state, an aggregator is created for each group
+	Aggregator interface {
+		// Init initializes aggregation state
+		Init()
+		// Accumulate adds row argument values, NULL argument value is passed as nil
+		Accumulate(args ...interface{}) error
+		// Merge merges partial state of other aggregator created by the same aggregate function
+		Merge(other Aggregator) error
+		// Result returns aggregated value, nil result is NULL, it is also called between Accumulate calls for running window aggregates
+		Result() interface{}
+	}
+
+	// NewAggregator represents aggregator factory
+	NewAggregator func() Aggregator
+
+	// aggregateFunction represents registered aggregate function
+	aggregateFunction struct {
+		name       string
+		resultType func(args []reflect.Type) (reflect.Type, error)
+		new        NewAggregator
+	}
+
+	// aggregates represents query scoped aggregate functions, functions not found are looked up in global registry
+	aggregates struct {
+		functions map[string]*aggregateFunction
+	}
+)
+
+var (
+	globalAggregates = &aggregates{}
+	aggregatesMux    sync.RWMutex
+)
+
+// RegisterAggregate registers global aggregate function used in projection i.e. WAVG(Price, Qty),
+// nil resultType uses the first argument type, newAggregator creates aggregator for each group.
+func RegisterAggregate(name string, resultType reflect.Type, newAggregator NewAggregator) error {
+	function, err := newAggregateFunction(name, resultType, newAggregator)
+	if err != nil {
+		return err
+	}
+	aggregatesMux.Lock()
+	defer aggregatesMux.Unlock()
+	globalAggregates.register(function)
+	return nil
+}
+
+func newAggregateFunction(name string, resultType reflect.Type, newAggregator NewAggregator) (*aggregateFunction, error) {
+	if name == "" {
+		return nil, fmt.Errorf("aggregate function name was empty")
+	}
+	if newAggregator == nil {
+		return nil, fmt.Errorf("invalid aggregate function %v: aggregator factory was nil", name)
+	}
+	return &aggregateFunction{name: strings.ToUpper(name), resultType: fixedType(resultType), new: newAggregator}, nil
+}
+
+// fixedType returns result type resolver, nil type resolves to the first argument type
+func fixedType(rType reflect.Type) func(args []reflect.Type) (reflect.Type, error) {
+	return func(args []reflect.Type) (reflect.Type, error) {
+		if rType != nil {
+			return rType, nil
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("result type was not defined for aggregate without arguments")
+		}
+		return args[0], nil
+	}
+}
+
+func (a *aggregates) register(function *aggregateFunction) {
+	if a.functions == nil {
+		a.functions = map[string]*aggregateFunction{}
+	}
+	a.functions[function.name] = function
+}
+
+// lookup returns query scoped or global aggregate function for supplied name or nil
+func (a *aggregates) lookup(name string) *aggregateFunction {
+	name = strings.ToUpper(name)
+	if a != nil {
+		if ret, ok := a.functions[name]; ok {
+			return ret
+		}
+	}
+	aggregatesMux.RLock()
+	defer aggregatesMux.RUnlock()
+	return globalAggregates.functions[name]
+}
+
+// aggregation represents aggregate function projection
+type aggregation struct {
+	function *aggregateFunction
+	args     []compute
+}
+
+// newAggregator returns initialized group aggregator
+func (a *aggregation) newAggregator() Aggregator {
+	ret := a.function.new()
+	ret.Init()
+	return ret
+}
+
+// accumulate adds source item argument values to the aggregator
+func (a *aggregation) accumulate(aggregator Aggregator, src unsafe.Pointer) error {
+	args := make([]interface{}, len(a.args))
+	for i, arg := range a.args {
+		args[i] = arg(src)
+	}
+	if err := aggregator.Accumulate(args...); err != nil {
+		return fmt.Errorf("aggregate %v failed: %w", a.function.name, err)
+	}
+	return nil
+}
+
+// compileAggregation compiles aggregate function projection, it returns aggregation and result type
+func compileAggregation(source reflect.Type, function *aggregateFunction, call *expr.Call, functions *scalar.Registry) (*aggregation, reflect.Type, error) {
+	ret := &aggregation{function: function}
+	var types []reflect.Type
+	for _, arg := range call.Args {
+		if _, ok := arg.(*expr.Star); ok {
+			continue
+		}
+		argFn, argType, err := compileAggregateArgument(source, arg, functions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %v argument: %w", function.name, err)
+		}
+		ret.args = append(ret.args, argFn)
+		types = append(types, argType)
+	}
+	resultType, err := function.resultType(types)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %v call: %w", function.name, err)
+	}
+	return ret, resultType, nil
+}
+
+// compileAggregateArgument compiles aggregate argument, pointer field value is dereferenced, nil pointer is passed as nil
+func compileAggregateArgument(source reflect.Type, n node.Node, functions *scalar.Registry) (compute, reflect.Type, error) {
+	switch actual := n.(type) {
+	case *expr.Ident, *expr.Selector:
+		name := sqlparser.Stringify(actual)
+		aField := xunsafe.FieldByName(source, name)
+		if aField == nil {
+			return nil, nil, errs.UnknownField("failed to lookup source field: '%s' at %s", name, source.String())
+		}
+		rType := aField.Type
+		if rType.Kind() == reflect.Ptr {
+			rType = rType.Elem()
+		}
+		return func(src unsafe.Pointer) interface{} {
+			return fieldValue(aField, src)
+		}, rType, nil
+	}
+	argFn, kind, err := compileArgument(source, n, functions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return argFn, kind.Type(), nil
+}
+
+// fieldValue returns field value, pointer field value is dereferenced, nil pointer is returned as nil
+func fieldValue(aField *xunsafe.Field, src unsafe.Pointer) interface{} {
+	value := aField.Interface(src)
+	if aField.Kind() != reflect.Ptr {
+		return value
+	}
+	rValue := reflect.ValueOf(value)
+	if rValue.IsNil() {
+		return nil
+	}
+	return rValue.Elem().Interface()
+}
@@ -0,0 +1,253 @@
+package structql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/viant/structql/parser/scalar"
+)
+
+type (
+	countAggregator struct {
+		count int
+	}
+
+	sumAggregator struct {
+		ints   int
+		floats float64
+		float  bool
+		valid  bool
+	}
+
+	avgAggregator struct {
+		sum   float64
+		count int
+	}
+
+	// extremumAggregator computes MIN (sign = -1) or MAX (sign = 1)
+	extremumAggregator struct {
+		sign  int
+		value interface{}
+	}
+)
+
+func init() {
+	globalAggregates.register(&aggregateFunction{name: "COUNT", resultType: countType, new: func() Aggregator { return &countAggregator{} }})
+	globalAggregates.register(&aggregateFunction{name: "SUM", resultType: sumType, new: func() Aggregator { return &sumAggregator{} }})
+	globalAggregates.register(&aggregateFunction{name: "AVG", resultType: avgType, new: func() Aggregator { return &avgAggregator{} }})
+	globalAggregates.register(&aggregateFunction{name: "MIN", resultType: extremumType, new: func() Aggregator { return &extremumAggregator{sign: -1} }})
+	globalAggregates.register(&aggregateFunction{name: "MAX", resultType: extremumType, new: func() Aggregator { return &extremumAggregator{sign: 1} }})
+}
+
+func countType(args []reflect.Type) (reflect.Type, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("invalid COUNT arguments count: %v", len(args))
+	}
+	return reflect.TypeOf(0), nil
+}
+
+func sumType(args []reflect.Type) (reflect.Type, error) {
+	kind, err := numericArgument("SUM", args)
+	return kind.Type(), err
+}
+
+func avgType(args []reflect.Type) (reflect.Type, error) {
+	_, err := numericArgument("AVG", args)
+	return reflect.TypeOf(0.0), err
+}
+
+func extremumType(args []reflect.Type) (reflect.Type, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("invalid MIN/MAX arguments count: %v", len(args))
+	}
+	switch scalar.KindOf(args[0]) {
+	case scalar.Int, scalar.Float, scalar.String, scalar.Time:
+		return args[0], nil
+	}
+	return nil, fmt.Errorf("invalid MIN/MAX argument type: %s", args[0].String())
+}
+
+func numericArgument(name string, args []reflect.Type) (scalar.Kind, error) {
+	if len(args) != 1 {
+		return scalar.Any, fmt.Errorf("invalid %v arguments count: %v", name, len(args))
+	}
+	kind := scalar.KindOf(args[0])
+	if !kind.IsNumeric() {
+		return scalar.Any, fmt.Errorf("invalid %v argument type: %s, expected numeric", name, args[0].String())
+	}
+	return kind, nil
+}
+
+func incompatibleAggregator(aggregator, other Aggregator) error {
+	return fmt.Errorf("incompatible aggregator: %T, expected %T", other, aggregator)
+}
+
+// Init initializes aggregation state
+func (a *countAggregator) Init() {
+	a.count = 0
+}
+
+// Accumulate counts rows, or not NULL values when an argument is supplied
+func (a *countAggregator) Accumulate(args ...interface{}) error {
+	if len(args) == 0 || args[0] != nil {
+		a.count++
+	}
+	return nil
+}
+
+// Merge merges other count
+func (a *countAggregator) Merge(other Aggregator) error {
+	otherCount, ok := other.(*countAggregator)
+	if !ok {
+		return incompatibleAggregator(a, other)
+	}
+	a.count += otherCount.count
+	return nil
+}
+
+// Result returns count
+func (a *countAggregator) Result() interface{} {
+	return a.count
+}
+
+// Init initializes aggregation state
+func (a *sumAggregator) Init() {
+	*a = sumAggregator{}
+}
+
+// Accumulate adds not NULL value
+func (a *sumAggregator) Accumulate(args ...interface{}) error {
+	value := args[0]
+	if value == nil {
+		return nil
+	}
+	a.valid = true
+	if scalar.KindOf(reflect.TypeOf(value)) == scalar.Float {
+		a.float = true
+		a.floats += scalar.AsFloat(value)
+		return nil
+	}
+	a.ints += scalar.AsInt(value)
+	return nil
+}
+
+// Merge merges other sum
+func (a *sumAggregator) Merge(other Aggregator) error {
+	otherSum, ok := other.(*sumAggregator)
+	if !ok {
+		return incompatibleAggregator(a, other)
+	}
+	a.ints += otherSum.ints
+	a.floats += otherSum.floats
+	a.float = a.float || otherSum.float
+	a.valid = a.valid || otherSum.valid
+	return nil
+}
+
+// Result returns sum, or nil when no values were accumulated
+func (a *sumAggregator) Result() interface{} {
+	if !a.valid {
+		return nil
+	}
+	if a.float {
+		return a.floats + float64(a.ints)
+	}
+	return a.ints
+}
+
+// Init initializes aggregation state
+func (a *avgAggregator) Init() {
+	*a = avgAggregator{}
+}
+
+// Accumulate adds not NULL value
+func (a *avgAggregator) Accumulate(args ...interface{}) error {
+	if args[0] == nil {
+		return nil
+	}
+	a.sum += scalar.AsFloat(args[0])
+	a.count++
+	return nil
+}
+
+// Merge merges other average state
+func (a *avgAggregator) Merge(other Aggregator) error {
+	otherAvg, ok := other.(*avgAggregator)
+	if !ok {
+		return incompatibleAggregator(a, other)
+	}
+	a.sum += otherAvg.sum
+	a.count += otherAvg.count
+	return nil
+}
+
+// Result returns average, or nil when no values were accumulated
+func (a *avgAggregator) Result() interface{} {
+	if a.count == 0 {
+		return nil
+	}
+	return a.sum / float64(a.count)
+}
+
+// Init initializes aggregation state
+func (a *extremumAggregator) Init() {
+	a.value = nil
+}
+
+// Accumulate keeps not NULL value if it is lower (MIN) or greater (MAX) than the current one
+func (a *extremumAggregator) Accumulate(args ...interface{}) error {
+	value := args[0]
+	if value == nil {
+		return nil
+	}
+	if a.value == nil || compareValues(value, a.value)*a.sign > 0 {
+		a.value = value
+	}
+	return nil
+}
+
+// Merge merges other extremum
+func (a *extremumAggregator) Merge(other Aggregator) error {
+	otherExtremum, ok := other.(*extremumAggregator)
+	if !ok || otherExtremum.sign != a.sign {
+		return incompatibleAggregator(a, other)
+	}
+	return a.Accumulate(otherExtremum.value)
+}
+
+// Result returns extremum, or nil when no values were accumulated
+func (a *extremumAggregator) Result() interface{} {
+	return a.value
+}
+
+// compareValues compares numeric, string or time values, it returns -1, 0 or 1
+func compareValues(x, y interface{}) int {
+	switch scalar.KindOf(reflect.TypeOf(x)) {
+	case scalar.Int:
+		return compare(scalar.AsInt(x), scalar.AsInt(y))
+	case scalar.Float:
+		return compare(scalar.AsFloat(x), scalar.AsFloat(y))
+	case scalar.String:
+		return strings.Compare(scalar.AsString(x), scalar.AsString(y))
+	case scalar.Time:
+		return asTime(x).Compare(asTime(y))
+	}
+	return 0
+}
+
+func compare[T int | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// asTime returns time value, including named time types
+func asTime(value interface{}) time.Time {
+	return reflect.ValueOf(value).Convert(reflect.TypeOf(time.Time{})).Interface().(time.Time)
+}
@@ -1,32 +1,98 @@
 package structql
 
 import (
+	"context"
 	"github.com/viant/xunsafe"
+	"reflect"
+	"unsafe"
 )
 
 type Context struct {
-	value    interface{}
-	group    map[string]interface{}
+	group    map[interface{}]*group
+	groups   []*group
+	current  *group
 	mapper   *Mapper
 	appender *xunsafe.Appender
+	guard    *guard
+	sources  []unsafe.Pointer
 }
 
+// group represents aggregation group dest value with its aggregators
+type group struct {
+	value       interface{}
+	aggregators []Aggregator
+	appended    bool
+}
+
+// Next returns dest item for source item, source items of the same group share dest item,
+// nil source (empty collection) returns the aggregate dest item only without GROUP BY
 func (c *Context) Next(source interface{}) interface{} {
 	if !c.mapper.aggregate {
 		return c.appender.Add()
 	}
-	if len(c.group) == 0 {
-		if c.value == nil {
-			c.value = c.appender.Add()
+	var key interface{}
+	if len(c.mapper.groupBy) > 0 {
+		if source == nil {
+			return nil
 		}
-		return c.value
+		key = c.mapper.groupKey(xunsafe.AsPointer(source))
 	}
-	panic("group by not supproted yet")
+	aGroup, ok := c.group[key]
+	if !ok {
+		if len(c.mapper.groupBy) == 0 {
+			aGroup = c.mapper.newGroup(c.appender.Add())
+			aGroup.appended = true
+		} else {
+			aGroup = c.mapper.newGroup(nil)
+		}
+		c.group[key] = aGroup
+		c.groups = append(c.groups, aGroup)
+	}
+	c.current = aGroup
+	return aGroup.value
+}
+
+// accumulate adds source item to the current group aggregators
+func (c *Context) accumulate(srcItemPtr unsafe.Pointer) error {
+	for i := range c.mapper.aggregations {
+		if err := c.mapper.aggregations[i].aggregation.accumulate(c.current.aggregators[i], srcItemPtr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush sets aggregated values and appends grouped dest items in the order the groups were created
+func (c *Context) flush() error {
+	for _, aGroup := range c.groups {
+		valuePtr := xunsafe.AsPointer(aGroup.value)
+		for i := range c.mapper.aggregations {
+			c.mapper.aggregations[i].setComputed(valuePtr, aGroup.aggregators[i].Result())
+		}
+		if err := c.guard.row(); err != nil {
+			return err
+		}
+		if !aGroup.appended {
+			dest := c.appender.Add()
+			reflect.NewAt(c.mapper.dest, xunsafe.AsPointer(dest)).Elem().Set(reflect.NewAt(c.mapper.dest, valuePtr).Elem())
+			valuePtr = xunsafe.AsPointer(dest)
+		}
+		if c.guard.limits.MaxBytes > 0 {
+			if err := c.guard.result(c.mapper.resultSize(valuePtr)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func NewContext(mapper *Mapper, appender *xunsafe.Appender, aggregate bool) *Context {
+	return newContext(newGuard(context.Background(), Limits{}), mapper, appender, aggregate)
+}
+
+func newContext(aGuard *guard, mapper *Mapper, appender *xunsafe.Appender, aggregate bool) *Context {
 	if !aggregate {
-		return &Context{mapper: mapper, appender: appender}
+		return &Context{mapper: mapper, appender: appender, guard: aGuard}
 	}
-	return &Context{mapper: mapper, appender: appender, group: map[string]interface{}{}}
+	return &Context{mapper: mapper, appender: appender, group: map[interface{}]*group{}, guard: aGuard}
 }
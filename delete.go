@@ -0,0 +1,162 @@
+package structql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/viant/structql/errs"
+	node "github.com/viant/structql/node"
+	sparser "github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+// Delete represents DELETE statement removing matched elements from the owning slices selected by the target path
+type Delete struct {
+	query   string
+	node    *Node
+	Binding *node.Binding
+	limits  Limits
+}
+
+// Exec removes matched source elements preserving order of the remaining ones, it returns number of removed elements,
+// source has to be a pointer, or a slice when target elements are nested in pointer reachable objects
+func (d *Delete) Exec(source interface{}) (int, error) {
+	return d.ExecContext(context.Background(), source)
+}
+
+// ExecContext removes matched source elements, the walk is stopped with context error when context is done
+// or with LimitError when visited nodes cap is exceeded
+func (d *Delete) ExecContext(ctx context.Context, source interface{}) (affected int, err error) {
+	defer scalar.Recover(&err)
+	if d.node.kind == nodeKindArray && reflect.TypeOf(source).Kind() != reflect.Ptr {
+		return 0, errs.UnsupportedType("invalid source: %T, expected pointer to slice", source)
+	}
+	if err = checkMutableSource(source); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if affected > 0 { //indexes built over changed elements are stale
+			mutated(unwrapStruct(d.node.LeafType()))
+		}
+	}()
+	return deleteItems(newGuard(ctx, d.limits), d.node, source)
+}
+
+// deleteItems walks the node removing matched leaf items from their slices
+func deleteItems(aGuard *guard, aNode *Node, value interface{}) (int, error) {
+	if err := aGuard.visit(); err != nil {
+		return 0, err
+	}
+	if !aNode.When(value) {
+		return 0, nil
+	}
+	ptr := xunsafe.AsPointer(value)
+	if ptr == nil {
+		return 0, nil
+	}
+	switch aNode.kind {
+	case nodeKindObject:
+		if aNode.child == nil {
+			return 0, nil
+		}
+		return deleteItems(aGuard, aNode.child, aNode.fieldItem(ptr))
+	case nodeKindArray:
+		if aNode.child.IsLeaf {
+			return aNode.deleteMatched(aGuard, ptr)
+		}
+		result := 0
+		from, to := aNode.bounds(aNode.xSlice.Len(ptr))
+		for i := from; i < to; i++ {
+			count, err := deleteItems(aGuard, aNode.child, aNode.xSlice.ValuePointerAt(ptr, i))
+			if err != nil {
+				return 0, err
+			}
+			result += count
+		}
+		return result, nil
+	}
+	return 0, nil
+}
+
+// deleteMatched removes slice items matching leaf node criteria
+func (n *Node) deleteMatched(aGuard *guard, slicePtr unsafe.Pointer) (int, error) {
+	from, to, at := n.positions(slicePtr)
+	var matched []int
+	for i := from; i < to; i++ {
+		index := at(i)
+		if err := aGuard.visit(); err != nil {
+			return 0, err
+		}
+		item := n.xSlice.ValuePointerAt(slicePtr, index)
+		if xunsafe.AsPointer(item) == nil {
+			continue
+		}
+		if n.child.When(item) {
+			matched = append(matched, index)
+		}
+	}
+	if len(matched) > 0 {
+		n.removeAt(slicePtr, matched)
+	}
+	return len(matched), nil
+}
+
+// removeAt removes items at ascending positions shifting the remaining ones, released tail items are zeroed
+func (n *Node) removeAt(slicePtr unsafe.Pointer, positions []int) {
+	itemType := n.xSlice.Type.Elem()
+	itemAt := func(index int) reflect.Value {
+		return reflect.NewAt(itemType, n.xSlice.PointerAt(slicePtr, uintptr(index))).Elem()
+	}
+	slice := reflect.NewAt(n.xSlice.Type, slicePtr).Elem()
+	at, next := positions[0], 0
+	for i := positions[0]; i < slice.Len(); i++ {
+		if next < len(positions) && positions[next] == i {
+			next++
+			continue
+		}
+		itemAt(at).Set(itemAt(i))
+		at++
+	}
+	for i := at; i < slice.Len(); i++ {
+		itemAt(i).Set(reflect.Zero(itemType))
+	}
+	slice.SetLen(at)
+}
+
+// NewDelete returns DELETE statement for the source type, i.e.
+// DELETE FROM `/Vendors/Products` WHERE Status = 0
+func NewDelete(SQL string, source reflect.Type, values ...interface{}) (*Delete, error) {
+	if unwrapStruct(source) == nil {
+		return nil, errs.UnsupportedType("invalid source type: %s", source.String())
+	}
+	opts, values, err := newOptions(values)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Delete{query: SQL, Binding: &node.Binding{}, limits: opts.limits}
+	value := &node.Values{Values: values, Bindings: ret.Binding, Functions: opts.functions}
+	stmt, err := sparser.ParseDelete(SQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %w, %v", err, SQL)
+	}
+	if ret.node, err = newSelectorNode(source, stmt.Target.X, value, opts); err != nil {
+		return nil, err
+	}
+	owner := ret.node
+	for owner.child != nil && !owner.child.IsLeaf {
+		owner = owner.child
+	}
+	if owner.kind != nodeKindArray || unwrapStruct(owner.LeafType()) == nil {
+		return nil, errs.UnsupportedType("invalid delete target: %s, expected slice of struct", ret.node.LeafType().String())
+	}
+	if err = ret.node.compileQualify(stmt.Qualify, value, opts); err != nil {
+		return nil, err
+	}
+	if err = value.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
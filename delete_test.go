@@ -0,0 +1,122 @@
+package structql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"reflect"
+	"testing"
+)
+
+func TestNewDelete(t *testing.T) {
+	type Product struct {
+		ID     int
+		Status int
+	}
+	type Vendor struct {
+		ID       int
+		Products []Product
+		Featured []*Product
+	}
+	type Catalog struct {
+		Vendors []*Vendor
+	}
+	newCatalog := func() *Catalog {
+		return &Catalog{Vendors: []*Vendor{
+			{ID: 1, Products: []Product{{ID: 1, Status: 0}, {ID: 2, Status: 1}, {ID: 3, Status: 0}}, Featured: []*Product{{ID: 1}, {ID: 2, Status: 1}}},
+			{ID: 2, Products: []Product{{ID: 4, Status: 1}, {ID: 5, Status: 0}}},
+		}}
+	}
+
+	var testCases = []struct {
+		description    string
+		query          string
+		values         []interface{}
+		expectAffected int
+		expect         string
+		hasError       bool
+	}{
+		{
+			description:    "value slices",
+			query:          "DELETE FROM `/Vendors/Products` WHERE Status = 0",
+			expectAffected: 3,
+			expect:         `[{"ID":1,"Products":[{"ID":2}],"Featured":[{"ID":1},{"ID":2}]},{"ID":2,"Products":[{"ID":4}]}]`,
+		},
+		{
+			description:    "pointer slice",
+			query:          "DELETE FROM `/Vendors/Featured` WHERE ID = ?",
+			values:         []interface{}{1},
+			expectAffected: 1,
+			expect:         `[{"ID":1,"Products":[{"ID":1},{"ID":2},{"ID":3}],"Featured":[{"ID":2}]},{"ID":2}]`,
+		},
+		{
+			description:    "selected owner",
+			query:          "DELETE FROM `/Vendors[ID=?]/Products` WHERE ID > 1",
+			values:         []interface{}{1},
+			expectAffected: 2,
+			expect:         `[{"ID":1,"Products":[{"ID":1}]},{"ID":2,"Products":[{"ID":4},{"ID":5}]}]`,
+		},
+		{
+			description:    "all items",
+			query:          "DELETE FROM `/Vendors/Products`",
+			expectAffected: 5,
+			expect:         `[{"ID":1,"Products":[]},{"ID":2,"Products":[]}]`,
+		},
+		{
+			description:    "no match",
+			query:          "DELETE FROM `/Vendors/Products` WHERE Status = 2",
+			expectAffected: 0,
+			expect:         `[{"ID":1,"Products":[{"ID":1},{"ID":2},{"ID":3}]},{"ID":2,"Products":[{"ID":4},{"ID":5}]}]`,
+		},
+		{
+			description: "unknown field",
+			query:       "DELETE FROM `/Vendors/Products` WHERE Price = 0",
+			hasError:    true,
+		},
+		{
+			description: "non slice target",
+			query:       "DELETE FROM `/Vendors[0]/ID`",
+			hasError:    true,
+		},
+	}
+
+	for _, evaluator := range []Evaluator{IgoEvaluator, NativeEvaluator} {
+		for _, testCase := range testCases {
+			description := testCase.description + " (" + string(evaluator) + ")"
+			aDelete, err := NewDelete(testCase.query, reflect.TypeOf(&Catalog{}), append(testCase.values, WithEvaluator(evaluator))...)
+			if testCase.hasError {
+				assert.NotNil(t, err, description)
+				continue
+			}
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			catalog := newCatalog()
+			affected, err := aDelete.Exec(catalog)
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			assert.EqualValues(t, testCase.expectAffected, affected, description)
+			assertly.AssertValues(t, testCase.expect, catalog.Vendors, description)
+		}
+	}
+
+	products := []Product{{ID: 1}, {ID: 2, Status: 1}, {ID: 3}}
+	aDelete, err := NewDelete("DELETE FROM `/` WHERE Status = 1", reflect.TypeOf(products))
+	assert.Nil(t, err)
+	_, err = aDelete.Exec(products)
+	assert.NotNil(t, err, "non pointer root slice")
+	affected, err := aDelete.Exec(&products)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, affected)
+	assert.EqualValues(t, []Product{{ID: 1}, {ID: 3}}, products)
+	assert.EqualValues(t, Product{}, products[:3][2], "released item")
+
+	featured := []*Product{{ID: 1, Status: 1}, nil, {ID: 2}}
+	aDelete, err = NewDelete("DELETE FROM `/` WHERE Status = 1", reflect.TypeOf(featured))
+	assert.Nil(t, err)
+	affected, err = aDelete.Exec(&featured)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, affected)
+	assert.EqualValues(t, []*Product{nil, {ID: 2}}, featured, "nil item")
+	assert.Nil(t, featured[:3][2], "released pointer")
+}
@@ -0,0 +1,29 @@
+package structql
+
+import (
+	"errors"
+	"github.com/viant/structql/errs"
+)
+
+// error kind sentinels matched with errors.Is, see errs package
+var (
+	// ErrUnknownField is matched when a query references a field that the source or dest type does not define
+	ErrUnknownField = errs.ErrUnknownField
+	// ErrUnsupportedType is matched when a source, target or field type is not supported
+	ErrUnsupportedType = errs.ErrUnsupportedType
+	// ErrConversion is matched when a value can not be converted or assigned to a field
+	ErrConversion = errs.ErrConversion
+	// ErrSyntax is matched when a statement, criteria or selector can not be parsed, use errors.As with *SyntaxError for position
+	ErrSyntax = errs.ErrSyntax
+	// ErrLimitExceeded is matched when any query resource cap is exceeded, use errors.As with *LimitError for details
+	ErrLimitExceeded = errs.ErrLimitExceeded
+	// ErrMultipleColumns is matched when a scalar query yields more than one column
+	ErrMultipleColumns = errors.New("multiple columns")
+	// ErrMultipleRows is matched when a scalar query yields more than one row
+	ErrMultipleRows = errors.New("multiple rows")
+	// ErrNull is matched when a typed scalar query yields NULL value or no rows
+	ErrNull = errors.New("null value")
+)
+
+// SyntaxError represents parsing error with the byte offset in the parsed text
+type SyntaxError = errs.SyntaxError
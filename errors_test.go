@@ -0,0 +1,159 @@
+package structql
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/structql/parser"
+	"reflect"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	type Product struct {
+		ID   int
+		Name string
+	}
+	type Vendor struct {
+		ID       int
+		Products []*Product
+	}
+	type Summary struct {
+		ID int
+	}
+
+	var testCases = []struct {
+		description string
+		fn          func() error
+		expect      error
+		offset      int
+	}{
+		{
+			description: "unknown projection field",
+			fn: func() error {
+				_, err := NewQuery("SELECT Code FROM `/Products`", reflect.TypeOf(&Vendor{}), nil)
+				return err
+			},
+			expect: ErrUnknownField,
+		},
+		{
+			description: "unknown criteria field",
+			fn: func() error {
+				_, err := NewQuery("SELECT ID FROM `/Products` WHERE Code = 1", reflect.TypeOf(&Vendor{}), nil)
+				return err
+			},
+			expect: ErrUnknownField,
+		},
+		{
+			description: "unknown selector field",
+			fn: func() error {
+				_, err := NewQuery("SELECT ID FROM `/Items`", reflect.TypeOf(&Vendor{}), nil)
+				return err
+			},
+			expect: ErrUnknownField,
+		},
+		{
+			description: "unknown dest field",
+			fn: func() error {
+				_, err := NewQuery("SELECT ID, Name FROM `/Products`", reflect.TypeOf(&Vendor{}), reflect.TypeOf(Summary{}))
+				return err
+			},
+			expect: ErrUnknownField,
+		},
+		{
+			description: "unknown dest aggregate field",
+			fn: func() error {
+				_, err := NewQuery("SELECT COUNT(*) AS Cnt FROM `/Products`", reflect.TypeOf(&Vendor{}), reflect.TypeOf(Summary{}))
+				return err
+			},
+			expect: ErrUnknownField,
+		},
+		{
+			description: "unknown dest function field",
+			fn: func() error {
+				_, err := NewQuery("SELECT LOWER(Name) AS Name FROM `/Products`", reflect.TypeOf(&Vendor{}), reflect.TypeOf(Summary{}))
+				return err
+			},
+			expect: ErrUnknownField,
+		},
+		{
+			description: "unknown dest window field",
+			fn: func() error {
+				_, err := NewQuery("SELECT ID, ROW_NUMBER() OVER (ORDER BY ID) AS Rn FROM `/Products`", reflect.TypeOf(&Vendor{}), reflect.TypeOf(Summary{}))
+				return err
+			},
+			expect: ErrUnknownField,
+		},
+		{
+			description: "unsupported source type",
+			fn: func() error {
+				_, err := NewQuery("SELECT ID FROM `/`", reflect.TypeOf(1), nil)
+				return err
+			},
+			expect: ErrUnsupportedType,
+		},
+		{
+			description: "criteria syntax",
+			fn: func() error {
+				_, err := NewQuery("SELECT ID FROM `/Products` WHERE ID = = 1", reflect.TypeOf(&Vendor{}), nil)
+				return err
+			},
+			expect: ErrSyntax,
+			offset: 38,
+		},
+		{
+			description: "insert syntax",
+			fn: func() error {
+				_, err := parser.ParseInsert("INSERT INTO `/Products` (ID) VALUE (1)")
+				return err
+			},
+			expect: ErrSyntax,
+			offset: 29,
+		},
+		{
+			description: "update conversion",
+			fn: func() error {
+				update, err := NewUpdate("UPDATE `/Products` SET Name = ?", reflect.TypeOf(&Vendor{}), 1)
+				if err != nil {
+					return err
+				}
+				_, err = update.Exec(&Vendor{Products: []*Product{{ID: 1}}})
+				return err
+			},
+			expect: ErrConversion,
+		},
+		{
+			description: "limit exceeded",
+			fn: func() error {
+				query, err := NewQuery("SELECT ID FROM `/Products`", reflect.TypeOf(&Vendor{}), nil, WithMaxRows(1))
+				if err != nil {
+					return err
+				}
+				_, err = query.Select(&Vendor{Products: []*Product{{ID: 1}, {ID: 2}}})
+				return err
+			},
+			expect: ErrLimitExceeded,
+		},
+	}
+
+	kinds := []error{ErrUnknownField, ErrUnsupportedType, ErrConversion, ErrSyntax, ErrLimitExceeded}
+	for _, testCase := range testCases {
+		err := testCase.fn()
+		if !assert.NotNil(t, err, testCase.description) {
+			continue
+		}
+		for _, kind := range kinds {
+			assert.EqualValues(t, kind == testCase.expect, errors.Is(err, kind), testCase.description+": "+kind.Error())
+		}
+		var syntaxErr *SyntaxError
+		if assert.EqualValues(t, testCase.expect == ErrSyntax, errors.As(err, &syntaxErr), testCase.description) && syntaxErr != nil {
+			assert.EqualValues(t, testCase.offset, syntaxErr.Offset, testCase.description)
+		}
+	}
+
+	var limitErr *LimitError
+	query, err := NewQuery("SELECT ID FROM `/Products`", reflect.TypeOf(&Vendor{}), nil, WithMaxRows(1))
+	assert.Nil(t, err)
+	_, err = query.Select(&Vendor{Products: []*Product{{ID: 1}, {ID: 2}}})
+	assert.True(t, errors.As(err, &limitErr))
+	assert.True(t, errors.Is(err, ErrMaxRowsExceeded))
+}
@@ -0,0 +1,105 @@
+// Package errs defines error kinds shared by structql packages, errors of a kind are matched with errors.Is
+// against the kind sentinel, and with errors.As against *Error or *SyntaxError
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// error kind sentinels
+var (
+	// ErrUnknownField is matched when a referenced field does not exist on the inspected type
+	ErrUnknownField = errors.New("unknown field")
+	// ErrUnsupportedType is matched when a source, target or field type is not supported
+	ErrUnsupportedType = errors.New("unsupported type")
+	// ErrConversion is matched when a value can not be converted or assigned to the target type
+	ErrConversion = errors.New("conversion failed")
+	// ErrSyntax is matched when a query, criteria or selector can not be parsed
+	ErrSyntax = errors.New("syntax error")
+	// ErrLimitExceeded is matched when a query resource cap is exceeded
+	ErrLimitExceeded = errors.New("limit exceeded")
+)
+
+var positionExpr = regexp.MustCompile(`pos: (\d+)`)
+
+type (
+	// Error represents an error of a kind, it wraps both the kind sentinel and the cause
+	Error struct {
+		Kind  error
+		cause error
+	}
+
+	// SyntaxError represents parsing error, offset is a byte position in the parsed text, -1 if unknown
+	SyntaxError struct {
+		Message string
+		Offset  int
+		cause   error
+	}
+)
+
+// Error returns error message
+func (e *Error) Error() string {
+	return e.cause.Error()
+}
+
+// Unwrap returns error kind and cause
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.cause}
+}
+
+// Error returns error message
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+// Is returns true for ErrSyntax
+func (e *SyntaxError) Is(target error) bool {
+	return target == ErrSyntax
+}
+
+// Unwrap returns cause
+func (e *SyntaxError) Unwrap() error {
+	return e.cause
+}
+
+// New returns an error of the kind, format supports %w verb to wrap a cause
+func New(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, cause: fmt.Errorf(format, args...)}
+}
+
+// UnknownField returns ErrUnknownField kind error
+func UnknownField(format string, args ...interface{}) error {
+	return New(ErrUnknownField, format, args...)
+}
+
+// UnsupportedType returns ErrUnsupportedType kind error
+func UnsupportedType(format string, args ...interface{}) error {
+	return New(ErrUnsupportedType, format, args...)
+}
+
+// Conversion returns ErrConversion kind error
+func Conversion(format string, args ...interface{}) error {
+	return New(ErrConversion, format, args...)
+}
+
+// Syntax returns syntax error wrapping parser error, the offset is taken from "pos: N" parser message,
+// errors already matching ErrSyntax are returned as is
+func Syntax(err error) error {
+	if err == nil || errors.Is(err, ErrSyntax) {
+		return err
+	}
+	ret := &SyntaxError{Message: err.Error(), Offset: -1, cause: err}
+	if match := positionExpr.FindStringSubmatch(ret.Message); len(match) > 1 {
+		ret.Offset, _ = strconv.Atoi(match[1])
+	}
+	return ret
+}
+
+// Syntaxf returns syntax error at the offset
+func Syntaxf(offset int, format string, args ...interface{}) error {
+	cause := fmt.Errorf(format, args...)
+	return &SyntaxError{Message: cause.Error(), Offset: offset, cause: errors.Unwrap(cause)}
+}
@@ -1,21 +1,28 @@
 package structql
 
 import (
-	"fmt"
+	"github.com/viant/structql/errs"
 	"github.com/viant/xunsafe"
 	"reflect"
 	"unsafe"
 )
 
 type field struct {
-	mapKind   mapKind
-	src       *xunsafe.Field
-	dest      *xunsafe.Field
-	aggregate bool
-	cp        func(src, dest unsafe.Pointer)
+	mapKind     mapKind
+	src         *xunsafe.Field
+	dest        *xunsafe.Field
+	aggregate   bool
+	cp          func(src, dest unsafe.Pointer)
+	compute     compute
+	aggregation *aggregation
+	window      *window
 }
 
 func (f *field) configure() error {
+	if f.compute != nil {
+		f.mapKind = mapKindExpr
+		return nil
+	}
 	if f.dest.Kind() == f.src.Kind() {
 		f.mapKind = mapKindDirect
 
@@ -137,7 +144,7 @@ func (f *field) computeCastedCopy() error {
 
 	}
 	if f.cp == nil {
-		return fmt.Errorf("unsupported structology field translation %s -> %s", f.src.Type.String(), f.dest.Type.String())
+		return errs.Conversion("unsupported structology field translation %s -> %s", f.src.Type.String(), f.dest.Type.String())
 	}
 	return nil
 }
@@ -0,0 +1,15 @@
+package structql
+
+import "github.com/viant/structql/parser/scalar"
+
+// RegisterFunc registers global scalar function callable in SELECT, WHERE and selector criteria i.e. CURRENCY(Amount, Code),
+// fn has to be a go func returning a value or a value and an error, variadic func is supported.
+// Function arguments are type checked when a query is compiled, error returned by the function stops the query.
+func RegisterFunc(name string, fn interface{}) error {
+	function, err := scalar.NewFunction(name, fn)
+	if err != nil {
+		return err
+	}
+	scalar.Register(function)
+	return nil
+}
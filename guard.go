@@ -0,0 +1,99 @@
+package structql
+
+import (
+	"context"
+	"fmt"
+)
+
+// resources limited by query caps
+const (
+	RowsResource  = "rows"
+	NodesResource = "nodes"
+	BytesResource = "bytes"
+)
+
+var (
+	// ErrMaxRowsExceeded is matched with errors.Is when produced rows exceed the cap
+	ErrMaxRowsExceeded = &LimitError{Resource: RowsResource}
+	// ErrMaxNodesExceeded is matched with errors.Is when visited source nodes exceed the cap
+	ErrMaxNodesExceeded = &LimitError{Resource: NodesResource}
+	// ErrMaxBytesExceeded is matched with errors.Is when result size exceeds the cap
+	ErrMaxBytesExceeded = &LimitError{Resource: BytesResource}
+)
+
+type (
+	// Limits represents query resource caps, zero value means no limit
+	Limits struct {
+		MaxRows  int //max produced rows
+		MaxNodes int //max visited source nodes, accounted across all walks of a single call
+		MaxBytes int //max approximate result size: record size with string and slice payloads
+	}
+
+	// LimitError represents exceeded query resource cap
+	LimitError struct {
+		Resource string
+		Limit    int
+	}
+
+	// guard enforces context cancellation and resource caps while walking the source
+	guard struct {
+		ctx    context.Context
+		done   <-chan struct{}
+		limits Limits
+		nodes  int
+		rows   int
+		bytes  int
+	}
+)
+
+// Error returns error message
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("max %v limit exceeded: %v", e.Resource, e.Limit)
+}
+
+// Is returns true if target is ErrLimitExceeded or a LimitError of the same resource
+func (e *LimitError) Is(target error) bool {
+	if target == ErrLimitExceeded {
+		return true
+	}
+	actual, ok := target.(*LimitError)
+	return ok && (actual.Resource == "" || actual.Resource == e.Resource)
+}
+
+// visit accounts visited node, it returns context error if context is done
+func (g *guard) visit() error {
+	if g.done != nil {
+		select {
+		case <-g.done:
+			return g.ctx.Err()
+		default:
+		}
+	}
+	g.nodes++
+	if g.limits.MaxNodes > 0 && g.nodes > g.limits.MaxNodes {
+		return &LimitError{Resource: NodesResource, Limit: g.limits.MaxNodes}
+	}
+	return nil
+}
+
+// row accounts produced row
+func (g *guard) row() error {
+	g.rows++
+	if g.limits.MaxRows > 0 && g.rows > g.limits.MaxRows {
+		return &LimitError{Resource: RowsResource, Limit: g.limits.MaxRows}
+	}
+	return nil
+}
+
+// result accounts produced result size
+func (g *guard) result(size int) error {
+	g.bytes += size
+	if g.bytes > g.limits.MaxBytes {
+		return &LimitError{Resource: BytesResource, Limit: g.limits.MaxBytes}
+	}
+	return nil
+}
+
+func newGuard(ctx context.Context, limits Limits) *guard {
+	return &guard{ctx: ctx, done: ctx.Done(), limits: limits}
+}
@@ -0,0 +1,340 @@
+package structql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/viant/structql/errs"
+	"github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+type (
+	// Index represents source slice column index, hash index supports = and IN,
+	// sorted index additionally supports <, <=, >, >= and BETWEEN constraints.
+	// Index is used by a query only while it is valid for the queried slice (the same slice data and length)
+	// and no UPDATE, DELETE or INSERT changed elements of the indexed type since it was built;
+	// other in place changes of indexed column values require explicit Rebuild.
+	Index struct {
+		column  string
+		sorted  bool
+		source  interface{}
+		xSlice  *xunsafe.Slice
+		field   *xunsafe.Field
+		keyType reflect.Type
+		mux     sync.RWMutex
+		valid   bool
+		data    uintptr
+		len     int
+		version *uint64 //indexed type mutation counter
+		built   uint64  //mutation counter value the index was built with
+		hash    map[interface{}][]int
+		keys    []interface{}
+		order   []int
+	}
+
+	// indexScan represents index usable with query constraint
+	indexScan struct {
+		index      *Index
+		constraint *parser.Constraint
+	}
+)
+
+// versions holds mutation counters by element struct type
+var versions sync.Map
+
+// typeVersion returns mutation counter of the element struct type
+func typeVersion(itemType reflect.Type) *uint64 {
+	if ret, ok := versions.Load(itemType); ok {
+		return ret.(*uint64)
+	}
+	ret, _ := versions.LoadOrStore(itemType, new(uint64))
+	return ret.(*uint64)
+}
+
+// mutated marks indexes over elements of the struct type stale
+func mutated(itemType reflect.Type) {
+	atomic.AddUint64(typeVersion(itemType), 1)
+}
+
+// NewIndex creates hash index over source slice (or pointer to slice) column
+func NewIndex(source interface{}, column string) (*Index, error) {
+	return newIndex(source, column, false)
+}
+
+// NewSortedIndex creates sorted index over source slice (or pointer to slice) column, it also supports range constraints
+func NewSortedIndex(source interface{}, column string) (*Index, error) {
+	return newIndex(source, column, true)
+}
+
+func newIndex(source interface{}, column string, sorted bool) (*Index, error) {
+	sliceType := reflect.TypeOf(source)
+	if sliceType != nil && sliceType.Kind() == reflect.Ptr {
+		sliceType = sliceType.Elem()
+	}
+	if sliceType == nil || sliceType.Kind() != reflect.Slice {
+		return nil, errs.UnsupportedType("invalid index source: %T, expected slice", source)
+	}
+	itemType := unwrapStruct(sliceType.Elem())
+	if itemType == nil {
+		return nil, errs.UnsupportedType("invalid index source: %s, expected slice of struct", sliceType.String())
+	}
+	aField := xunsafe.FieldByName(itemType, column)
+	if aField == nil {
+		return nil, errs.UnknownField("failed to lookup index field: '%s' at %s", column, itemType.String())
+	}
+	keyType := aField.Type
+	if keyType.Kind() == reflect.Ptr {
+		keyType = keyType.Elem()
+	}
+	if !keyType.Comparable() {
+		return nil, errs.UnsupportedType("unsupported index field type: '%s' %s", column, aField.Type.String())
+	}
+	if sorted {
+		switch scalar.KindOf(keyType) {
+		case scalar.Int, scalar.Float, scalar.String, scalar.Time:
+		default:
+			return nil, errs.UnsupportedType("unsupported sorted index field type: '%s' %s", column, aField.Type.String())
+		}
+	}
+	ret := &Index{column: aField.Name, sorted: sorted, source: source, xSlice: xunsafe.NewSlice(sliceType), field: aField, keyType: keyType, version: typeVersion(itemType)}
+	return ret, ret.Rebuild()
+}
+
+// Column returns indexed column
+func (i *Index) Column() string {
+	return i.column
+}
+
+// Valid returns true if index was built and has not been invalidated or changed by UPDATE, DELETE or INSERT
+func (i *Index) Valid() bool {
+	i.mux.RLock()
+	defer i.mux.RUnlock()
+	return i.valid && atomic.LoadUint64(i.version) == i.built
+}
+
+// Invalidate marks index invalid, queries do not use invalid index until it is rebuilt
+func (i *Index) Invalidate() {
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	i.valid = false
+}
+
+// Rebuild rebuilds index from the current source slice
+func (i *Index) Rebuild() error {
+	slicePtr := xunsafe.AsPointer(i.source)
+	if slicePtr == nil {
+		return fmt.Errorf("invalid index source: nil")
+	}
+	i.mux.Lock()
+	defer i.mux.Unlock()
+	i.built = atomic.LoadUint64(i.version)
+	i.data, i.len = sliceData(slicePtr), i.xSlice.Len(slicePtr)
+	i.hash, i.keys, i.order = nil, nil, nil
+	if !i.sorted {
+		i.hash = make(map[interface{}][]int, i.len)
+	}
+	for index := 0; index < i.len; index++ {
+		itemPtr := xunsafe.AsPointer(i.xSlice.ValuePointerAt(slicePtr, index))
+		if itemPtr == nil {
+			continue
+		}
+		key := fieldValue(i.field, itemPtr)
+		if key == nil {
+			continue
+		}
+		if i.sorted {
+			i.keys = append(i.keys, key)
+			i.order = append(i.order, index)
+			continue
+		}
+		i.hash[key] = append(i.hash[key], index)
+	}
+	if i.sorted {
+		sort.Stable(&sortedKeys{index: i})
+	}
+	i.valid = true
+	return nil
+}
+
+// supports returns true if index supports constraint operator
+func (i *Index) supports(op string) bool {
+	switch op {
+	case "=", "IN":
+		return true
+	}
+	return i.sorted
+}
+
+// positions returns ascending item positions matching constraint, it returns false if index can not be used for the slice
+func (i *Index) positions(slicePtr unsafe.Pointer, constraint *parser.Constraint) ([]int, bool) {
+	i.mux.RLock()
+	defer i.mux.RUnlock()
+	if !i.valid || atomic.LoadUint64(i.version) != i.built || sliceData(slicePtr) != i.data || i.xSlice.Len(slicePtr) != i.len {
+		return nil, false
+	}
+	keys := make([]interface{}, len(constraint.Values))
+	for k, value := range constraint.Values {
+		key, ok := i.key(value)
+		if !ok {
+			return nil, false
+		}
+		keys[k] = key
+	}
+	switch constraint.Op {
+	case "=", "IN":
+		if len(keys) == 1 && !i.sorted {
+			return i.hash[keys[0]], true
+		}
+		var ret []int
+		for _, key := range keys {
+			if i.sorted {
+				ret = append(ret, i.order[i.lowerBound(key, false):i.lowerBound(key, true)]...)
+				continue
+			}
+			ret = append(ret, i.hash[key]...)
+		}
+		return sortedPositions(ret), true
+	}
+	from, to := 0, len(i.keys)
+	switch constraint.Op {
+	case ">":
+		from = i.lowerBound(keys[0], true)
+	case ">=":
+		from = i.lowerBound(keys[0], false)
+	case "<":
+		to = i.lowerBound(keys[0], false)
+	case "<=":
+		to = i.lowerBound(keys[0], true)
+	case "BETWEEN":
+		from, to = i.lowerBound(keys[0], false), i.lowerBound(keys[1], true)
+	}
+	if from >= to {
+		return nil, true
+	}
+	return sortedPositions(append([]int{}, i.order[from:to]...)), true
+}
+
+// sliceData returns address of the slice first element
+func sliceData(slicePtr unsafe.Pointer) uintptr {
+	return uintptr(unsafe.Pointer(unsafe.SliceData(*(*[]byte)(slicePtr)))) //slice header layout does not depend on element type
+}
+
+// lowerBound returns position of the first sorted key greater or equal (greater if after is true) than supplied key
+func (i *Index) lowerBound(key interface{}, after bool) int {
+	return sort.Search(len(i.keys), func(k int) bool {
+		if after {
+			return compareValues(i.keys[k], key) > 0
+		}
+		return compareValues(i.keys[k], key) >= 0
+	})
+}
+
+// key returns constraint value converted to index key type
+func (i *Index) key(value interface{}) (interface{}, bool) {
+	rValue := reflect.ValueOf(value)
+	if rValue.Kind() == reflect.Ptr {
+		if rValue.IsNil() {
+			return nil, false
+		}
+		rValue = rValue.Elem()
+	}
+	if rValue.Type() == i.keyType {
+		return rValue.Interface(), true
+	}
+	valueKind, keyKind := scalar.KindOf(rValue.Type()), scalar.KindOf(i.keyType)
+	if valueKind != keyKind && !(valueKind == scalar.Int && keyKind == scalar.Float) {
+		return nil, false
+	}
+	if !rValue.CanConvert(i.keyType) {
+		return nil, false
+	}
+	ret := rValue.Convert(i.keyType)
+	if valueKind == scalar.Int && ret.Convert(rValue.Type()).Interface() != rValue.Interface() { //value overflows key type
+		return nil, false
+	}
+	return ret.Interface(), true
+}
+
+// sortedPositions returns ascending unique positions
+func sortedPositions(positions []int) []int {
+	sort.Ints(positions)
+	ret := positions[:0]
+	for k, position := range positions {
+		if k == 0 || position != positions[k-1] {
+			ret = append(ret, position)
+		}
+	}
+	return ret
+}
+
+// sortedKeys sorts index keys with item positions
+type sortedKeys struct {
+	index *Index
+}
+
+func (s *sortedKeys) Len() int {
+	return len(s.index.keys)
+}
+
+func (s *sortedKeys) Less(i, j int) bool {
+	return compareValues(s.index.keys[i], s.index.keys[j]) < 0
+}
+
+func (s *sortedKeys) Swap(i, j int) {
+	s.index.keys[i], s.index.keys[j] = s.index.keys[j], s.index.keys[i]
+	s.index.order[i], s.index.order[j] = s.index.order[j], s.index.order[i]
+}
+
+// indexed returns item positions of the most selective usable index, it returns false if no index can be used
+func (n *Node) indexed(slicePtr unsafe.Pointer) ([]int, bool) {
+	var ret []int
+	found := false
+	for _, scan := range n.indexes {
+		positions, ok := scan.index.positions(slicePtr, scan.constraint)
+		if !ok {
+			continue
+		}
+		if !found || len(positions) < len(ret) {
+			ret, found = positions, true
+		}
+	}
+	return ret, found
+}
+
+// positions returns array node item range and a function returning slice item index at range position,
+// positions of the most selective usable index are used instead of the node bounds when available
+func (n *Node) positions(slicePtr unsafe.Pointer) (int, int, func(int) int) {
+	if positions, ok := n.indexed(slicePtr); ok {
+		return 0, len(positions), func(i int) int { return positions[i] }
+	}
+	from, to := n.bounds(n.xSlice.Len(slicePtr))
+	return from, to, func(i int) int { return i }
+}
+
+// setIndexes assigns indexes usable with constraints to the array node holding leaf items
+func (n *Node) setIndexes(indexes []*Index, constraints []*parser.Constraint) {
+	aNode := n
+	for aNode.child != nil && !(aNode.kind == nodeKindArray && aNode.child.IsLeaf) {
+		aNode = aNode.child
+	}
+	if aNode.kind != nodeKindArray || aNode.position != nil {
+		return
+	}
+	for _, index := range indexes {
+		if index.xSlice.Type != aNode.xSlice.Type {
+			continue
+		}
+		for _, constraint := range constraints {
+			if strings.EqualFold(constraint.Field, index.column) && index.supports(constraint.Op) {
+				aNode.indexes = append(aNode.indexes, &indexScan{index: index, constraint: constraint})
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package structql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"reflect"
+	"testing"
+)
+
+func TestNewIndex(t *testing.T) {
+	type Region string
+	type Record struct {
+		ID     int32
+		Region Region
+		Score  *float64
+	}
+	score := func(v float64) *float64 { return &v }
+	records := []*Record{
+		{ID: 3, Region: "EU", Score: score(1.5)},
+		{ID: 1, Region: "US"},
+		{ID: 2, Region: "EU", Score: score(0.5)},
+		{ID: 5, Region: "APAC", Score: score(3)},
+		{ID: 4, Region: "US", Score: score(2)},
+	}
+	idIndex, err := NewIndex(records, "ID")
+	assert.Nil(t, err)
+	regionIndex, err := NewIndex(&records, "Region")
+	assert.Nil(t, err)
+	scoreIndex, err := NewSortedIndex(records, "Score")
+	assert.Nil(t, err)
+
+	var testCases = []struct {
+		description string
+		query       string
+		values      []interface{}
+		expect      string
+		expectProbe int //evaluated items
+	}{
+		{
+			description: "hash equality",
+			query:       "SELECT ID FROM `/` WHERE PROBE(ID) = 1 AND ID = ?",
+			values:      []interface{}{2},
+			expect:      `[{"ID":2}]`,
+			expectProbe: 1,
+		},
+		{
+			description: "hash in with other conjuncts",
+			query:       "SELECT ID FROM `/` WHERE PROBE(ID) = 1 AND ID > ? AND Region IN ('EU', ?, 'EU')",
+			values:      []interface{}{2, "APAC"},
+			expect:      `[{"ID":3},{"ID":5}]`,
+			expectProbe: 3,
+		},
+		{
+			description: "most selective index",
+			query:       "SELECT ID FROM `/` WHERE PROBE(ID) = 1 AND Region = 'US' AND 4 = ID",
+			expect:      `[{"ID":4}]`,
+			expectProbe: 1,
+		},
+		{
+			description: "sorted range",
+			query:       "SELECT ID FROM `/` WHERE PROBE(ID) = 1 AND Score BETWEEN ? AND 2",
+			values:      []interface{}{1},
+			expect:      `[{"ID":3},{"ID":4}]`,
+			expectProbe: 2,
+		},
+		{
+			description: "sorted open range",
+			query:       "SELECT ID FROM `/` WHERE PROBE(ID) = 1 AND Score > 1.5",
+			expect:      `[{"ID":5},{"ID":4}]`,
+			expectProbe: 2,
+		},
+		{
+			description: "no usable constraint",
+			query:       "SELECT ID FROM `/` WHERE PROBE(ID) = 0 OR ID = 1",
+			expect:      `[{"ID":1}]`,
+			expectProbe: 5,
+		},
+		{
+			description: "unconvertible value",
+			query:       "SELECT ID FROM `/` WHERE PROBE(ID) = 1 AND ID = 1.5",
+			expect:      `[]`,
+			expectProbe: 5,
+		},
+	}
+	for _, evaluator := range []Evaluator{IgoEvaluator, NativeEvaluator} {
+		for _, testCase := range testCases {
+			description := testCase.description + " (" + string(evaluator) + ")"
+			probe := 0
+			options := []interface{}{WithEvaluator(evaluator), WithIndex(idIndex, regionIndex, scoreIndex), WithFunc("PROBE", func(id int) int {
+				probe++
+				return 1
+			})}
+			query, err := NewQuery(testCase.query, reflect.TypeOf(records), nil, append(testCase.values, options...)...)
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			result, err := query.Select(records)
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			assertly.AssertValues(t, testCase.expect, result, description)
+			assert.EqualValues(t, 2*testCase.expectProbe, probe, description) //items are visited by count and map walks
+		}
+	}
+
+	probe := 0
+	query, err := NewQuery("SELECT ID FROM `/` WHERE PROBE(ID) = 1 AND Region = 'EU'", reflect.TypeOf(records), nil, WithIndex(regionIndex), WithFunc("PROBE", func(id int) int {
+		probe++
+		return 1
+	}))
+	assert.Nil(t, err)
+	records[0].Region = "US"
+	regionIndex.Invalidate()
+	assert.False(t, regionIndex.Valid())
+	result, _ := query.Select(records)
+	assertly.AssertValues(t, `[{"ID":2}]`, result, "invalidated index")
+	assert.EqualValues(t, 10, probe, "invalidated index")
+
+	assert.Nil(t, regionIndex.Rebuild())
+	probe = 0
+	result, _ = query.Select(records)
+	assertly.AssertValues(t, `[{"ID":2}]`, result, "rebuilt index")
+	assert.EqualValues(t, 2, probe, "rebuilt index")
+
+	records = append(records, &Record{ID: 6, Region: "EU"})
+	probe = 0
+	result, _ = query.Select(records)
+	assertly.AssertValues(t, `[{"ID":2},{"ID":6}]`, result, "stale index")
+	assert.EqualValues(t, 12, probe, "stale index")
+
+	assert.Nil(t, regionIndex.Rebuild())
+	update, err := NewUpdate("UPDATE `/` SET Region = 'EU' WHERE ID = 1", reflect.TypeOf(records))
+	assert.Nil(t, err)
+	_, err = update.Exec(records)
+	assert.Nil(t, err)
+	assert.False(t, regionIndex.Valid(), "updated index")
+	probe = 0
+	result, _ = query.Select(records)
+	assertly.AssertValues(t, `[{"ID":1},{"ID":2},{"ID":6}]`, result, "updated index")
+	assert.EqualValues(t, 12, probe, "updated index")
+
+	assert.Nil(t, regionIndex.Rebuild())
+	assert.True(t, regionIndex.Valid(), "rebuilt updated index")
+	deleteStmt, err := NewDelete("DELETE FROM `/` WHERE ID = 6", reflect.TypeOf(records))
+	assert.Nil(t, err)
+	_, err = deleteStmt.Exec(&records)
+	assert.Nil(t, err)
+	assert.False(t, regionIndex.Valid(), "deleted index")
+	probe = 0
+	result, _ = query.Select(records)
+	assertly.AssertValues(t, `[{"ID":1},{"ID":2}]`, result, "deleted index")
+	assert.EqualValues(t, 10, probe, "deleted index")
+
+	_, err = NewIndex(records, "Name")
+	assert.NotNil(t, err)
+	_, err = NewIndex(records[0], "ID")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,268 @@
+package structql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/viant/structql/errs"
+	node "github.com/viant/structql/node"
+	sparser "github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+// Insert represents INSERT statement appending new elements to the slices selected by the target path
+type Insert struct {
+	query    string
+	node     *Node
+	itemType reflect.Type
+	rows     [][]*assignment
+	selector *Query
+	fields   []*field
+	Binding  *node.Binding
+	limits   Limits
+}
+
+// Exec appends inserted elements to every matched target slice, it returns number of inserted elements,
+// source has to be a pointer, or a slice when target slices are nested in pointer reachable objects.
+// INSERT ... SELECT query runs once against the source before any element is inserted.
+func (i *Insert) Exec(source interface{}) (int, error) {
+	return i.ExecContext(context.Background(), source)
+}
+
+// ExecContext appends inserted elements to every matched target slice, the walk is stopped with context error
+// when context is done or with LimitError when query resource cap is exceeded
+func (i *Insert) ExecContext(ctx context.Context, source interface{}) (affected int, err error) {
+	defer scalar.Recover(&err)
+	if i.node.kind == nodeKindArray && reflect.TypeOf(source).Kind() != reflect.Ptr {
+		return 0, errs.UnsupportedType("invalid source: %T, expected pointer to slice", source)
+	}
+	if err = checkMutableSource(source); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if affected > 0 { //indexes built over changed elements are stale
+			mutated(i.itemType)
+		}
+	}()
+	var selected unsafe.Pointer
+	var rows [][]reflect.Value
+	if i.selector != nil {
+		if _, selected, err = i.selector.selectContext(ctx, source); err != nil {
+			return 0, err
+		}
+	} else if rows, err = i.rowValues(); err != nil {
+		return 0, err
+	}
+	var targets []*Node
+	var slices []unsafe.Pointer
+	err = visitSlices(newGuard(ctx, i.limits), i.node, source, func(aNode *Node, slicePtr unsafe.Pointer) error {
+		targets = append(targets, aNode)
+		slices = append(slices, slicePtr)
+		return nil
+	})
+	if err != nil { //no element is inserted unless all target slices were matched
+		return 0, err
+	}
+	for t, aNode := range targets {
+		appender := aNode.xSlice.Appender(slices[t])
+		if i.selector == nil {
+			for r, row := range i.rows {
+				itemPtr := i.newItem(appender)
+				for k, assign := range row {
+					assign.assign(itemPtr, rows[r][k])
+				}
+				affected++
+			}
+			continue
+		}
+		for k := 0; k < i.selector.destSlice.Len(selected); k++ {
+			srcPtr := xunsafe.AsPointer(i.selector.destSlice.ValuePointerAt(selected, k))
+			destPtr := i.newItem(appender)
+			for _, aField := range i.fields {
+				aField.Map(srcPtr, destPtr)
+			}
+			affected++
+		}
+	}
+	return affected, nil
+}
+
+// rowValues computes VALUES rows converted to the inserted columns types
+func (i *Insert) rowValues() ([][]reflect.Value, error) {
+	ret := make([][]reflect.Value, len(i.rows))
+	for r, row := range i.rows {
+		ret[r] = make([]reflect.Value, len(row))
+		for k, assign := range row {
+			value, err := assign.value(nil)
+			if err != nil {
+				return nil, err
+			}
+			ret[r][k] = value
+		}
+	}
+	return ret, nil
+}
+
+// newItem appends zero element, it returns element pointer
+func (i *Insert) newItem(appender *xunsafe.Appender) unsafe.Pointer {
+	itemPtr := xunsafe.AsPointer(appender.Add())
+	reflect.NewAt(i.itemType, itemPtr).Elem().Set(reflect.Zero(i.itemType)) //reused slice capacity can hold stale data
+	return itemPtr
+}
+
+// visitSlices walks the node calling visitor with matched slices holding leaf items
+func visitSlices(aGuard *guard, aNode *Node, value interface{}, visitor func(aNode *Node, slicePtr unsafe.Pointer) error) error {
+	if err := aGuard.visit(); err != nil {
+		return err
+	}
+	if !aNode.When(value) {
+		return nil
+	}
+	ptr := xunsafe.AsPointer(value)
+	if ptr == nil {
+		return nil
+	}
+	switch aNode.kind {
+	case nodeKindObject:
+		if aNode.child == nil {
+			return nil
+		}
+		return visitSlices(aGuard, aNode.child, aNode.fieldItem(ptr), visitor)
+	case nodeKindArray:
+		if aNode.child.IsLeaf {
+			return visitor(aNode, ptr)
+		}
+		from, to := aNode.bounds(aNode.xSlice.Len(ptr))
+		for k := from; k < to; k++ {
+			if err := visitSlices(aGuard, aNode.child, aNode.xSlice.ValuePointerAt(ptr, k), visitor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewInsert returns INSERT statement for the source type, i.e.
+// INSERT INTO `/Vendors[ID=?]/Products` (ID, Name, Status) VALUES (?, ?, ?)
+// or INSERT INTO `/Archive` (ID, Name) SELECT ID, Name FROM `/Vendors/Products` WHERE Status = 0.
+// VALUES use update conversion rules, SELECT columns are copied with the mapper field conversion rules.
+func NewInsert(SQL string, source reflect.Type, values ...interface{}) (*Insert, error) {
+	if unwrapStruct(source) == nil {
+		return nil, errs.UnsupportedType("invalid source type: %s", source.String())
+	}
+	opts, params, err := newOptions(values)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Insert{query: SQL, Binding: &node.Binding{}, limits: opts.limits}
+	value := &node.Values{Values: params, Bindings: ret.Binding, Functions: opts.functions}
+	stmt, err := sparser.ParseInsert(SQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %w, %v", err, SQL)
+	}
+	sel, err := sparser.ParseSelector(stmt.Target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target: %w, %v", err, stmt.Target)
+	}
+	if ret.node, err = newNode(source, sel, value, opts.evaluator); err != nil {
+		return nil, err
+	}
+	owner := ret.node
+	for owner.child != nil && !owner.child.IsLeaf {
+		owner = owner.child
+	}
+	if ret.itemType = unwrapStruct(owner.LeafType()); owner.kind != nodeKindArray || ret.itemType == nil {
+		return nil, errs.UnsupportedType("invalid insert target: %s, expected slice of struct", ret.node.LeafType().String())
+	}
+	if owner.child.hasCriteria() {
+		return nil, fmt.Errorf("invalid insert target: %v, inserted elements can not have criteria", stmt.Target)
+	}
+	columns, err := insertColumns(ret.itemType, stmt.Columns)
+	if err != nil {
+		return nil, err
+	}
+	if stmt.Query != "" {
+		if err = ret.compileSelect(source, stmt.Query, columns, stmt.Columns, value, opts); err != nil {
+			return nil, err
+		}
+		if err = value.Validate(); err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+	lookup := func(name string) *xunsafe.Field { return nil }
+	for _, row := range stmt.Rows {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("invalid insert values count: %v, expected: %v", len(row), len(columns))
+		}
+		var assignments []*assignment
+		for k, item := range row {
+			expression, valueType, err := sparser.AsExpression(item, lookup, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid insert column '%s' value: %w", columns[k].Name, err)
+			}
+			if err = checkAssignable(valueType, columns[k]); err != nil {
+				return nil, err
+			}
+			assignments = append(assignments, &assignment{field: field{dest: columns[k]}, expression: expression})
+		}
+		ret.rows = append(ret.rows, assignments)
+	}
+	if err = value.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// compileSelect compiles INSERT ... SELECT query, query columns are mapped to insert columns by position,
+// or by name when insert columns are not specified; query placeholders follow the target ones
+func (i *Insert) compileSelect(source reflect.Type, SQL string, columns []*xunsafe.Field, names []string, value *node.Values, opts *options) error {
+	var err error
+	if i.selector, err = newQuery(SQL, source, nil, value, opts); err != nil {
+		return err
+	}
+	selected := i.selector.StructType()
+	if len(names) > 0 && selected.NumField() != len(columns) {
+		return fmt.Errorf("invalid insert select columns count: %v, expected: %v", selected.NumField(), len(columns))
+	}
+	for k := 0; k < selected.NumField(); k++ {
+		src := xunsafe.NewField(selected.Field(k))
+		dest := xunsafe.FieldByName(i.itemType, src.Name)
+		if len(names) > 0 {
+			dest = columns[k]
+		}
+		if dest == nil {
+			return errs.UnknownField("failed to lookup insert field: '%s' at %s", src.Name, i.itemType.String())
+		}
+		aField := &field{src: src, dest: dest}
+		if err = aField.configure(); err != nil {
+			return fmt.Errorf("invalid insert column '%s': %w", dest.Name, err)
+		}
+		i.fields = append(i.fields, aField)
+	}
+	return nil
+}
+
+// insertColumns returns insert column fields, all exported fields are used when columns are not specified
+func insertColumns(itemType reflect.Type, names []string) ([]*xunsafe.Field, error) {
+	var ret []*xunsafe.Field
+	if len(names) == 0 {
+		for k := 0; k < itemType.NumField(); k++ {
+			if itemType.Field(k).IsExported() {
+				ret = append(ret, xunsafe.NewField(itemType.Field(k)))
+			}
+		}
+		return ret, nil
+	}
+	for _, name := range names {
+		aField := xunsafe.FieldByName(itemType, name)
+		if aField == nil {
+			return nil, errs.UnknownField("failed to lookup insert field: '%s' at %s", name, itemType.String())
+		}
+		ret = append(ret, aField)
+	}
+	return ret, nil
+}
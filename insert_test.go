@@ -0,0 +1,197 @@
+package structql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"reflect"
+	"testing"
+)
+
+func TestNewInsert(t *testing.T) {
+	type Product struct {
+		ID     int
+		Name   string
+		Status *int
+		Price  float32
+	}
+	type Vendor struct {
+		ID       int
+		Products []Product
+		Featured []*Product
+	}
+	type Summary struct {
+		ID   int64
+		Name string
+	}
+	type Catalog struct {
+		Vendors  []*Vendor
+		Archive  []*Summary
+		Statuses []int
+	}
+	status := 1
+	newCatalog := func() *Catalog {
+		return &Catalog{Vendors: []*Vendor{
+			{ID: 1, Products: []Product{{ID: 1, Name: "a", Status: &status}}},
+			{ID: 2, Products: []Product{{ID: 2, Name: "b"}, {ID: 3, Name: "c", Status: &status}}},
+		}}
+	}
+
+	var testCases = []struct {
+		description    string
+		query          string
+		values         []interface{}
+		expectAffected int
+		expect         string
+		hasError       bool
+		hasExecError   bool
+	}{
+		{
+			description:    "values with selected owner",
+			query:          "INSERT INTO `/Vendors[ID=?]/Products` (ID, Name, Status) VALUES (?, ?, ?)",
+			values:         []interface{}{2, 10, "x", int64(2)},
+			expectAffected: 1,
+			expect:         `{"Vendors":[{"ID":1,"Products":[{"ID":1}]},{"ID":2,"Products":[{"ID":2},{"ID":3},{"ID":10,"Name":"x","Status":2}]}]}`,
+		},
+		{
+			description:    "multiple rows into each owner",
+			query:          "INSERT INTO `/Vendors/Featured` (ID, Name, Price) VALUES (1 + 1, UPPER('x, y'), 1.5), (3, CONCAT('a', ?), NULL)",
+			values:         []interface{}{"b"},
+			expectAffected: 4,
+			expect:         `{"Vendors":[{"ID":1,"Featured":[{"ID":2,"Name":"X, Y","Price":1.5},{"ID":3,"Name":"ab","Price":0}]},{"ID":2,"Featured":[{"ID":2},{"ID":3}]}]}`,
+		},
+		{
+			description:    "all columns",
+			query:          "INSERT INTO `/Vendors[ID=1]/Products` VALUES (5, 'e', NULL, 2)",
+			expectAffected: 1,
+			expect:         `{"Vendors":[{"ID":1,"Products":[{"ID":1},{"ID":5,"Name":"e","Price":2}]},{"ID":2,"Products":[{"ID":2},{"ID":3}]}]}`,
+		},
+		{
+			description:    "select by name",
+			query:          "INSERT INTO `/Archive` SELECT ID, Name FROM `/Vendors/Products` WHERE ID > ?",
+			values:         []interface{}{1},
+			expectAffected: 2,
+			expect:         `{"Archive":[{"ID":2,"Name":"b"},{"ID":3,"Name":"c"}]}`,
+		},
+		{
+			description:    "select by position into selected owner",
+			query:          "INSERT INTO `/Vendors[ID=?]/Products` (Name, Status) SELECT UPPER(Name) AS X, ID AS Z FROM `/Vendors/Products` WHERE Status IS NULL",
+			values:         []interface{}{1},
+			expectAffected: 1,
+			expect:         `{"Vendors":[{"ID":1,"Products":[{"ID":1},{"ID":0,"Name":"B","Status":2}]},{"ID":2,"Products":[{"ID":2},{"ID":3}]}]}`,
+		},
+		{
+			description:    "select with named parameters in target and query",
+			query:          "INSERT INTO `/Vendors[ID=@vendor]/Products` (Name, Status) SELECT UPPER(Name) AS X, ID AS Z FROM `/Vendors/Products` WHERE ID > :min",
+			values:         []interface{}{map[string]interface{}{"vendor": 1, "min": 2}},
+			expectAffected: 1,
+			expect:         `{"Vendors":[{"ID":1,"Products":[{"ID":1},{"ID":0,"Name":"C","Status":3}]},{"ID":2,"Products":[{"ID":2},{"ID":3}]}]}`,
+		},
+		{
+			description: "select with extra positional value",
+			query:       "INSERT INTO `/Archive` SELECT ID, Name FROM `/Vendors/Products` WHERE ID > ?",
+			values:      []interface{}{1, 2},
+			hasError:    true,
+		},
+		{
+			description: "select with unused named parameter",
+			query:       "INSERT INTO `/Vendors[ID=@vendor]/Products` (Name) SELECT Name FROM `/Vendors/Products`",
+			values:      []interface{}{map[string]interface{}{"vendor": 1, "min": 2}},
+			hasError:    true,
+		},
+		{
+			description: "unknown column",
+			query:       "INSERT INTO `/Vendors/Products` (ID, Code) VALUES (1, 2)",
+			hasError:    true,
+		},
+		{
+			description: "values count mismatch",
+			query:       "INSERT INTO `/Vendors/Products` (ID, Name) VALUES (1)",
+			hasError:    true,
+		},
+		{
+			description: "field reference in values",
+			query:       "INSERT INTO `/Vendors/Products` (ID) VALUES (ID)",
+			hasError:    true,
+		},
+		{
+			description: "non slice target",
+			query:       "INSERT INTO `/Vendors[0]/ID` VALUES (1)",
+			hasError:    true,
+		},
+		{
+			description: "non struct target",
+			query:       "INSERT INTO `/Statuses` VALUES (1)",
+			hasError:    true,
+		},
+		{
+			description: "unsupported select conversion",
+			query:       "INSERT INTO `/Archive` (Name) SELECT ID FROM `/Vendors/Products`",
+			hasError:    true,
+		},
+		{
+			description: "invalid syntax",
+			query:       "INSERT INTO `/Archive` (ID) VALUES (1",
+			hasError:    true,
+		},
+		{
+			description: "incompatible value",
+			query:       "INSERT INTO `/Archive` (ID) VALUES ('x')",
+			hasError:    true,
+		},
+		{
+			description: "truncated float value",
+			query:       "INSERT INTO `/Archive` (ID) VALUES (1.5)",
+			hasError:    true,
+		},
+		{
+			description:  "overflowing value leaves data unchanged",
+			query:        "INSERT INTO `/Vendors/Featured` (ID, Price) VALUES (1, 1.5), (2, ?)",
+			values:       []interface{}{1e40},
+			hasExecError: true,
+		},
+		{
+			description:  "exceeded limit leaves data unchanged",
+			query:        "INSERT INTO `/Vendors/Featured` (ID) VALUES (1)",
+			values:       []interface{}{WithMaxNodes(3)},
+			hasExecError: true,
+		},
+	}
+
+	for _, evaluator := range []Evaluator{IgoEvaluator, NativeEvaluator} {
+		for _, testCase := range testCases {
+			description := testCase.description + " (" + string(evaluator) + ")"
+			insert, err := NewInsert(testCase.query, reflect.TypeOf(&Catalog{}), append(testCase.values, WithEvaluator(evaluator))...)
+			if testCase.hasError {
+				assert.NotNil(t, err, description)
+				continue
+			}
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			catalog := newCatalog()
+			affected, err := insert.Exec(catalog)
+			if testCase.hasExecError {
+				assert.NotNil(t, err, description)
+				assert.EqualValues(t, newCatalog(), catalog, description)
+				continue
+			}
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			assert.EqualValues(t, testCase.expectAffected, affected, description)
+			assertly.AssertValues(t, testCase.expect, catalog, description)
+		}
+	}
+
+	products := make([]Product, 1, 3)
+	stale := products[:2]
+	stale[1] = Product{ID: 9, Name: "stale"}
+	insert, err := NewInsert("INSERT INTO `/` (ID) VALUES (2)", reflect.TypeOf(products))
+	assert.Nil(t, err)
+	_, err = insert.Exec(products)
+	assert.NotNil(t, err, "non pointer root slice")
+	affected, err := insert.Exec(&products)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, affected)
+	assert.EqualValues(t, []Product{{}, {ID: 2}}, products)
+}
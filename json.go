@@ -0,0 +1,275 @@
+package structql
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/viant/structql/naming"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+type (
+	// JSONOption represents JSON encoding option
+	JSONOption func(o *jsonOptions)
+
+	jsonOptions struct {
+		naming    naming.Case
+		ndjson    bool
+		omitEmpty bool
+	}
+
+	// jsonEncoder encodes dest rows as JSON objects
+	jsonEncoder struct {
+		fields  []*jsonField
+		options *jsonOptions
+		buffer  []byte
+	}
+
+	jsonField struct {
+		xField    *xunsafe.Field
+		name      []byte //encoded name with colon
+		nullable  bool
+		native    bool
+		omitEmpty bool //json tag omitempty option
+		quoted    bool //json tag string option
+	}
+)
+
+// WithJSONNaming returns option formatting JSON field names with the case, field alias is used as written by default
+func WithJSONNaming(aCase naming.Case) JSONOption {
+	return func(o *jsonOptions) {
+		o.naming = aCase
+	}
+}
+
+// WithNDJSON returns option encoding rows as newline delimited JSON instead of JSON array
+func WithNDJSON() JSONOption {
+	return func(o *jsonOptions) {
+		o.ndjson = true
+	}
+}
+
+// WithOmitEmpty returns option omitting nil pointer, slice, map and interface fields
+func WithOmitEmpty() JSONOption {
+	return func(o *jsonOptions) {
+		o.omitEmpty = true
+	}
+}
+
+// EncodeJSON writes query result rows as JSON array or NDJSON, see EncodeJSONContext
+func (s *Query) EncodeJSON(w io.Writer, source interface{}, options ...JSONOption) error {
+	return s.EncodeJSONContext(context.Background(), w, source, options...)
+}
+
+// EncodeJSONContext writes query result rows as JSON array or NDJSON, rows are mapped into a reused dest value
+// and written while walking the source, aggregate and window function queries are written once all source items are mapped,
+// on error partially written output is not rolled back
+func (s *Query) EncodeJSONContext(ctx context.Context, w io.Writer, source interface{}, options ...JSONOption) (err error) {
+	defer scalar.Recover(&err)
+	opts := &jsonOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	destType := s.mapper.dest
+	encoder := newJSONEncoder(destType, opts)
+	writer := bufio.NewWriter(w)
+	rows := 0
+	write := func(destPtr unsafe.Pointer) error {
+		if !opts.ndjson {
+			delimiter := byte(',')
+			if rows == 0 {
+				delimiter = '['
+			}
+			if err := writer.WriteByte(delimiter); err != nil {
+				return err
+			}
+		}
+		rows++
+		data, err := encoder.encode(destPtr)
+		if err != nil {
+			return err
+		}
+		if _, err = writer.Write(data); err != nil {
+			return err
+		}
+		if opts.ndjson {
+			return writer.WriteByte('\n')
+		}
+		return nil
+	}
+	if s.mapper.aggregate || len(s.mapper.windows) > 0 {
+		_, destPtr, err := s.selectContext(ctx, source)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < s.destSlice.Len(destPtr); i++ {
+			if err = write(xunsafe.AsPointer(s.destSlice.ValuePointerAt(destPtr, i))); err != nil {
+				return err
+			}
+		}
+	} else {
+		aGuard := newGuard(ctx, s.limits)
+		dest := reflect.New(destType)
+		destPtr := xunsafe.AsPointer(dest.Interface())
+		zero := reflect.Zero(destType)
+		visitor := func(value interface{}) error {
+			srcPtr := xunsafe.AsPointer(value)
+			if srcPtr == nil {
+				return nil
+			}
+			if err := aGuard.row(); err != nil {
+				return err
+			}
+			dest.Elem().Set(zero)
+			if err := s.mapper.mapStruct(srcPtr, destPtr); err != nil {
+				return err
+			}
+			if aGuard.limits.MaxBytes > 0 {
+				if err := aGuard.result(s.mapper.resultSize(destPtr)); err != nil {
+					return err
+				}
+			}
+			return write(destPtr)
+		}
+		if err = s.walker.traverse(aGuard, s.walker.root, source, visitor, nil); err != nil {
+			return err
+		}
+	}
+	if !opts.ndjson {
+		if rows == 0 {
+			if err = writer.WriteByte('['); err != nil {
+				return err
+			}
+		}
+		if err = writer.WriteByte(']'); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// encode returns dest row JSON object, returned bytes are valid till the next call
+func (e *jsonEncoder) encode(destPtr unsafe.Pointer) ([]byte, error) {
+	buffer := append(e.buffer[:0], '{')
+	written := 0
+	for _, aField := range e.fields {
+		if aField.nullable && e.options.omitEmpty && reflect.NewAt(aField.xField.Type, aField.xField.Pointer(destPtr)).Elem().IsNil() {
+			continue
+		}
+		if aField.omitEmpty && isEmptyJSON(reflect.NewAt(aField.xField.Type, aField.xField.Pointer(destPtr)).Elem()) {
+			continue
+		}
+		if written > 0 {
+			buffer = append(buffer, ',')
+		}
+		written++
+		buffer = append(buffer, aField.name...)
+		var err error
+		if buffer, err = aField.appendValue(buffer, destPtr); err != nil {
+			return nil, err
+		}
+	}
+	e.buffer = append(buffer, '}')
+	return e.buffer, nil
+}
+
+// appendValue appends field JSON value
+func (f *jsonField) appendValue(buffer []byte, destPtr unsafe.Pointer) ([]byte, error) {
+	if f.native {
+		ptr := f.xField.Pointer(destPtr)
+		switch f.xField.Type.Kind() {
+		case reflect.Int:
+			return strconv.AppendInt(buffer, int64(*(*int)(ptr)), 10), nil
+		case reflect.Int64:
+			return strconv.AppendInt(buffer, *(*int64)(ptr), 10), nil
+		case reflect.Bool:
+			return strconv.AppendBool(buffer, *(*bool)(ptr)), nil
+		}
+	}
+	data, err := json.Marshal(f.xField.Interface(destPtr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode field: %v, %w", f.xField.Name, err)
+	}
+	if f.quoted && string(data) != "null" {
+		if data, err = json.Marshal(string(data)); err != nil {
+			return nil, err
+		}
+	}
+	return append(buffer, data...), nil
+}
+
+// isEmptyJSON returns true for value omitted by json omitempty option
+func isEmptyJSON(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	case reflect.Struct:
+		return false
+	}
+	return value.IsZero()
+}
+
+// newJSONEncoder returns dest type encoder, unexported and json:"-" tagged fields are skipped,
+// json tag name and options take precedence over the naming option, which formats untagged field names only
+func newJSONEncoder(destType reflect.Type, opts *jsonOptions) *jsonEncoder {
+	ret := &jsonEncoder{options: opts}
+	for i := 0; i < destType.NumField(); i++ {
+		structField := destType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		tag := structField.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		tagName, tagOptions, _ := strings.Cut(tag, ",")
+		fieldName := tagName
+		if fieldName == "" {
+			fieldName = opts.naming.Format(structField.Name)
+		}
+		name, _ := json.Marshal(fieldName)
+		aField := &jsonField{xField: xunsafe.NewField(structField), name: append(name, ':')}
+		for _, option := range strings.Split(tagOptions, ",") {
+			switch option {
+			case "omitempty":
+				aField.omitEmpty = true
+			case "string":
+				aField.quoted = isQuotable(structField.Type)
+			}
+		}
+		switch structField.Type.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			aField.nullable = true
+		case reflect.Int, reflect.Int64, reflect.Bool:
+			aField.native = !aField.quoted && !structField.Type.Implements(jsonMarshalerType) && !reflect.PtrTo(structField.Type).Implements(jsonMarshalerType)
+		}
+		ret.fields = append(ret.fields, aField)
+	}
+	return ret
+}
+
+// isQuotable returns true for types json string tag option applies to
+func isQuotable(fType reflect.Type) bool {
+	if fType.Kind() == reflect.Ptr {
+		fType = fType.Elem()
+	}
+	switch fType.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
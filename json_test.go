@@ -0,0 +1,188 @@
+package structql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/structql/naming"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuery_EncodeJSON(t *testing.T) {
+	type Product struct {
+		ID        int
+		Name      string
+		VendorID  int
+		Revenue   float64
+		Rating    *int
+		CreatedAt time.Time
+	}
+	type Vendor struct {
+		ID       int
+		Products []*Product
+	}
+	type Summary struct {
+		ProductName string
+		Rating      *int
+	}
+	rating := 4
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	vendors := []*Vendor{
+		{ID: 1, Products: []*Product{{ID: 1, Name: "a\"1", VendorID: 1, Revenue: 1.5, Rating: &rating, CreatedAt: created}, {ID: 2, Name: "b", VendorID: 1, Revenue: 3}}},
+		{ID: 2, Products: []*Product{{ID: 3, Name: "c", VendorID: 2, Revenue: 2}}},
+	}
+
+	var testCases = []struct {
+		description string
+		query       string
+		dest        reflect.Type
+		values      []interface{}
+		options     []JSONOption
+		expect      string
+		expectErr   error
+	}{
+		{
+			description: "array",
+			query:       "SELECT ID, Name, Rating FROM `/Products`",
+			expect:      `[{"ID":1,"Name":"a\"1","Rating":4},{"ID":2,"Name":"b","Rating":null},{"ID":3,"Name":"c","Rating":null}]`,
+		},
+		{
+			description: "ndjson with omit empty",
+			query:       "SELECT ID, Rating FROM `/Products` WHERE Revenue > ?",
+			values:      []interface{}{1.6},
+			options:     []JSONOption{WithNDJSON(), WithOmitEmpty()},
+			expect:      "{\"ID\":2}\n{\"ID\":3}\n",
+		},
+		{
+			description: "snake case",
+			query:       "SELECT VendorID, Name AS ProductName, CreatedAt FROM `/Products[ID=1]`",
+			options:     []JSONOption{WithJSONNaming(naming.Snake)},
+			expect:      `[{"vendor_id":1,"product_name":"a\"1","created_at":"2024-01-02T00:00:00Z"}]`,
+		},
+		{
+			description: "lower camel case",
+			query:       "SELECT VendorID, Revenue FROM `/Products[ID=2]`",
+			options:     []JSONOption{WithJSONNaming(naming.LowerCamel)},
+			expect:      `[{"vendorID":1,"revenue":3}]`,
+		},
+		{
+			description: "dest type",
+			query:       "SELECT Name AS ProductName, Rating FROM `/Products`",
+			dest:        reflect.TypeOf(Summary{}),
+			options:     []JSONOption{WithOmitEmpty()},
+			expect:      `[{"ProductName":"a\"1","Rating":4},{"ProductName":"b"},{"ProductName":"c"}]`,
+		},
+		{
+			description: "aggregation",
+			query:       "SELECT VendorID, SUM(Revenue) AS Revenue, ARRAY_AGG(ID) AS Ids FROM `/Products` GROUP BY VendorID",
+			expect:      `[{"VendorID":1,"Revenue":4.5,"Ids":[1,2]},{"VendorID":2,"Revenue":2,"Ids":[3]}]`,
+		},
+		{
+			description: "window",
+			query:       "SELECT ID, ROW_NUMBER() OVER (ORDER BY Revenue DESC) AS Pos FROM `/Products`",
+			options:     []JSONOption{WithNDJSON()},
+			expect:      "{\"ID\":1,\"Pos\":3}\n{\"ID\":2,\"Pos\":1}\n{\"ID\":3,\"Pos\":2}\n",
+		},
+		{
+			description: "no rows",
+			query:       "SELECT ID FROM `/Products` WHERE ID > 10",
+			expect:      `[]`,
+		},
+		{
+			description: "max rows",
+			query:       "SELECT ID FROM `/Products`",
+			values:      []interface{}{WithMaxRows(2)},
+			expectErr:   ErrMaxRowsExceeded,
+		},
+	}
+
+	for _, testCase := range testCases {
+		query, err := NewQuery(testCase.query, reflect.TypeOf(vendors), testCase.dest, testCase.values...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		writer := &bytes.Buffer{}
+		err = query.EncodeJSON(writer, vendors, testCase.options...)
+		if testCase.expectErr != nil {
+			assert.True(t, errors.Is(err, testCase.expectErr), testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, writer.String(), testCase.description)
+		if !bytes.HasSuffix(writer.Bytes(), []byte("\n")) {
+			assert.True(t, json.Valid(writer.Bytes()), testCase.description)
+		}
+	}
+}
+
+func TestQuery_EncodeJSON_Tags(t *testing.T) {
+	type Account struct {
+		ID       int      `json:"id"`
+		Name     string   `json:"name,omitempty"`
+		Secret   string   `json:"-"`
+		Score    *float64 `json:"score,omitempty"`
+		Code     int      `json:"code,string"`
+		internal int
+	}
+	type Summary struct {
+		ID    int    `json:"account_id"`
+		Label string `json:"label,omitempty"`
+		Total int
+	}
+	score := 1.5
+	accounts := []*Account{
+		{ID: 1, Name: "a", Secret: "s", Score: &score, Code: 10, internal: 7},
+		{ID: 2, Secret: "t", Code: 20, internal: 8},
+	}
+
+	var testCases = []struct {
+		description string
+		query       string
+		dest        reflect.Type
+		options     []JSONOption
+	}{
+		{
+			description: "tagged source",
+			query:       "SELECT * FROM `/`",
+		},
+		{
+			description: "tagged source with naming",
+			query:       "SELECT ID, Name, Score, Code FROM `/`",
+			options:     []JSONOption{WithJSONNaming(naming.UpperSnake)},
+		},
+		{
+			description: "tagged dest type",
+			query:       "SELECT ID, Name AS Label, Code AS Total FROM `/`",
+			dest:        reflect.TypeOf(Summary{}),
+		},
+	}
+
+	for _, testCase := range testCases {
+		query, err := NewQuery(testCase.query, reflect.TypeOf(accounts), testCase.dest)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		writer := &bytes.Buffer{}
+		if !assert.Nil(t, query.EncodeJSON(writer, accounts, testCase.options...), testCase.description) {
+			continue
+		}
+		result, err := query.Select(accounts)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		expect, err := json.Marshal(result)
+		assert.Nil(t, err, testCase.description)
+		assert.EqualValues(t, string(expect), writer.String(), testCase.description)
+	}
+
+	query, err := NewQuery("SELECT ID, Name AS Label, Code AS Total FROM `/`", reflect.TypeOf(accounts), reflect.TypeOf(Summary{}))
+	assert.Nil(t, err)
+	writer := &bytes.Buffer{}
+	assert.Nil(t, query.EncodeJSON(writer, accounts, WithJSONNaming(naming.Snake)))
+	assert.EqualValues(t, `[{"account_id":1,"label":"a","total":10},{"account_id":2,"total":20}]`, writer.String(), "naming formats untagged fields only")
+}
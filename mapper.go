@@ -9,6 +9,9 @@ import (
 	"github.com/viant/sqlparser"
 	"github.com/viant/sqlparser/expr"
 	"github.com/viant/sqlparser/query"
+	"github.com/viant/structql/errs"
+	sparser "github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
 	"github.com/viant/xunsafe"
 )
 
@@ -25,22 +28,101 @@ const (
 type (
 	//Mapper represents struct mapper
 	Mapper struct {
-		fields    []field
-		dest      reflect.Type
-		aggregate bool
-		groupBy   []string
-		xType     *xunsafe.Type
+		fields       []field
+		aggregations []field
+		windows      []field
+		dest         reflect.Type
+		aggregate    bool
+		groupBy      []*xunsafe.Field
+		keyType      reflect.Type
+		xType        *xunsafe.Type
+		payload      []*xunsafe.Field
 	}
 )
 
 // Map maps source to appender
-func (m *Mapper) Map(walker *Walker, source interface{}, appender *xunsafe.Appender) error {
+func (m *Mapper) Map(walker *Walker, source interface{}, appender *xunsafe.Appender) (err error) {
+	defer scalar.Recover(&err)
 	ctx := NewContext(m, appender, m.aggregate)
-	return walker.mapNode(ctx, walker.root, source)
+	if err := walker.mapNode(ctx, walker.root, source); err != nil {
+		return err
+	}
+	return ctx.flush()
+}
+
+// mapWithGuard maps source to appender, it returns mapped source items when the query uses window functions
+func (m *Mapper) mapWithGuard(aGuard *guard, walker *Walker, source interface{}, appender *xunsafe.Appender) ([]unsafe.Pointer, error) {
+	ctx := newContext(aGuard, m, appender, m.aggregate)
+	if err := walker.mapNode(ctx, walker.root, source); err != nil {
+		return nil, err
+	}
+	return ctx.sources, ctx.flush()
+}
+
+// computeWindows sets window function values on dest items mapped from the source items
+func (m *Mapper) computeWindows(sources []unsafe.Pointer, destSlice *xunsafe.Slice, destPtr unsafe.Pointer) error {
+	for i := range m.windows {
+		aField := &m.windows[i]
+		values, err := aField.window.compute(sources)
+		if err != nil {
+			return err
+		}
+		for k, value := range values {
+			aField.setComputed(xunsafe.AsPointer(destSlice.ValuePointerAt(destPtr, k)), value)
+		}
+	}
+	return nil
+}
+
+// groupKey returns GROUP BY key for source item
+func (m *Mapper) groupKey(srcItemPtr unsafe.Pointer) interface{} {
+	if len(m.groupBy) == 1 {
+		return fieldValue(m.groupBy[0], srcItemPtr)
+	}
+	key := reflect.New(m.keyType).Elem()
+	for i, aField := range m.groupBy {
+		if value := fieldValue(aField, srcItemPtr); value != nil {
+			key.Index(i).Set(reflect.ValueOf(value))
+		}
+	}
+	return key.Interface()
+}
+
+// newGroup returns group with initialized aggregators, dest value is allocated unless supplied
+func (m *Mapper) newGroup(value interface{}) *group {
+	if value == nil {
+		value = reflect.New(m.dest).Interface()
+	}
+	ret := &group{value: value, aggregators: make([]Aggregator, len(m.aggregations))}
+	for i := range m.aggregations {
+		ret.aggregators[i] = m.aggregations[i].aggregation.newAggregator()
+	}
+	return ret
+}
+
+// resultSize returns approximate dest record size: struct size with string and slice payloads
+func (m *Mapper) resultSize(destItemPtr unsafe.Pointer) int {
+	ret := int(m.dest.Size())
+	for _, aField := range m.payload {
+		ptr := aField.Pointer(destItemPtr)
+		switch aField.Kind() {
+		case reflect.String:
+			ret += len(*(*string)(ptr))
+		case reflect.Slice:
+			ret += len(*(*[]byte)(ptr)) * int(aField.Type.Elem().Size()) //slice header layout does not depend on element type
+		}
+	}
+	return ret
 }
 
 // MapStruct maps struct
-func (m *Mapper) MapStruct(srcItemPtr unsafe.Pointer, destItemPtr unsafe.Pointer) error {
+func (m *Mapper) MapStruct(srcItemPtr unsafe.Pointer, destItemPtr unsafe.Pointer) (err error) {
+	defer scalar.Recover(&err)
+	return m.mapStruct(srcItemPtr, destItemPtr)
+}
+
+// mapStruct maps struct, function error is raised as panic recovered by the exported caller
+func (m *Mapper) mapStruct(srcItemPtr unsafe.Pointer, destItemPtr unsafe.Pointer) error {
 	if srcItemPtr == nil || destItemPtr == nil {
 		return nil
 	}
@@ -70,6 +152,16 @@ func (m *Mapper) MapStruct(srcItemPtr unsafe.Pointer, destItemPtr unsafe.Pointer
 func (m *Mapper) setType(dest reflect.Type) {
 	m.dest = dest
 	m.xType = xunsafe.NewType(dest)
+	m.payload = nil
+	if dest.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < dest.NumField(); i++ {
+		switch dest.Field(i).Type.Kind() {
+		case reflect.String, reflect.Slice:
+			m.payload = append(m.payload, xunsafe.NewField(dest.Field(i)))
+		}
+	}
 }
 
 // Map map fields
@@ -78,6 +170,10 @@ func (f *field) Map(src, dest unsafe.Pointer) {
 }
 
 func (f *field) copy(src unsafe.Pointer, dest unsafe.Pointer) {
+	if f.mapKind == mapKindExpr {
+		f.setComputed(dest, f.compute(src))
+		return
+	}
 	if f.mapKind == mapKindDirectPrimitive {
 		source := f.src.Interface(src)
 		f.dest.Set(dest, source)
@@ -90,20 +186,46 @@ func (f *field) copy(src unsafe.Pointer, dest unsafe.Pointer) {
 	} else {
 		source := f.src.Interface(src)
 		src = xunsafe.AsPointer(source)
-		if f.dest.Offset > 0 {
-			d := f.dest.Interface(dest)
-			dest = xunsafe.AsPointer(d)
-		}
+		dest = f.dest.Pointer(dest)
 	}
 	f.translate(src, dest)
 }
 
+// setComputed sets computed value, value is converted to dest type, nil value leaves zero value
+func (f *field) setComputed(dest unsafe.Pointer, value interface{}) {
+	if value == nil {
+		return
+	}
+	rValue := reflect.ValueOf(value)
+	destType := f.dest.Type
+	isPtr := destType.Kind() == reflect.Ptr
+	if isPtr {
+		destType = destType.Elem()
+	}
+	if rValue.Type() != destType {
+		if !rValue.CanConvert(destType) {
+			return
+		}
+		rValue = rValue.Convert(destType)
+	}
+	if isPtr {
+		ptr := reflect.New(destType)
+		ptr.Elem().Set(rValue)
+		rValue = ptr
+	}
+	f.dest.SetValue(dest, rValue.Interface())
+}
+
 func (f *field) translate(source, dest unsafe.Pointer) {
 	f.cp(source, dest)
 }
 
 // NewMapper creates a mapper
 func NewMapper(source reflect.Type, dest reflect.Type, sel *query.Select) (*Mapper, error) {
+	return newMapper(source, dest, sel, nil, nil, nil, nil)
+}
+
+func newMapper(source reflect.Type, dest reflect.Type, sel *query.Select, functions *scalar.Registry, aggregates *aggregates, windows map[int]*sparser.Window, tags []*tagStrategy) (*Mapper, error) {
 	ret := &Mapper{
 		fields: make([]field, 0, len(sel.List)),
 	}
@@ -115,11 +237,18 @@ func NewMapper(source reflect.Type, dest reflect.Type, sel *query.Select) (*Mapp
 
 	hasDest := dest != nil
 	var destFields []reflect.StructField
+	var aTagger *tagger
+	if !hasDest {
+		aTagger = newTagger(source, tags)
+	}
 	for i := range sel.List {
 		item := sel.List[i]
-		ret.fields = append(ret.fields, field{})
-		fieldMap := &ret.fields[i]
-		if err := mapSourceField(source, item, fieldMap); err != nil {
+		fieldMap := &field{}
+		if spec, ok := windows[i]; ok {
+			if err := mapWindowField(source, item, fieldMap, spec, functions, aggregates); err != nil {
+				return nil, err
+			}
+		} else if err := mapSourceField(source, item, fieldMap, functions, aggregates); err != nil {
 			return nil, err
 		}
 		if item.Alias == "" {
@@ -131,23 +260,13 @@ func NewMapper(source reflect.Type, dest reflect.Type, sel *query.Select) (*Mapp
 
 		if !hasDest {
 			fieldName := item.Alias
-			if fieldMap.src.Tag != "" {
-				tag := string(fieldMap.src.Tag)
-				//TODO detect case format and replace accordingly
-				tag = strings.ReplaceAll(tag, fieldMap.src.Name, item.Alias)
-				fieldMap.src.Tag = reflect.StructTag(tag)
-			}
-			tag := reflect.StructTag(fieldMap.src.Tag)
-
-			if fieldMap.aggregate {
-				tag = ""
-			}
-
+			computed := fieldMap.aggregation != nil || fieldMap.compute != nil || fieldMap.window != nil
+			tag := aTagger.tag(fieldMap.src, fieldName, computed)
 			fieldType := fieldMap.src.Type
 			if fieldMap.dest != nil {
 				fieldType = fieldMap.dest.Type
 			}
-			pkgPath := fieldMap.src.PkgPath()
+			pkgPath := "" //exported field, source field type package path would make it unexported
 			if strings.ToLower(fieldName[:1]) == fieldName[:1] {
 				pkgPath = "autogen"
 			}
@@ -157,25 +276,61 @@ func NewMapper(source reflect.Type, dest reflect.Type, sel *query.Select) (*Mapp
 		if err := mapDestField(dest, item, fieldMap); err != nil {
 			return nil, err
 		}
-
+		if fieldMap.window != nil {
+			ret.windows = append(ret.windows, *fieldMap)
+			continue
+		}
+		if fieldMap.aggregation != nil {
+			ret.aggregations = append(ret.aggregations, *fieldMap)
+			continue
+		}
 		if err := fieldMap.configure(); err != nil {
 			return nil, err
 		}
+		ret.fields = append(ret.fields, *fieldMap)
+	}
+	if err := ret.setGroupBy(source, sel.GroupBy); err != nil {
+		return nil, err
+	}
+	if ret.aggregate && len(ret.windows) > 0 {
+		return nil, fmt.Errorf("window functions can not be used with aggregation")
 	}
-
 	ret.setType(dest)
 	return ret, nil
 }
 
-func mapSourceField(source reflect.Type, item *query.Item, fieldMap *field) error {
+func (m *Mapper) setGroupBy(source reflect.Type, groupBy query.List) error {
+	for _, item := range groupBy {
+		name := sqlparser.Stringify(item.Expr)
+		aField := xunsafe.FieldByName(source, name)
+		if aField == nil {
+			return errs.UnknownField("failed to lookup group by field: '%s' at %s", name, source.String())
+		}
+		keyType := aField.Type
+		if keyType.Kind() == reflect.Ptr {
+			keyType = keyType.Elem()
+		}
+		if !keyType.Comparable() {
+			return errs.UnsupportedType("unsupported group by field type: '%s' %s", name, aField.Type.String())
+		}
+		m.groupBy = append(m.groupBy, aField)
+	}
+	if len(m.groupBy) > 0 {
+		m.aggregate = true
+		m.keyType = reflect.ArrayOf(len(m.groupBy), reflect.TypeOf((*interface{})(nil)).Elem())
+	}
+	return nil
+}
+
+func mapSourceField(source reflect.Type, item *query.Item, fieldMap *field, functions *scalar.Registry, aggregates *aggregates) error {
 	switch actual := item.Expr.(type) {
 	case *expr.Selector:
 		if fieldMap.src = xunsafe.FieldByName(source, actual.Name); fieldMap.src == nil {
-			return fmt.Errorf("failed to lookup source field: '%s' at %s", actual.Name, source.String())
+			return errs.UnknownField("failed to lookup source field: '%s' at %s", actual.Name, source.String())
 		}
 	case *expr.Ident:
 		if fieldMap.src = xunsafe.FieldByName(source, actual.Name); fieldMap.src == nil {
-			return fmt.Errorf("failed to lookup source field: '%s' at %s", actual.Name, source.String())
+			return errs.UnknownField("failed to lookup source field: '%s' at %s", actual.Name, source.String())
 		}
 	case *expr.Call:
 		funName := sqlparser.Stringify(actual.X)
@@ -188,13 +343,38 @@ func mapSourceField(source reflect.Type, item *query.Item, fieldMap *field) erro
 			}
 			colName := sqlparser.Stringify(actual.Args[0])
 			if fieldMap.src = xunsafe.FieldByName(source, colName); fieldMap.src == nil {
-				return fmt.Errorf("failed to lookup source field: '%s' at %s", colName, source.String())
+				return errs.UnknownField("failed to lookup source field: '%s' at %s", colName, source.String())
 			}
 			destName := item.Alias
 			if item.Alias == "" {
 				destName = fieldMap.src.Name
 			}
 			fieldMap.dest = &xunsafe.Field{Name: destName, Type: reflect.SliceOf(fieldMap.src.Type)}
+		default:
+			if function := aggregates.lookup(funName); function != nil {
+				anAggregation, resultType, err := compileAggregation(source, function, actual, functions)
+				if err != nil {
+					return err
+				}
+				destName := item.Alias
+				if destName == "" {
+					destName = function.name
+				}
+				fieldMap.aggregate = true
+				fieldMap.aggregation = anAggregation
+				fieldMap.src = xunsafe.NewField(reflect.StructField{Name: destName, Type: resultType})
+				return nil
+			}
+			compute, kind, err := compileFunction(source, actual, functions)
+			if err != nil {
+				return err
+			}
+			destName := item.Alias
+			if destName == "" {
+				destName = strings.ToUpper(funName)
+			}
+			fieldMap.compute = compute
+			fieldMap.src = xunsafe.NewField(reflect.StructField{Name: destName, Type: kind.Type()})
 		}
 
 	default:
@@ -203,12 +383,34 @@ func mapSourceField(source reflect.Type, item *query.Item, fieldMap *field) erro
 	return nil
 }
 
+// mapWindowField maps window function projection, i.e. RANK() OVER (PARTITION BY VendorID ORDER BY Revenue DESC)
+func mapWindowField(source reflect.Type, item *query.Item, fieldMap *field, spec *sparser.Window, functions *scalar.Registry, aggregates *aggregates) error {
+	call, ok := item.Expr.(*expr.Call)
+	if !ok {
+		return fmt.Errorf("invalid window function: %v", sqlparser.Stringify(item.Expr))
+	}
+	aWindow, resultType, err := compileWindow(source, call, spec, functions, aggregates)
+	if err != nil {
+		return err
+	}
+	destName := item.Alias
+	if destName == "" {
+		destName = aWindow.name
+	}
+	fieldMap.window = aWindow
+	fieldMap.src = xunsafe.NewField(reflect.StructField{Name: destName, Type: resultType})
+	return nil
+}
+
 func mapDestField(source reflect.Type, item *query.Item, fieldMap *field) error {
 	if fieldMap.dest != nil {
+		if aField := xunsafe.FieldByName(source, item.Alias); aField != nil {
+			fieldMap.dest = aField
+		}
 		return nil
 	}
-	if fieldMap.dest = xunsafe.FieldByName(source, item.Alias); fieldMap.src == nil {
-		return fmt.Errorf("failed to lookup dest field: '%s' at %s", item.Alias, source.String())
+	if fieldMap.dest = xunsafe.FieldByName(source, item.Alias); fieldMap.dest == nil {
+		return errs.UnknownField("failed to lookup dest field: '%s' at %s", item.Alias, source.String())
 	}
 	return nil
 }
@@ -0,0 +1,148 @@
+package naming
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Case represents identifier case format
+type Case int
+
+const (
+	// AsIs keeps identifier as written
+	AsIs = Case(iota)
+	// UpperCamel represents upper camel case, i.e. VendorName
+	UpperCamel
+	// LowerCamel represents lower camel case, i.e. vendorName
+	LowerCamel
+	// Snake represents lower snake case, i.e. vendor_name
+	Snake
+	// UpperSnake represents upper snake case, i.e. VENDOR_NAME
+	UpperSnake
+)
+
+// String returns case name
+func (c Case) String() string {
+	switch c {
+	case UpperCamel:
+		return "UpperCamel"
+	case LowerCamel:
+		return "LowerCamel"
+	case Snake:
+		return "Snake"
+	case UpperSnake:
+		return "UpperSnake"
+	}
+	return "AsIs"
+}
+
+// Format formats identifier with the case, words are split on separators and case changes,
+// acronyms are preserved with camel cases, i.e. VendorID is vendorID in LowerCamel and vendor_id in Snake,
+// upper snake case words are not treated as acronyms
+func (c Case) Format(name string) string {
+	if c == AsIs {
+		return name
+	}
+	if Detect(name) == UpperSnake {
+		name = strings.ToLower(name)
+	}
+	words := Words(name)
+	for i, word := range words {
+		switch c {
+		case UpperCamel:
+			words[i] = capitalize(word)
+		case LowerCamel:
+			if i == 0 {
+				words[i] = strings.ToLower(word)
+			} else {
+				words[i] = capitalize(word)
+			}
+		case Snake:
+			words[i] = strings.ToLower(word)
+		case UpperSnake:
+			words[i] = strings.ToUpper(word)
+		}
+	}
+	switch c {
+	case Snake, UpperSnake:
+		return strings.Join(words, "_")
+	}
+	return strings.Join(words, "")
+}
+
+// Detect returns identifier case, single lower case word is detected as LowerCamel,
+// single upper case word and mixed formats are detected as AsIs
+func Detect(name string) Case {
+	hasLower, hasUpper := false, false
+	for _, r := range name {
+		hasLower = hasLower || unicode.IsLower(r)
+		hasUpper = hasUpper || unicode.IsUpper(r)
+	}
+	if strings.ContainsRune(name, '_') {
+		switch {
+		case hasLower && !hasUpper:
+			return Snake
+		case hasUpper && !hasLower:
+			return UpperSnake
+		}
+		return AsIs
+	}
+	for _, r := range name {
+		switch {
+		case unicode.IsLower(r):
+			return LowerCamel
+		case unicode.IsUpper(r) && hasLower:
+			return UpperCamel
+		case unicode.IsUpper(r):
+			return AsIs
+		}
+	}
+	return AsIs
+}
+
+// Words splits identifier into words on '_', '-', ' ' separators and case changes, digits are kept with the preceding word,
+// plural acronym is kept as a single word, i.e. ProductIDs is Product and IDs
+func Words(name string) []string {
+	var ret []string
+	runes := []rune(name)
+	begin := 0
+	for i, r := range runes {
+		switch r {
+		case '_', '-', ' ':
+			if i > begin {
+				ret = append(ret, string(runes[begin:i]))
+			}
+			begin = i + 1
+			continue
+		}
+		if i == begin || !unicode.IsUpper(r) {
+			continue
+		}
+		prev := runes[i-1]
+		nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if nextLower && runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2])) {
+			nextLower = false //plural acronym, i.e. IDs
+		}
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+			ret = append(ret, string(runes[begin:i]))
+			begin = i
+		}
+	}
+	if begin < len(runes) {
+		ret = append(ret, string(runes[begin:]))
+	}
+	return ret
+}
+
+// capitalize returns word with upper case first letter, the rest of the word is lower cased unless the word is an acronym
+func capitalize(word string) string {
+	if word == "" {
+		return word
+	}
+	if acronym := strings.TrimSuffix(word, "s"); acronym == strings.ToUpper(acronym) && len([]rune(acronym)) > 1 {
+		return word
+	}
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
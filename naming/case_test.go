@@ -0,0 +1,50 @@
+package naming
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCase_Format(t *testing.T) {
+	var testCases = []struct {
+		description string
+		name        string
+		aCase       Case
+		expect      string
+	}{
+		{description: "as is", name: "VendorID", aCase: AsIs, expect: "VendorID"},
+		{description: "upper camel to snake", name: "VendorName", aCase: Snake, expect: "vendor_name"},
+		{description: "acronym to snake", name: "VendorID", aCase: Snake, expect: "vendor_id"},
+		{description: "leading acronym to snake", name: "HTTPStatus2Code", aCase: Snake, expect: "http_status2_code"},
+		{description: "plural acronym to snake", name: "ProductIDsList", aCase: Snake, expect: "product_ids_list"},
+		{description: "upper camel to lower camel", name: "VendorID", aCase: LowerCamel, expect: "vendorID"},
+		{description: "plural acronym to lower camel", name: "ProductIDs", aCase: LowerCamel, expect: "productIDs"},
+		{description: "acronym to lower camel", name: "ID", aCase: LowerCamel, expect: "id"},
+		{description: "snake to lower camel", name: "vendor_name", aCase: LowerCamel, expect: "vendorName"},
+		{description: "snake to upper camel", name: "vendor_id", aCase: UpperCamel, expect: "VendorId"},
+		{description: "lower camel to upper snake", name: "vendorName", aCase: UpperSnake, expect: "VENDOR_NAME"},
+		{description: "upper snake to upper camel", name: "VENDOR_NAME", aCase: UpperCamel, expect: "VendorName"},
+	}
+	for _, testCase := range testCases {
+		assert.EqualValues(t, testCase.expect, testCase.aCase.Format(testCase.name), testCase.description)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		expect Case
+	}{
+		{name: "VendorName", expect: UpperCamel},
+		{name: "vendorName", expect: LowerCamel},
+		{name: "name", expect: LowerCamel},
+		{name: "vendor_name", expect: Snake},
+		{name: "VENDOR_NAME", expect: UpperSnake},
+		{name: "ID", expect: AsIs},
+		{name: "Vendor_name", expect: AsIs},
+		{name: "", expect: AsIs},
+	}
+	for _, testCase := range testCases {
+		assert.EqualValues(t, testCase.expect, Detect(testCase.name), testCase.name)
+	}
+}
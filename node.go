@@ -6,8 +6,10 @@ import (
 	"github.com/viant/igo/exec"
 	"github.com/viant/igo/exec/expr"
 	snode "github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
 	"github.com/viant/structql/node"
 	"github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
 	"github.com/viant/xunsafe"
 	"reflect"
 )
@@ -28,9 +30,12 @@ type Node struct {
 	xField    *xunsafe.Field
 	xSlice    *xunsafe.Slice
 	selector  *node.Selector
+	position  *node.Position
 	child     *Node
 	expr      *expr.Bool
 	exprSel   *exec.Selector
+	predicate parser.Predicate
+	indexes   []*indexScan
 }
 
 // Type returns node Type
@@ -54,8 +59,19 @@ func (n *Node) LeafType() reflect.Type {
 	return n.ownerType
 }
 
-// When applied expr or returns true if not defined
+// Match returns true when value matches node criteria or criteria is not defined, criteria function error is returned
+func (n *Node) Match(value interface{}) (matched bool, err error) {
+	defer scalar.Recover(&err)
+	return n.When(value), nil
+}
+
+// When applied expr or returns true if not defined, criteria function error is raised as *scalar.FunctionError panic,
+// use Match to get the error
 func (n *Node) When(value interface{}) bool {
+	if n.predicate != nil {
+		ptr := xunsafe.AsPointer(value)
+		return ptr != nil && n.predicate(ptr)
+	}
 	if n.expr == nil {
 		return true
 	}
@@ -66,6 +82,14 @@ func (n *Node) When(value interface{}) bool {
 	return result
 }
 
+// bounds returns slice item range [from, to) for array node
+func (n *Node) bounds(sliceLen int) (int, int) {
+	if n.position == nil {
+		return 0, sliceLen
+	}
+	return n.position.Bounds(sliceLen)
+}
+
 // LeafOwnerType returns leaf type
 func (n *Node) LeafOwnerType() reflect.Type {
 	if n.child != nil {
@@ -77,8 +101,17 @@ func (n *Node) LeafOwnerType() reflect.Type {
 	return n.ownerType
 }
 
+// hasCriteria returns true if node criteria is defined
+func (n *Node) hasCriteria() bool {
+	return n.expr != nil || n.predicate != nil
+}
+
 // NewNode creates a node
 func NewNode(ownerType reflect.Type, sel *node.Selector, values *node.Values) (*Node, error) {
+	return newNode(ownerType, sel, values, IgoEvaluator)
+}
+
+func newNode(ownerType reflect.Type, sel *node.Selector, values *node.Values, evaluator Evaluator) (*Node, error) {
 	var err error
 	aNode := &Node{selector: sel}
 	aNode.ownerType = ownerType
@@ -94,32 +127,54 @@ func NewNode(ownerType reflect.Type, sel *node.Selector, values *node.Values) (*
 		aNode.kind = nodeKindArray
 		aNode.IsLeaf = false
 		aNode.xSlice = xunsafe.NewSlice(rawType)
-		if aNode.child, err = NewNode(aNode.ownerType.Elem(), sel, values); err != nil {
+		itemSel := sel
+		if sel.Position != nil {
+			aNode.position = sel.Position
+			itemSel = &node.Selector{Name: sel.Name, Criteria: sel.Criteria, Holder: sel.Holder, Child: sel.Child}
+		}
+		if aNode.child, err = newNode(aNode.ownerType.Elem(), itemSel, values, evaluator); err != nil {
 			return nil, err
 		}
 	case reflect.Struct:
 		aNode.kind = nodeKindObject
 		if sel.Name != "" {
 			if aNode.xField = xunsafe.FieldByName(rawType, sel.Name); aNode.xField == nil {
-				return nil, fmt.Errorf("failed to lookup field: '%v' on %v", sel.Name, rawType.Name())
+				return nil, errs.UnknownField("failed to lookup field: '%v' on %v", sel.Name, rawType.Name())
+			}
+			childSel := sel.Child
+			if childSel != nil && childSel.Criteria != nil && childSel.Holder == "" {
+				childSel = &node.Selector{Name: childSel.Name, Criteria: childSel.Criteria, Holder: sel.Name, Position: childSel.Position, Child: childSel.Child}
 			}
-			if aNode.child, err = NewNode(aNode.xField.Type, sel.Child, values); err != nil {
+			if aNode.child, err = newNode(aNode.xField.Type, childSel, values, evaluator); err != nil {
 				return nil, err
 			}
 		}
 		if sel.Criteria != nil {
-			aNode.expr, aNode.exprSel, err = compileCriteria(sel.Holder, sel.Criteria, aNode.ownerType, values)
+			err = aNode.compileCriteria(sel.Holder, sel.Criteria, values, evaluator)
 		}
 
 	default:
 		if aNode.IsLeaf {
 			return aNode, nil
 		}
-		return nil, fmt.Errorf("unsupported type:%s", ownerType.String())
+		return nil, errs.UnsupportedType("unsupported type:%s", ownerType.String())
 	}
 	return aNode, err
 }
 
+// compileCriteria compiles node criteria with supplied evaluator
+func (n *Node) compileCriteria(holder string, criteria snode.Node, values *node.Values, evaluator Evaluator) error {
+	var err error
+	if evaluator == NativeEvaluator {
+		if n.predicate, err = parser.AsPredicate(criteria, node.LookupFieldType(holder, n.ownerType), values); err != nil {
+			return fmt.Errorf("failed to compile criteria: %w", err)
+		}
+		return nil
+	}
+	n.expr, n.exprSel, err = compileCriteria(holder, criteria, n.ownerType, values)
+	return err
+}
+
 func compileCriteria(holder string, criteria snode.Node, ownerType reflect.Type, values *node.Values) (*expr.Bool, *exec.Selector, error) {
 	var err error
 	scope := igo.NewScope()
@@ -128,6 +183,9 @@ func compileCriteria(holder string, criteria snode.Node, ownerType reflect.Type,
 		return nil, nil, fmt.Errorf("failed to compile criteria: %w", err)
 	}
 
+	for _, fn := range values.Bindings.Functions {
+		scope.RegisterFunc(fn.Name, fn.Fn)
+	}
 	exprSel, err := scope.DefineVariable(holder, ownerType)
 	if err != nil {
 		return nil, nil, err
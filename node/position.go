@@ -0,0 +1,52 @@
+package node
+
+// Position represents positional selector, either index i.e. [0], [-1] or slice i.e. [1:4], [:2], [-3:]
+type Position struct {
+	Index *int
+	From  *int
+	To    *int
+}
+
+// IsIndex returns true if position represents a single index
+func (p *Position) IsIndex() bool {
+	return p.Index != nil
+}
+
+// Bounds returns [from, to) range for supplied slice length, negative values are counted from the end
+func (p *Position) Bounds(length int) (int, int) {
+	if p.Index != nil {
+		index := normalizeIndex(*p.Index, length)
+		if index < 0 || index >= length {
+			return 0, 0
+		}
+		return index, index + 1
+	}
+	from, to := 0, length
+	if p.From != nil {
+		from = clamp(normalizeIndex(*p.From, length), length)
+	}
+	if p.To != nil {
+		to = clamp(normalizeIndex(*p.To, length), length)
+	}
+	if from > to {
+		return 0, 0
+	}
+	return from, to
+}
+
+func normalizeIndex(index, length int) int {
+	if index < 0 {
+		return length + index
+	}
+	return index
+}
+
+func clamp(index, length int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
@@ -4,8 +4,11 @@ import "github.com/viant/sqlparser/node"
 
 //Selector represents a selector
 type Selector struct {
-	Name     string
-	Criteria node.Node
-	Holder   string
-	Child    *Selector
+	Name           string
+	Criteria       node.Node
+	Holder         string
+	Position       *Position
+	Child          *Selector
+	Offset         int //name byte offset in selector expression
+	CriteriaOffset int //criteria block content byte offset in selector expression
 }
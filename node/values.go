@@ -0,0 +1,121 @@
+package node
+
+import (
+	"fmt"
+	"github.com/viant/structql/errs"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Positional returns positional placeholder value
+func (v *Values) Positional(position int) (interface{}, error) {
+	if v.named != nil {
+		return nil, fmt.Errorf("positional placeholder can not be mixed with named parameters")
+	}
+	if position >= len(v.Values) {
+		return nil, fmt.Errorf("missing placeholder value at position: %v", position)
+	}
+	v.positional = true
+	return v.Values[position], nil
+}
+
+// Named returns named parameter value, parameters are supplied as a single map[string]interface{} or struct value,
+// struct parameter name is defined by sqlx tag or field name
+func (v *Values) Named(name string) (interface{}, error) {
+	if v.named == nil {
+		if err := v.initNamed(); err != nil {
+			return nil, err
+		}
+	}
+	key := name
+	value, ok := v.named[key]
+	if !ok {
+		var candidates []string
+		for candidate := range v.named {
+			if strings.EqualFold(candidate, name) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		switch len(candidates) {
+		case 0:
+			return nil, fmt.Errorf("missing parameter: %v", name)
+		case 1:
+			key, value = candidates[0], v.named[candidates[0]]
+		default:
+			sort.Strings(candidates)
+			return nil, fmt.Errorf("ambiguous parameter: %v, matches: %v", name, strings.Join(candidates, ", "))
+		}
+	}
+	v.used[key] = true
+	return value, nil
+}
+
+// Validate checks that all positional values or map parameters were used by the query
+func (v *Values) Validate() error {
+	if v.named == nil {
+		if v.Bindings != nil && v.Bindings.Count < len(v.Values) {
+			return fmt.Errorf("invalid placeholder values count: %v, expected: %v", len(v.Values), v.Bindings.Count)
+		}
+		return nil
+	}
+	if !v.strict {
+		return nil
+	}
+	var unused []string
+	for name := range v.named {
+		if !v.used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+	sort.Strings(unused)
+	return fmt.Errorf("unused parameters: %v", strings.Join(unused, ", "))
+}
+
+func (v *Values) initNamed() error {
+	if v.positional {
+		return fmt.Errorf("named parameter can not be mixed with positional placeholders")
+	}
+	if len(v.Values) != 1 {
+		return fmt.Errorf("named parameters require a single map or struct value, but had %v values", len(v.Values))
+	}
+	v.named = map[string]interface{}{}
+	v.used = map[string]bool{}
+	rValue := reflect.ValueOf(v.Values[0])
+	for rValue.Kind() == reflect.Ptr && !rValue.IsNil() {
+		rValue = rValue.Elem()
+	}
+	switch rValue.Kind() {
+	case reflect.Map:
+		if rValue.Type().Key().Kind() != reflect.String {
+			break
+		}
+		v.strict = true
+		iter := rValue.MapRange()
+		for iter.Next() {
+			v.named[iter.Key().String()] = iter.Value().Interface()
+		}
+		return nil
+	case reflect.Struct:
+		rType := rValue.Type()
+		for i := 0; i < rType.NumField(); i++ {
+			field := rType.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := field.Name
+			if tag := strings.Split(field.Tag.Get("sqlx"), ",")[0]; tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			v.named[name] = rValue.Field(i).Interface()
+		}
+		return nil
+	}
+	v.named = nil
+	return errs.UnsupportedType("unsupported named parameters type: %T, expected map or struct", v.Values[0])
+}
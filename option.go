@@ -0,0 +1,143 @@
+package structql
+
+import (
+	"fmt"
+	"github.com/viant/structql/naming"
+	"github.com/viant/structql/parser/scalar"
+	"reflect"
+)
+
+// Evaluator represents criteria evaluator
+type Evaluator string
+
+const (
+	// IgoEvaluator evaluates criteria translated into go expression with igo, it is the default evaluator
+	IgoEvaluator = Evaluator("igo")
+	// NativeEvaluator evaluates criteria compiled into closures over struct field accessors
+	NativeEvaluator = Evaluator("native")
+)
+
+type (
+	// Option represents query option, options are passed along with placeholder values
+	Option func(o *options)
+
+	options struct {
+		evaluator  Evaluator
+		limits     Limits
+		functions  *scalar.Registry
+		aggregates *aggregates
+		indexes    []*Index
+		tags       []*tagStrategy
+		err        error
+	}
+)
+
+// WithEvaluator returns option selecting criteria evaluator
+func WithEvaluator(evaluator Evaluator) Option {
+	return func(o *options) {
+		o.evaluator = evaluator
+	}
+}
+
+// WithMaxRows returns option limiting number of produced rows
+func WithMaxRows(maxRows int) Option {
+	return func(o *options) {
+		o.limits.MaxRows = maxRows
+	}
+}
+
+// WithMaxNodes returns option limiting number of visited source nodes
+func WithMaxNodes(maxNodes int) Option {
+	return func(o *options) {
+		o.limits.MaxNodes = maxNodes
+	}
+}
+
+// WithMaxResultBytes returns option limiting approximate result size
+func WithMaxResultBytes(maxBytes int) Option {
+	return func(o *options) {
+		o.limits.MaxBytes = maxBytes
+	}
+}
+
+// WithLimits returns option setting all resource caps
+func WithLimits(limits Limits) Option {
+	return func(o *options) {
+		o.limits = limits
+	}
+}
+
+// WithFunc returns option registering query scoped scalar function, see RegisterFunc
+func WithFunc(name string, fn interface{}) Option {
+	return func(o *options) {
+		function, err := scalar.NewFunction(name, fn)
+		if err != nil {
+			o.err = err
+			return
+		}
+		if o.functions == nil {
+			o.functions = &scalar.Registry{}
+		}
+		o.functions.Register(function)
+	}
+}
+
+// WithAggregate returns option registering query scoped aggregate function, see RegisterAggregate
+func WithAggregate(name string, resultType reflect.Type, newAggregator NewAggregator) Option {
+	return func(o *options) {
+		function, err := newAggregateFunction(name, resultType, newAggregator)
+		if err != nil {
+			o.err = err
+			return
+		}
+		if o.aggregates == nil {
+			o.aggregates = &aggregates{}
+		}
+		o.aggregates.register(function)
+	}
+}
+
+// WithIndex returns option supplying indexes, an index is used when a WHERE clause top level conjunct constrains its column
+func WithIndex(indexes ...*Index) Option {
+	return func(o *options) {
+		o.indexes = append(o.indexes, indexes...)
+	}
+}
+
+// WithTag returns option generating the tag key on autogenerated dest type fields, i.e. json, yaml, db or a custom key,
+// alias is formatted with the naming convention detected from the source field tags with the key,
+// without tag options all tag keys used by the source fields are generated
+func WithTag(key string) Option {
+	return func(o *options) {
+		o.tags = append(o.tags, &tagStrategy{key: key, detect: true})
+	}
+}
+
+// WithTagCase returns option generating the tag key on autogenerated dest type fields with alias formatted with the case
+func WithTagCase(key string, aCase naming.Case) Option {
+	return func(o *options) {
+		o.tags = append(o.tags, &tagStrategy{key: key, aCase: aCase})
+	}
+}
+
+// newOptions returns query options and remaining placeholder values
+func newOptions(values []interface{}) (*options, []interface{}, error) {
+	ret := &options{evaluator: IgoEvaluator}
+	var params []interface{}
+	for _, value := range values {
+		if option, ok := value.(Option); ok {
+			option(ret)
+			continue
+		}
+		params = append(params, value)
+	}
+	if ret.err != nil {
+		return nil, nil, ret.err
+	}
+	switch ret.evaluator {
+	case IgoEvaluator, NativeEvaluator:
+	default:
+		return nil, nil, fmt.Errorf("unsupported evaluator: %v", ret.evaluator)
+	}
+	return ret, params, nil
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/parser/in"
+	"reflect"
+	"strings"
+)
+
+// arrayIn translates value [NOT] IN slice field i.e. 'promo' IN Tags
+func (q *qualifier) arrayIn(binary *expr.Binary, negate bool) error {
+	negate = negate != (strings.ToUpper(binary.Op) == "NOT IN")
+	return q.arrayMatch(binary.Y, []node.Node{binary.X}, true, negate)
+}
+
+// array translates ARRAY_CONTAINS(slice, value) and ARRAY_INTERSECTS(slice, values)
+func (q *qualifier) array(name string, call *expr.Call, negate bool) error {
+	if len(call.Args) != 2 {
+		return fmt.Errorf("invalid %v arguments count: %v", name, len(call.Args))
+	}
+	return q.arrayMatch(call.Args[0], call.Args[1:], name == "ARRAY_CONTAINS", negate)
+}
+
+// arrayMatch writes registered set intersection call for slice field, values are resolved at compile time
+func (q *qualifier) arrayMatch(array node.Node, items []node.Node, single bool, negate bool) error {
+	var guards []string
+	value, fType, err := q.inOperand(array, &guards)
+	if err != nil {
+		return err
+	}
+	set, err := q.arraySet(array, fType, items, single)
+	if err != nil {
+		return err
+	}
+	if set == nil { //SQL: NULL IN array is UNKNOWN
+		q.output.WriteString(constant(false))
+		return nil
+	}
+	q.writeGuards(guards)
+	q.output.WriteString(q.binding.AddFunction("Intersects", set.Intersects) + "(" + value + ")")
+	if negate { //igo does not support negated call expression
+		q.output.WriteString(" == false")
+	}
+	return nil
+}
+
+// arraySet returns set of values matched with slice operand, nil set is returned for single NULL value
+func (q *qualifier) arraySet(array node.Node, fType reflect.Type, items []node.Node, single bool) (*in.Set, error) {
+	label := sqlparser.Stringify(array)
+	if fType == nil || fType.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("invalid operand: %v, expected slice field", label)
+	}
+	itemType := fType.Elem()
+	if itemType.Kind() == reflect.Ptr {
+		itemType = itemType.Elem()
+	}
+	set, err := in.NewSet(itemType)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		values, err := q.listValues(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v operand: %w", label, err)
+		}
+		if single && len(values) != 1 {
+			return nil, fmt.Errorf("invalid %v operand: %v, expected single value", label, sqlparser.Stringify(item))
+		}
+		for _, item := range values {
+			if isNil(item) { //SQL: NULL is never an array member
+				continue
+			}
+			if err = set.Add(item); err != nil {
+				return nil, fmt.Errorf("invalid %v operand: %w", label, err)
+			}
+		}
+	}
+	if single && set.Len() == 0 {
+		return nil, nil
+	}
+	return set, nil
+}
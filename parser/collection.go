@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/igo"
+	"github.com/viant/igo/exec"
+	"github.com/viant/igo/exec/expr"
+	sexpr "github.com/viant/sqlparser/expr"
+	"github.com/viant/structql/errs"
+	node2 "github.com/viant/structql/node"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// itemHolder represents collection item variable name
+const itemHolder = "item"
+
+// collection represents child slice predicate evaluated against slice items
+type collection struct {
+	xSlice    *xunsafe.Slice
+	isPointer bool
+	expr      *expr.Bool
+	exprSel   *exec.Selector
+}
+
+// isNil returns true for nil pointer item, nil items are skipped
+func (c *collection) isNil(slicePtr unsafe.Pointer, index int) bool {
+	return c.isPointer && xunsafe.DerefPointer(c.xSlice.PointerAt(slicePtr, uintptr(index))) == nil
+}
+
+// matches returns true if item matches collection criteria, or if criteria is not defined
+func (c *collection) matches(slicePtr unsafe.Pointer, index int) bool {
+	if c.expr == nil {
+		return true
+	}
+	state := c.expr.NewState()
+	c.exprSel.SetValue(state.Pointer(), c.xSlice.ValuePointerAt(slicePtr, index))
+	result := c.expr.ComputeWithState(state)
+	state.Release()
+	return result
+}
+
+// Any returns true if any slice item matches criteria
+func (c *collection) Any(value interface{}) bool {
+	slicePtr := xunsafe.AsPointer(value)
+	for i := 0; i < c.xSlice.Len(slicePtr); i++ {
+		if !c.isNil(slicePtr, i) && c.matches(slicePtr, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns true if all slice items match criteria, it returns true for empty slice
+func (c *collection) All(value interface{}) bool {
+	slicePtr := xunsafe.AsPointer(value)
+	for i := 0; i < c.xSlice.Len(slicePtr); i++ {
+		if !c.isNil(slicePtr, i) && !c.matches(slicePtr, i) {
+			return false
+		}
+	}
+	return true
+}
+
+var collectionFunctions = map[string]string{"ANY": "Any", "ALL": "All", "EXISTS": "Exists"}
+
+// collection translates ANY, ALL and EXISTS over child slice field i.e. ANY(Items[Price > 100])
+func (q *qualifier) collection(name string, call *sexpr.Call, negate bool) error {
+	aField, fType, criteria, err := q.collectionArgument(name, call)
+	if err != nil {
+		return err
+	}
+	aCollection := &collection{xSlice: xunsafe.NewSlice(fType), isPointer: fType.Elem().Kind() == reflect.Ptr}
+	if criteria != "" {
+		if err = q.compileCollection(aCollection, fType.Elem(), criteria); err != nil {
+			return fmt.Errorf("invalid %v criteria: %w", name, err)
+		}
+	}
+	q.binding.ContextField = aField
+	fn := aCollection.Any
+	if name == "ALL" {
+		fn = aCollection.All
+	}
+	var guards []string
+	value, err := q.operand(&sexpr.Ident{Name: aField.Name}, &guards)
+	if err != nil {
+		return err
+	}
+	q.writeGuards(guards)
+	q.output.WriteString(q.binding.AddFunction(collectionFunctions[name], fn) + "(" + value.expr + ")")
+	if negate { //igo does not support negated call expression
+		q.output.WriteString(" == false")
+	}
+	return nil
+}
+
+// collectionArgument returns collection slice field, dereferenced slice type and item criteria
+func (q *qualifier) collectionArgument(name string, call *sexpr.Call) (*xunsafe.Field, reflect.Type, string, error) {
+	if len(call.Args) != 1 {
+		return nil, nil, "", fmt.Errorf("invalid %v arguments count: %v", name, len(call.Args))
+	}
+	ident, ok := call.Args[0].(*sexpr.Ident)
+	if !ok {
+		return nil, nil, "", fmt.Errorf("invalid %v argument: %v, expected slice field", name, call.Raw)
+	}
+	fieldName, criteria := ident.Name, ""
+	if index := strings.Index(fieldName, "["); index != -1 {
+		fieldName, criteria = fieldName[:index], fieldName[index+1:len(fieldName)-1]
+	}
+	aField, err := q.field(fieldName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	fType := aField.Type
+	if fType.Kind() == reflect.Ptr {
+		fType = fType.Elem()
+	}
+	if fType.Kind() != reflect.Slice {
+		return nil, nil, "", fmt.Errorf("invalid %v argument: %v, expected slice but had %s", name, fieldName, aField.Type.String())
+	}
+	return aField, fType, strings.TrimSpace(criteria), nil
+}
+
+// collectionItemType returns collection item pointer type, criteria can only be used with struct items
+func collectionItemType(itemType reflect.Type) (reflect.Type, error) {
+	if itemType.Kind() != reflect.Ptr {
+		itemType = reflect.PtrTo(itemType)
+	}
+	if itemType.Elem().Kind() != reflect.Struct {
+		return nil, errs.UnsupportedType("unsupported item type: %s", itemType.Elem().String())
+	}
+	return itemType, nil
+}
+
+// compileCollection compiles collection item criteria in a dedicated scope sharing query binding
+func (q *qualifier) compileCollection(aCollection *collection, itemType reflect.Type, criteria string) error {
+	itemType, err := collectionItemType(itemType)
+	if err != nil {
+		return err
+	}
+	aNode, err := ParseCriteria("", []byte(criteria), 0)
+	if err != nil {
+		return err
+	}
+	output := strings.Builder{}
+	if err = qualifyExpr(itemHolder+".", aNode, node2.LookupFieldType(itemHolder, itemType), &output, q.params, false); err != nil {
+		return err
+	}
+	scope := igo.NewScope()
+	for _, fn := range q.binding.Functions {
+		scope.RegisterFunc(fn.Name, fn.Fn)
+	}
+	if aCollection.exprSel, err = scope.DefineVariable(itemHolder, itemType); err != nil {
+		return err
+	}
+	aCollection.expr, err = scope.BoolExpression(strings.TrimSpace(output.String()))
+	return err
+}
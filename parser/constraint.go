@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	node2 "github.com/viant/structql/node"
+	"strings"
+)
+
+// Constraint represents top level criteria conjunct comparing a field with constant values, it is used to select an index
+type Constraint struct {
+	Field  string
+	Op     string //=, IN, <, <=, >, >= or BETWEEN
+	Values []interface{}
+}
+
+var reversedComparison = map[string]string{"=": "=", "<": ">", "<=": ">=", ">": "<", ">=": "<="}
+
+// Constraints returns field constraints of top level AND conjuncts, literal and placeholder values are resolved,
+// positional placeholders are read from values starting at offset position, conjuncts with other predicates are skipped
+func Constraints(n node.Node, values *node2.Values, offset int) []*Constraint {
+	params := &node2.Values{Values: values.Values, Bindings: &node2.Binding{Count: offset}, Functions: values.Functions}
+	q := &qualifier{binding: params.Bindings, params: params}
+	var ret []*Constraint
+	for _, conjunct := range conjuncts(n, nil) {
+		position := q.binding.Count
+		if constraint := q.constraint(conjunct); constraint != nil {
+			ret = append(ret, constraint)
+		}
+		q.binding.Count = position + countPlaceholders(sqlparser.Stringify(conjunct))
+	}
+	return ret
+}
+
+// conjuncts returns top level AND operands
+func conjuncts(n node.Node, result []node.Node) []node.Node {
+	switch actual := n.(type) {
+	case *expr.Qualify:
+		return conjuncts(actual.X, result)
+	case *expr.Parenthesis:
+		if _, isList := actual.X.([]node.Node); !isList && actual.X != nil {
+			return conjuncts(actual.X, result)
+		}
+	case *expr.Binary:
+		if strings.ToUpper(actual.Op) == "AND" {
+			return conjuncts(actual.Y, conjuncts(actual.X, result))
+		}
+	}
+	return append(result, n)
+}
+
+func (q *qualifier) constraint(n node.Node) *Constraint {
+	binary, ok := n.(*expr.Binary)
+	if !ok {
+		return nil
+	}
+	op := strings.ToUpper(binary.Op)
+	x, y := binary.X, binary.Y
+	if _, ok := x.(*expr.Ident); !ok {
+		if op, ok = reversedComparison[op]; !ok {
+			return nil
+		}
+		x, y = y, x
+	}
+	ident, ok := x.(*expr.Ident)
+	if !ok {
+		return nil
+	}
+	var items []node.Node
+	switch op {
+	case "=", "<", "<=", ">", ">=":
+		items = []node.Node{y}
+	case "IN":
+		list, ok := y.(*expr.Parenthesis)
+		if !ok {
+			items = []node.Node{y}
+		} else if items, ok = list.X.([]node.Node); !ok {
+			items = []node.Node{list.X}
+		}
+	case "BETWEEN":
+		bounds, ok := y.(*expr.Range)
+		if !ok {
+			return nil
+		}
+		items = []node.Node{bounds.Min, bounds.Max}
+	default:
+		return nil
+	}
+	ret := &Constraint{Field: ident.Name, Op: op}
+	for _, item := range items {
+		values, err := q.listValues(item)
+		if err != nil {
+			return nil
+		}
+		for _, value := range values {
+			if isNil(value) {
+				if op != "IN" { //SQL: comparison with NULL is UNKNOWN
+					return nil
+				}
+				continue
+			}
+			ret.Values = append(ret.Values, value)
+		}
+	}
+	if op != "IN" && len(ret.Values) != len(items) {
+		return nil
+	}
+	return ret
+}
+
+// countPlaceholders returns positional placeholders count, quoted text is skipped
+func countPlaceholders(text string) int {
+	ret := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote != 0:
+			if c == quote {
+				if i+1 < len(text) && text[i+1] == quote {
+					i++
+				} else {
+					quote = 0
+				}
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			ret++
+		}
+	}
+	return ret
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/structql/node"
+	"testing"
+)
+
+func TestConstraints(t *testing.T) {
+	var testCases = []struct {
+		description string
+		expr        string
+		values      []interface{}
+		offset      int
+		expect      []*Constraint
+	}{
+		{
+			description: "comparisons",
+			expr:        "ID = 1 AND (Name IN ('a', ?) AND 10 > Price)",
+			values:      []interface{}{"b"},
+			expect: []*Constraint{
+				{Field: "ID", Op: "=", Values: []interface{}{1}},
+				{Field: "Name", Op: "IN", Values: []interface{}{"a", "b"}},
+				{Field: "Price", Op: "<", Values: []interface{}{10}},
+			},
+		},
+		{
+			description: "skipped conjuncts placeholders",
+			expr:        "LOWER(Name) = ? AND Note = 'what?' AND Price BETWEEN ? AND -1.5",
+			values:      []interface{}{"skip", "x", 2},
+			offset:      1,
+			expect: []*Constraint{
+				{Field: "Note", Op: "=", Values: []interface{}{"what?"}},
+				{Field: "Price", Op: "BETWEEN", Values: []interface{}{2, -1.5}},
+			},
+		},
+		{
+			description: "in list placeholder slice with null",
+			expr:        "ID IN (?, NULL)",
+			values:      []interface{}{[]int{1, 2}},
+			expect:      []*Constraint{{Field: "ID", Op: "IN", Values: []interface{}{1, 2}}},
+		},
+		{
+			description: "named parameter",
+			expr:        "ID >= @id AND Status = :status",
+			values:      []interface{}{map[string]interface{}{"id": 3, "status": "open"}},
+			expect: []*Constraint{
+				{Field: "ID", Op: ">=", Values: []interface{}{3}},
+				{Field: "Status", Op: "=", Values: []interface{}{"open"}},
+			},
+		},
+		{
+			description: "disjunction and null comparison",
+			expr:        "(ID = 1 OR ID = 2) AND Name = NULL AND NOT ID = 3",
+		},
+	}
+	for _, testCase := range testCases {
+		aNode, err := ParseCriteria("", []byte(testCase.expr), 0)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		values := &node.Values{Values: testCase.values, Bindings: &node.Binding{}}
+		assert.EqualValues(t, testCase.expect, Constraints(aNode, values, testCase.offset), testCase.description)
+	}
+}
@@ -0,0 +1,344 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/parsly"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
+	"strings"
+)
+
+// ParseCriteria parses SQL boolean criteria into expression tree honoring SQL operator precedence:
+// OR < AND < NOT < comparison, IS, IN, LIKE, BETWEEN < additive < multiplicative < unary
+func ParseCriteria(path string, criteria []byte, offset int) (node.Node, error) {
+	cursor := parsly.NewCursor(path, criteria, offset)
+	ret, err := parseOr(cursor)
+	if err != nil {
+		return nil, errs.Syntax(err)
+	}
+	if match := cursor.MatchAfterOptional(whitespaceMatcher, orKeywordMatcher, andKeywordMatcher); match.Code != parsly.EOF {
+		return nil, errs.Syntax(cursor.NewError(orKeywordMatcher, andKeywordMatcher))
+	}
+	return ret, nil
+}
+
+func parseOr(cursor *parsly.Cursor) (node.Node, error) {
+	left, err := parseAnd(cursor)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := cursor.Pos
+		if match := cursor.MatchAfterOptional(whitespaceMatcher, orKeywordMatcher); match.Code != orKeyword {
+			cursor.Pos = pos
+			return left, nil
+		}
+		right, err := parseAnd(cursor)
+		if err != nil {
+			return nil, err
+		}
+		left = &expr.Binary{X: left, Op: "OR", Y: right}
+	}
+}
+
+func parseAnd(cursor *parsly.Cursor) (node.Node, error) {
+	left, err := parseNot(cursor)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := cursor.Pos
+		if match := cursor.MatchAfterOptional(whitespaceMatcher, andKeywordMatcher); match.Code != andKeyword {
+			cursor.Pos = pos
+			return left, nil
+		}
+		right, err := parseNot(cursor)
+		if err != nil {
+			return nil, err
+		}
+		left = &expr.Binary{X: left, Op: "AND", Y: right}
+	}
+}
+
+func parseNot(cursor *parsly.Cursor) (node.Node, error) {
+	pos := cursor.Pos
+	if match := cursor.MatchAfterOptional(whitespaceMatcher, notKeywordMatcher); match.Code != notKeyword {
+		cursor.Pos = pos
+		return parsePredicate(cursor)
+	}
+	x, err := parseNot(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &expr.Unary{Op: "NOT", X: x}, nil
+}
+
+func parsePredicate(cursor *parsly.Cursor) (node.Node, error) {
+	left, err := parseAdditive(cursor)
+	if err != nil {
+		return nil, err
+	}
+	pos := cursor.Pos
+	match := cursor.MatchAfterOptional(whitespaceMatcher, comparisonOperatorMatcher, isNotKeywordMatcher, isKeywordMatcher, notInKeywordMatcher, inKeywordMatcher,
+		notLikeKeywordMatcher, likeKeywordMatcher, notILikeKeywordMatcher, iLikeKeywordMatcher,
+		notBetweenKeywordMatcher, betweenKeywordMatcher)
+	switch match.Code {
+	case comparisonOperator:
+		op := match.Text(cursor)
+		if op == "<>" {
+			op = "!="
+		}
+		right, err := parseAdditive(cursor)
+		if err != nil {
+			return nil, err
+		}
+		return &expr.Binary{X: left, Op: op, Y: right}, nil
+	case isKeyword, isNotKeyword:
+		op := "IS"
+		if match.Code == isNotKeyword {
+			op = "IS NOT"
+		}
+		if match = cursor.MatchAfterOptional(whitespaceMatcher, nullKeywordMatcher); match.Code != nullKeyword {
+			return nil, cursor.NewError(nullKeywordMatcher)
+		}
+		return &expr.Binary{X: left, Op: op, Y: expr.NewNullLiteral(match.Text(cursor))}, nil
+	case inKeyword, notInKeyword:
+		op := "IN"
+		if match.Code == notInKeyword {
+			op = "NOT IN"
+		}
+		right, err := parseInOperand(cursor)
+		if err != nil {
+			return nil, err
+		}
+		return &expr.Binary{X: left, Op: op, Y: right}, nil
+	case likeKeyword, notLikeKeyword, iLikeKeyword, notILikeKeyword:
+		op := likeOperators[match.Code]
+		right, err := parseLikePattern(cursor)
+		if err != nil {
+			return nil, err
+		}
+		return &expr.Binary{X: left, Op: op, Y: right}, nil
+	case betweenKeyword, notBetweenKeyword:
+		op := "BETWEEN"
+		if match.Code == notBetweenKeyword {
+			op = "NOT BETWEEN"
+		}
+		right, err := parseRange(cursor)
+		if err != nil {
+			return nil, err
+		}
+		return &expr.Binary{X: left, Op: op, Y: right}, nil
+	}
+	cursor.Pos = pos
+	return left, nil
+}
+
+// parseRange parses BETWEEN bounds: min AND max
+func parseRange(cursor *parsly.Cursor) (node.Node, error) {
+	min, err := parseAdditive(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if match := cursor.MatchAfterOptional(whitespaceMatcher, andKeywordMatcher); match.Code != andKeyword {
+		return nil, cursor.NewError(andKeywordMatcher)
+	}
+	max, err := parseAdditive(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &expr.Range{Min: min, Max: max}, nil
+}
+
+var likeOperators = map[int]string{
+	likeKeyword:     "LIKE",
+	notLikeKeyword:  "NOT LIKE",
+	iLikeKeyword:    "ILIKE",
+	notILikeKeyword: "NOT ILIKE",
+}
+
+// parseLikePattern parses LIKE pattern with optional ESCAPE clause represented as pattern ESCAPE char binary
+func parseLikePattern(cursor *parsly.Cursor) (node.Node, error) {
+	pattern, err := parseAdditive(cursor)
+	if err != nil {
+		return nil, err
+	}
+	pos := cursor.Pos
+	if match := cursor.MatchAfterOptional(whitespaceMatcher, escapeKeywordMatcher); match.Code != escapeKeyword {
+		cursor.Pos = pos
+		return pattern, nil
+	}
+	escape, err := parsePrimary(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &expr.Binary{X: pattern, Op: "ESCAPE", Y: escape}, nil
+}
+
+// parseInOperand parses IN operand, either parenthesized list or a single operand
+func parseInOperand(cursor *parsly.Cursor) (node.Node, error) {
+	pos := cursor.Pos
+	match := cursor.MatchAfterOptional(whitespaceMatcher, parenthesisOpenMatcher)
+	if match.Code != parenthesisOpen {
+		cursor.Pos = pos
+		return parsePrimary(cursor)
+	}
+	begin := cursor.Pos - 1
+	list, err := parseList(cursor)
+	if err != nil {
+		return nil, err
+	}
+	return &expr.Parenthesis{Raw: string(cursor.Input[begin:cursor.Pos]), X: list}, nil
+}
+
+// parseList parses comma separated expressions up to closing parenthesis
+func parseList(cursor *parsly.Cursor) ([]node.Node, error) {
+	var list []node.Node
+	pos := cursor.Pos
+	if match := cursor.MatchAfterOptional(whitespaceMatcher, parenthesisCloseMatcher); match.Code == parenthesisClose {
+		return list, nil
+	}
+	cursor.Pos = pos
+	for {
+		item, err := parseAdditive(cursor)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+		match := cursor.MatchAfterOptional(whitespaceMatcher, nextMatcher, parenthesisCloseMatcher)
+		switch match.Code {
+		case nextCode:
+			continue
+		case parenthesisClose:
+			return list, nil
+		default:
+			return nil, cursor.NewError(nextMatcher, parenthesisCloseMatcher)
+		}
+	}
+}
+
+func parseAdditive(cursor *parsly.Cursor) (node.Node, error) {
+	left, err := parseMultiplicative(cursor)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := cursor.Pos
+		match := cursor.MatchAfterOptional(whitespaceMatcher, additiveOperatorMatcher)
+		if match.Code != additiveOperator {
+			cursor.Pos = pos
+			return left, nil
+		}
+		op := match.Text(cursor)
+		right, err := parseMultiplicative(cursor)
+		if err != nil {
+			return nil, err
+		}
+		left = &expr.Binary{X: left, Op: op, Y: right}
+	}
+}
+
+func parseMultiplicative(cursor *parsly.Cursor) (node.Node, error) {
+	left, err := parseUnary(cursor)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		pos := cursor.Pos
+		match := cursor.MatchAfterOptional(whitespaceMatcher, multiplicativeOperatorMatcher)
+		if match.Code != multiplicativeOperator {
+			cursor.Pos = pos
+			return left, nil
+		}
+		op := match.Text(cursor)
+		right, err := parseUnary(cursor)
+		if err != nil {
+			return nil, err
+		}
+		left = &expr.Binary{X: left, Op: op, Y: right}
+	}
+}
+
+func parseUnary(cursor *parsly.Cursor) (node.Node, error) {
+	pos := cursor.Pos
+	match := cursor.MatchAfterOptional(whitespaceMatcher, numericLiteralMatcher, additiveOperatorMatcher)
+	switch match.Code {
+	case numericLiteral:
+		return newNumericLiteral(match.Text(cursor)), nil
+	case additiveOperator:
+		op := match.Text(cursor)
+		x, err := parseUnary(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return x, nil
+		}
+		return &expr.Unary{Op: op, X: x}, nil
+	}
+	cursor.Pos = pos
+	return parsePrimary(cursor)
+}
+
+func parsePrimary(cursor *parsly.Cursor) (node.Node, error) {
+	match := cursor.MatchAfterOptional(whitespaceMatcher,
+		parenthesisOpenMatcher,
+		singleQuotedStringMatcher,
+		doubleQuotedStringMatcher,
+		numericLiteralMatcher,
+		placeholderMatcher,
+		nullKeywordMatcher,
+		trueKeywordMatcher,
+		falseKeywordMatcher,
+		identifierMatcher,
+	)
+	switch match.Code {
+	case parenthesisOpen:
+		begin := cursor.Pos - 1
+		x, err := parseOr(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if match = cursor.MatchAfterOptional(whitespaceMatcher, parenthesisCloseMatcher); match.Code != parenthesisClose {
+			return nil, cursor.NewError(parenthesisCloseMatcher)
+		}
+		return &expr.Parenthesis{Raw: string(cursor.Input[begin:cursor.Pos]), X: x}, nil
+	case stringLiteral:
+		return expr.NewStringLiteral(match.Text(cursor)), nil
+	case numericLiteral:
+		return newNumericLiteral(match.Text(cursor)), nil
+	case placeholder:
+		return expr.NewPlaceholder(match.Text(cursor)), nil
+	case nullKeyword:
+		return expr.NewNullLiteral(match.Text(cursor)), nil
+	case boolLiteral:
+		return expr.NewBoolLiteral(strings.ToLower(match.Text(cursor))), nil
+	case identifier:
+		name := match.Text(cursor)
+		if match = cursor.MatchOne(conditionalBlockMatcher); match.Code == conditionalBlock { //collection criteria i.e. Items[Price > 100]
+			return &expr.Ident{Name: name + match.Text(cursor)}, nil
+		}
+		pos := cursor.Pos
+		if match = cursor.MatchAfterOptional(whitespaceMatcher, parenthesisOpenMatcher); match.Code != parenthesisOpen {
+			cursor.Pos = pos
+			return &expr.Ident{Name: name}, nil
+		}
+		begin := cursor.Pos - 1
+		args, err := parseList(cursor)
+		if err != nil {
+			return nil, err
+		}
+		return &expr.Call{X: &expr.Ident{Name: name}, Args: args, Raw: string(cursor.Input[begin:cursor.Pos])}, nil
+	case parsly.EOF:
+		return nil, fmt.Errorf("unexpected end of criteria, expected operand")
+	}
+	return nil, cursor.NewError(identifierMatcher, singleQuotedStringMatcher, numericLiteralMatcher, placeholderMatcher, parenthesisOpenMatcher)
+}
+
+func newNumericLiteral(text string) *expr.Literal {
+	if strings.ContainsAny(text, ".eE") {
+		return expr.NewNumericLiteral(text)
+	}
+	return expr.NewIntLiteral(text)
+}
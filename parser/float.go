@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"github.com/viant/igo/exec"
+	"unsafe"
+)
+
+// floatPredicate represents float64 comparison callable by igo, igo float64 ordering operators compare values as int
+type floatPredicate func(x, y float64) bool
+
+// Call calls the predicate with computed arguments
+func (p floatPredicate) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	result := p(*(*float64)(args[0].Compute(ptr)), *(*float64)(args[1].Compute(ptr)))
+	return unsafe.Pointer(&result)
+}
+
+var floatComparisons = map[string]struct {
+	name string
+	fn   floatPredicate
+}{
+	"<":  {"FloatLt", func(x, y float64) bool { return x < y }},
+	"<=": {"FloatLe", func(x, y float64) bool { return x <= y }},
+	">":  {"FloatGt", func(x, y float64) bool { return x > y }},
+	">=": {"FloatGe", func(x, y float64) bool { return x >= y }},
+}
+
+// floatQuo divides float64 values, igo float64 division operator divides values as int
+func floatQuo(x, y float64) float64 {
+	return x / y
+}
@@ -0,0 +1,159 @@
+package in
+
+import (
+	"github.com/viant/structql/errs"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Set represents a set of comparable values of the same type
+type Set struct {
+	rType reflect.Type
+	index map[interface{}]bool
+}
+
+// Add adds value to the set, value is converted to the set type
+func (s *Set) Add(value interface{}) error {
+	converted, err := convert(value, s.rType)
+	if err != nil {
+		return err
+	}
+	s.index[key(converted)] = true
+	return nil
+}
+
+// Len returns set size
+func (s *Set) Len() int {
+	return len(s.index)
+}
+
+// Has returns true if value (or value pointer) is in the set, nil value is never in the set
+func (s *Set) Has(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if reflect.TypeOf(value) != s.rType {
+		converted, err := convert(value, s.rType)
+		if err != nil {
+			return false
+		}
+		value = converted
+	}
+	return s.index[key(value)]
+}
+
+// Intersects returns true if any item of supplied slice (or slice pointer) is in the set
+func (s *Set) Intersects(values interface{}) bool {
+	switch actual := values.(type) {
+	case []string:
+		if s.rType.Kind() == reflect.String {
+			for _, item := range actual {
+				if s.Has(item) {
+					return true
+				}
+			}
+			return false
+		}
+	case []int:
+		if s.rType.Kind() == reflect.Int {
+			for _, item := range actual {
+				if s.Has(item) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	rValue := reflect.ValueOf(values)
+	for rValue.Kind() == reflect.Ptr && !rValue.IsNil() {
+		rValue = rValue.Elem()
+	}
+	if rValue.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < rValue.Len(); i++ {
+		if s.Has(rValue.Index(i).Interface()) {
+			return true
+		}
+	}
+	return false
+}
+
+func key(value interface{}) interface{} {
+	if ts, ok := value.(time.Time); ok {
+		return ts.Round(0).UTC()
+	}
+	return value
+}
+
+// convert converts value to the target type
+func convert(value interface{}, target reflect.Type) (interface{}, error) {
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Ptr {
+		if rValue.IsNil() {
+			return nil, errs.Conversion("invalid nil value for %v", target.String())
+		}
+		rValue = rValue.Elem()
+	}
+	if !rValue.IsValid() {
+		return nil, errs.Conversion("invalid nil value for %v", target.String())
+	}
+	if rValue.Type() == target {
+		return rValue.Interface(), nil
+	}
+	if rValue.Kind() == reflect.String && target.Kind() != reflect.String {
+		return parse(rValue.String(), target)
+	}
+	if isNumber(rValue.Kind()) == isNumber(target.Kind()) && rValue.CanConvert(target) {
+		return rValue.Convert(target).Interface(), nil
+	}
+	return nil, errs.Conversion("unable to convert %T to %v", value, target.String())
+}
+
+// parse parses text into target type
+func parse(text string, target reflect.Type) (interface{}, error) {
+	var value interface{}
+	var err error
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err = strconv.ParseInt(text, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err = strconv.ParseUint(text, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		value, err = strconv.ParseFloat(text, 64)
+	case reflect.Bool:
+		value, err = strconv.ParseBool(text)
+	case reflect.Struct:
+		if !timeType.ConvertibleTo(target) {
+			return nil, errs.Conversion("unable to convert %q to %v", text, target.String())
+		}
+		value, err = time.Parse(time.RFC3339Nano, text)
+	default:
+		return nil, errs.Conversion("unable to convert %q to %v", text, target.String())
+	}
+	if err != nil {
+		return nil, errs.Conversion("unable to convert %q to %v: %w", text, target.String(), err)
+	}
+	return reflect.ValueOf(value).Convert(target).Interface(), nil
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// NewSet returns a set for supplied comparable type
+func NewSet(rType reflect.Type) (*Set, error) {
+	if !rType.Comparable() {
+		return nil, errs.UnsupportedType("unsupported type: %v for in operator", rType.String())
+	}
+	return &Set{rType: rType, index: make(map[interface{}]bool)}, nil
+}
@@ -0,0 +1,97 @@
+package in
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type status int
+
+func TestSet_Has(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var testCases = []struct {
+		description string
+		rType       reflect.Type
+		values      []interface{}
+		has         []interface{}
+		hasNot      []interface{}
+	}{
+		{
+			description: "ints",
+			rType:       reflect.TypeOf(0),
+			values:      []interface{}{1, "2", int64(3)},
+			has:         []interface{}{1, 2, 3, &[]int{3}[0]},
+			hasNot:      []interface{}{4, (*int)(nil), nil},
+		},
+		{
+			description: "named int",
+			rType:       reflect.TypeOf(status(0)),
+			values:      []interface{}{1, 2},
+			has:         []interface{}{status(1), 2},
+			hasNot:      []interface{}{status(3)},
+		},
+		{
+			description: "floats",
+			rType:       reflect.TypeOf(0.0),
+			values:      []interface{}{1, 2.5},
+			has:         []interface{}{1.0, 2.5},
+			hasNot:      []interface{}{2.0},
+		},
+		{
+			description: "uints",
+			rType:       reflect.TypeOf(uint64(0)),
+			values:      []interface{}{1, "2"},
+			has:         []interface{}{uint64(1), uint64(2)},
+			hasNot:      []interface{}{uint64(3)},
+		},
+		{
+			description: "bools",
+			rType:       reflect.TypeOf(true),
+			values:      []interface{}{true},
+			has:         []interface{}{true},
+			hasNot:      []interface{}{false},
+		},
+		{
+			description: "strings",
+			rType:       reflect.TypeOf(""),
+			values:      []interface{}{"a", &[]string{"b"}[0]},
+			has:         []interface{}{"a", "b"},
+			hasNot:      []interface{}{"c", 1},
+		},
+		{
+			description: "time",
+			rType:       reflect.TypeOf(time.Time{}),
+			values:      []interface{}{ts, "2024-01-03T00:00:00Z"},
+			has:         []interface{}{ts.In(time.FixedZone("X", 3600)), time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+			hasNot:      []interface{}{ts.Add(time.Second)},
+		},
+	}
+	for _, testCase := range testCases {
+		set, err := NewSet(testCase.rType)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		for _, value := range testCase.values {
+			assert.Nil(t, set.Add(value), testCase.description)
+		}
+		for _, value := range testCase.has {
+			assert.True(t, set.Has(value), testCase.description)
+		}
+		for _, value := range testCase.hasNot {
+			assert.False(t, set.Has(value), testCase.description)
+		}
+	}
+}
+
+func TestSet_Add(t *testing.T) {
+	set, err := NewSet(reflect.TypeOf(""))
+	assert.Nil(t, err)
+	assert.NotNil(t, set.Add(1))
+	set, err = NewSet(reflect.TypeOf(0))
+	assert.Nil(t, err)
+	assert.NotNil(t, set.Add("abc"))
+	_, err = NewSet(reflect.TypeOf([]int{}))
+	assert.NotNil(t, err)
+}
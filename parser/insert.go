@@ -0,0 +1,157 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
+	"strings"
+)
+
+// Insert represents INSERT statement, inserted elements are supplied by VALUES rows or SELECT query
+type Insert struct {
+	Target  string //selector path
+	Columns []string
+	Rows    [][]node.Node
+	Query   string //INSERT ... SELECT query
+}
+
+var (
+	insertKeyword = newKeyword("insert")
+	intoKeyword   = newKeyword("into")
+	valuesKeyword = newKeyword("values")
+	selectKeyword = newKeyword("select")
+)
+
+// ParseInsert parses INSERT INTO target [(columns)] VALUES (...)[, (...)] or INSERT INTO target [(columns)] SELECT ... statement,
+// row values are parsed with criteria parser
+func ParseInsert(SQL string) (*Insert, error) {
+	input := []byte(SQL)
+	pos := skipWhitespace(input, 0)
+	for _, expect := range []*keyword{insertKeyword, intoKeyword} {
+		matched := expect.match(input, pos)
+		if matched == 0 {
+			return nil, errs.Syntaxf(pos, "invalid insert statement, expected: %s at pos: %d", expect.words[0], pos)
+		}
+		pos = skipWhitespace(input, pos+matched)
+	}
+	ret := &Insert{}
+	begin := pos
+	if pos < len(input) && input[pos] == '`' {
+		pos = skipQuoted(input, pos) + 1
+	} else {
+		for pos < len(input) && !isWhitespace(input[pos]) && input[pos] != '(' {
+			pos++
+		}
+	}
+	if pos > len(input) {
+		return nil, errs.Syntaxf(begin, "invalid insert target, unterminated quote at pos: %d", begin)
+	}
+	if ret.Target = strings.Trim(SQL[begin:pos], "`"); ret.Target == "" {
+		return nil, errs.Syntaxf(begin, "invalid insert statement, expected: target at pos: %d", begin)
+	}
+	pos = skipWhitespace(input, pos)
+	if pos < len(input) && input[pos] == '(' {
+		end := closingParenthesis(input, pos)
+		if end == -1 {
+			return nil, errs.Syntaxf(pos, "invalid insert columns, unterminated list at pos: %d", pos)
+		}
+		for _, column := range strings.Split(SQL[pos+1:end], ",") {
+			if column = strings.Trim(strings.TrimSpace(column), "`"); column == "" {
+				return nil, errs.Syntaxf(pos, "invalid insert columns, empty column at pos: %d", pos)
+			}
+			ret.Columns = append(ret.Columns, column)
+		}
+		pos = skipWhitespace(input, end+1)
+	}
+	if selectKeyword.match(input, pos) > 0 {
+		ret.Query = SQL[pos:]
+		return ret, nil
+	}
+	matched := valuesKeyword.match(input, pos)
+	if matched == 0 {
+		return nil, errs.Syntaxf(pos, "invalid insert statement, expected: VALUES or SELECT at pos: %d", pos)
+	}
+	pos = skipWhitespace(input, pos+matched)
+	for {
+		if pos >= len(input) || input[pos] != '(' {
+			return nil, errs.Syntaxf(pos, "invalid insert values, expected: ( at pos: %d", pos)
+		}
+		end := closingParenthesis(input, pos)
+		if end == -1 {
+			return nil, errs.Syntaxf(pos, "invalid insert values, unterminated list at pos: %d", pos)
+		}
+		row, err := parseRow(input, pos+1, end)
+		if err != nil {
+			return nil, err
+		}
+		ret.Rows = append(ret.Rows, row)
+		if pos = skipWhitespace(input, end+1); pos < len(input) && input[pos] == ',' {
+			pos = skipWhitespace(input, pos+1)
+			continue
+		}
+		break
+	}
+	if pos < len(input) && input[pos] == ';' {
+		pos = skipWhitespace(input, pos+1)
+	}
+	if pos < len(input) {
+		return nil, errs.Syntaxf(pos, "invalid insert statement, unexpected token at pos: %d", pos)
+	}
+	return ret, nil
+}
+
+// parseRow parses comma separated VALUES row expressions
+func parseRow(input []byte, begin, end int) ([]node.Node, error) {
+	var ret []node.Node
+	from, depth := begin, 0
+	for i := begin; i <= end; i++ {
+		if i < end {
+			switch input[i] {
+			case '\'', '"', '`':
+				i = skipQuoted(input, i)
+				continue
+			case '(', '[':
+				depth++
+				continue
+			case ')', ']':
+				depth--
+				continue
+			}
+			if input[i] != ',' || depth > 0 {
+				continue
+			}
+		}
+		item, err := ParseCriteria("", input[from:i], from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid insert value: %w", err)
+		}
+		ret = append(ret, item)
+		from = i + 1
+	}
+	return ret, nil
+}
+
+// closingParenthesis returns position of parenthesis closing the one at pos, or -1
+func closingParenthesis(input []byte, pos int) int {
+	depth := 0
+	for i := pos; i < len(input); i++ {
+		switch input[i] {
+		case '\'', '"', '`':
+			i = skipQuoted(input, i)
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func skipWhitespace(input []byte, pos int) int {
+	for pos < len(input) && isWhitespace(input[pos]) {
+		pos++
+	}
+	return pos
+}
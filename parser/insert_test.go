@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/sqlparser"
+	"testing"
+)
+
+func TestParseInsert(t *testing.T) {
+	var testCases = []struct {
+		description   string
+		SQL           string
+		expectTarget  string
+		expectColumns []string
+		expectRows    [][]string
+		expectQuery   string
+		hasError      bool
+	}{
+		{
+			description:   "values rows",
+			SQL:           "insert into `/Vendors[ID=?]/Products` (ID, `Name`) VALUES (?, 'a, (b)'), (1 + 2, CONCAT('x', ?));",
+			expectTarget:  "/Vendors[ID=?]/Products",
+			expectColumns: []string{"ID", "Name"},
+			expectRows:    [][]string{{"?", "'a, (b)'"}, {"1 + 2", "CONCAT('x', ?)"}},
+		},
+		{
+			description:  "select",
+			SQL:          "INSERT INTO /Archive SELECT ID FROM `/` WHERE ID > 1",
+			expectTarget: "/Archive",
+			expectQuery:  "SELECT ID FROM `/` WHERE ID > 1",
+		},
+		{
+			description: "missing values",
+			SQL:         "INSERT INTO `/Archive` (ID)",
+			hasError:    true,
+		},
+		{
+			description: "trailing token",
+			SQL:         "INSERT INTO `/Archive` VALUES (1) LIMIT 1",
+			hasError:    true,
+		},
+		{
+			description: "empty value",
+			SQL:         "INSERT INTO `/Archive` VALUES (1, )",
+			hasError:    true,
+		},
+	}
+	for _, testCase := range testCases {
+		actual, err := ParseInsert(testCase.SQL)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectTarget, actual.Target, testCase.description)
+		assert.EqualValues(t, testCase.expectColumns, actual.Columns, testCase.description)
+		assert.EqualValues(t, testCase.expectQuery, actual.Query, testCase.description)
+		var rows [][]string
+		for _, row := range actual.Rows {
+			var values []string
+			for _, item := range row {
+				values = append(values, sqlparser.Stringify(item))
+			}
+			rows = append(rows, values)
+		}
+		assert.EqualValues(t, testCase.expectRows, rows, testCase.description)
+	}
+}
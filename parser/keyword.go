@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"bytes"
+	"github.com/viant/parsly"
+	"strings"
+)
+
+// keyword represents case-insensitive (possibly multi word) keyword matcher respecting word boundaries
+type keyword struct {
+	words [][]byte
+}
+
+// Match matches a keyword
+func (k *keyword) Match(cursor *parsly.Cursor) int {
+	return k.match(cursor.Input, cursor.Pos)
+}
+
+func (k *keyword) match(input []byte, begin int) int {
+	pos := begin
+	size := len(input)
+	for i, word := range k.words {
+		if i > 0 {
+			spaces := 0
+			for pos < size && isWhitespace(input[pos]) {
+				pos++
+				spaces++
+			}
+			if spaces == 0 {
+				return 0
+			}
+		}
+		if pos+len(word) > size || !bytes.EqualFold(input[pos:pos+len(word)], word) {
+			return 0
+		}
+		pos += len(word)
+	}
+	if pos < size && isIdentByte(input[pos]) {
+		return 0
+	}
+	return pos - begin
+}
+
+func isWhitespace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+func isIdentByte(b byte) bool {
+	return IsLetter(b) || (b >= '0' && b <= '9') || b == '_'
+}
+
+// newKeyword creates a keyword matcher, words are separated by a space
+func newKeyword(text string) *keyword {
+	ret := &keyword{}
+	for _, word := range strings.Fields(text) {
+		ret.words = append(ret.words, []byte(word))
+	}
+	return ret
+}
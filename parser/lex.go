@@ -10,9 +10,64 @@ const (
 	selectorSeparator
 	identifier
 	conditionalBlock
+	parenthesisOpen
+	parenthesisClose
+	nextCode
+	orKeyword
+	andKeyword
+	notKeyword
+	isNotKeyword
+	isKeyword
+	notInKeyword
+	inKeyword
+	notLikeKeyword
+	likeKeyword
+	notILikeKeyword
+	iLikeKeyword
+	escapeKeyword
+	notBetweenKeyword
+	betweenKeyword
+	nullKeyword
+	boolLiteral
+	comparisonOperator
+	additiveOperator
+	multiplicativeOperator
+	stringLiteral
+	numericLiteral
+	placeholder
 )
 
 var whitespaceMatcher = parsly.NewToken(whitespaceCode, "whitespace", pmatcher.NewWhiteSpace())
 var selectorSeparatorMatcher = parsly.NewToken(selectorSeparator, "/", pmatcher.NewByte('/'))
 var identifierMatcher = parsly.NewToken(identifier, "Ident", NewIdentity())
-var conditionalBlockMatcher = parsly.NewToken(conditionalBlock, "[]", pmatcher.NewBlock('[', ']', '\\'))
+var conditionalBlockMatcher = parsly.NewToken(conditionalBlock, "[]", pmatcher.NewBlock('[', ']', 0)) //no escape, quoted literal doubles the quote
+
+var parenthesisOpenMatcher = parsly.NewToken(parenthesisOpen, "(", pmatcher.NewByte('('))
+var parenthesisCloseMatcher = parsly.NewToken(parenthesisClose, ")", pmatcher.NewByte(')'))
+var nextMatcher = parsly.NewToken(nextCode, ",", pmatcher.NewByte(','))
+var orKeywordMatcher = parsly.NewToken(orKeyword, "OR", newKeyword("or"))
+var andKeywordMatcher = parsly.NewToken(andKeyword, "AND", newKeyword("and"))
+var notKeywordMatcher = parsly.NewToken(notKeyword, "NOT", newKeyword("not"))
+var isNotKeywordMatcher = parsly.NewToken(isNotKeyword, "IS NOT", newKeyword("is not"))
+var isKeywordMatcher = parsly.NewToken(isKeyword, "IS", newKeyword("is"))
+var notInKeywordMatcher = parsly.NewToken(notInKeyword, "NOT IN", newKeyword("not in"))
+var inKeywordMatcher = parsly.NewToken(inKeyword, "IN", newKeyword("in"))
+var notLikeKeywordMatcher = parsly.NewToken(notLikeKeyword, "NOT LIKE", newKeyword("not like"))
+var likeKeywordMatcher = parsly.NewToken(likeKeyword, "LIKE", newKeyword("like"))
+var notILikeKeywordMatcher = parsly.NewToken(notILikeKeyword, "NOT ILIKE", newKeyword("not ilike"))
+var iLikeKeywordMatcher = parsly.NewToken(iLikeKeyword, "ILIKE", newKeyword("ilike"))
+var escapeKeywordMatcher = parsly.NewToken(escapeKeyword, "ESCAPE", newKeyword("escape"))
+var notBetweenKeywordMatcher = parsly.NewToken(notBetweenKeyword, "NOT BETWEEN", newKeyword("not between"))
+var betweenKeywordMatcher = parsly.NewToken(betweenKeyword, "BETWEEN", newKeyword("between"))
+var nullKeywordMatcher = parsly.NewToken(nullKeyword, "NULL", newKeyword("null"))
+var trueKeywordMatcher = parsly.NewToken(boolLiteral, "TRUE", newKeyword("true"))
+var falseKeywordMatcher = parsly.NewToken(boolLiteral, "FALSE", newKeyword("false"))
+var comparisonOperatorMatcher = parsly.NewToken(comparisonOperator, "=|!=|<>|<|<=|>|>=", pmatcher.NewSet([]string{"<>", "!=", "<=", ">=", "=", "<", ">"}))
+var additiveOperatorMatcher = parsly.NewToken(additiveOperator, "+|-", pmatcher.NewSet([]string{"+", "-"}))
+var multiplicativeOperatorMatcher = parsly.NewToken(multiplicativeOperator, "*|/|%", pmatcher.NewSet([]string{"*", "/", "%"}))
+
+// string literals escape quote by doubling it (SQL standard), backslash is a regular character
+var singleQuotedStringMatcher = parsly.NewToken(stringLiteral, `'...'`, pmatcher.NewByteQuote('\'', '\''))
+var doubleQuotedStringMatcher = parsly.NewToken(stringLiteral, `"..."`, pmatcher.NewByteQuote('"', '"'))
+var numericLiteralMatcher = parsly.NewToken(numericLiteral, "NUMERIC", pmatcher.NewNumber())
+var placeholderMatcher = parsly.NewToken(placeholder, "?|@name|:name", newPlaceholderToken())
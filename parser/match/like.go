@@ -0,0 +1,143 @@
+package match
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	literalRune = iota
+	anyRune     // _
+	anyRunes    // %
+)
+
+type patternRune struct {
+	kind  int
+	value rune
+}
+
+// NewLike returns SQL LIKE pattern matcher, where '%' matches any sequence and '_' any single character,
+// escape (if not zero) makes following wildcard literal
+func NewLike(pattern string, escape rune, caseInsensitive bool) (Matcher, error) {
+	runes, err := parseLike(pattern, escape, caseInsensitive)
+	if err != nil {
+		return nil, err
+	}
+	ret := newLike(runes)
+	if !caseInsensitive {
+		return ret, nil
+	}
+	return func(value string) bool {
+		return ret(strings.ToLower(value))
+	}, nil
+}
+
+func parseLike(pattern string, escape rune, caseInsensitive bool) ([]patternRune, error) {
+	var ret []patternRune
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+		case escape != 0 && r == escape:
+			escaped = true
+			continue
+		case r == '%':
+			if len(ret) > 0 && ret[len(ret)-1].kind == anyRunes {
+				continue
+			}
+			ret = append(ret, patternRune{kind: anyRunes})
+			continue
+		case r == '_':
+			ret = append(ret, patternRune{kind: anyRune})
+			continue
+		}
+		if caseInsensitive {
+			r = unicode.ToLower(r)
+		}
+		ret = append(ret, patternRune{kind: literalRune, value: r})
+	}
+	if escaped {
+		return nil, fmt.Errorf("invalid LIKE pattern: %v, missing character after escape", pattern)
+	}
+	return ret, nil
+}
+
+// newLike returns matcher, common patterns are matched with strings functions
+func newLike(runes []patternRune) Matcher {
+	literal := func(runes []patternRune) (string, bool) {
+		text := strings.Builder{}
+		for _, r := range runes {
+			if r.kind != literalRune {
+				return "", false
+			}
+			text.WriteRune(r.value)
+		}
+		return text.String(), true
+	}
+	if text, ok := literal(runes); ok {
+		return func(value string) bool {
+			return value == text
+		}
+	}
+	size := len(runes)
+	leading, trailing := runes[0].kind == anyRunes, runes[size-1].kind == anyRunes
+	switch {
+	case size == 1 && leading:
+		return func(value string) bool {
+			return true
+		}
+	case leading && trailing:
+		if text, ok := literal(runes[1 : size-1]); ok {
+			return NewContains(text)
+		}
+	case leading:
+		if text, ok := literal(runes[1:]); ok {
+			return NewSuffix(text)
+		}
+	case trailing:
+		if text, ok := literal(runes[:size-1]); ok {
+			return NewPrefix(text)
+		}
+	}
+	return func(value string) bool {
+		return matchLike(runes, []rune(value))
+	}
+}
+
+// matchLike matches pattern with backtracking to the last '%' only
+func matchLike(pattern []patternRune, value []rune) bool {
+	p, v := 0, 0
+	star, mark := -1, 0
+	for v < len(value) {
+		if p < len(pattern) {
+			switch r := pattern[p]; r.kind {
+			case anyRunes:
+				star, mark = p, v
+				p++
+				continue
+			case anyRune:
+				p++
+				v++
+				continue
+			default:
+				if r.value == value[v] {
+					p++
+					v++
+					continue
+				}
+			}
+		}
+		if star == -1 {
+			return false
+		}
+		p = star + 1
+		mark++
+		v = mark
+	}
+	for p < len(pattern) && pattern[p].kind == anyRunes {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,53 @@
+package match
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewLike(t *testing.T) {
+	var testCases = []struct {
+		description     string
+		pattern         string
+		escape          rune
+		caseInsensitive bool
+		value           string
+		expect          bool
+	}{
+		{description: "exact", pattern: "abc", value: "abc", expect: true},
+		{description: "exact mismatch", pattern: "abc", value: "abcd", expect: false},
+		{description: "empty", pattern: "", value: "", expect: true},
+		{description: "prefix", pattern: "ab%", value: "abc", expect: true},
+		{description: "suffix", pattern: "%bc", value: "abc", expect: true},
+		{description: "contains", pattern: "%b%", value: "abc", expect: true},
+		{description: "any", pattern: "%%", value: "", expect: true},
+		{description: "single char", pattern: "a_c", value: "abc", expect: true},
+		{description: "single char mismatch", pattern: "a_c", value: "ac", expect: false},
+		{description: "multi byte", pattern: "ż_ł%", value: "żółw", expect: true},
+		{description: "backtracking", pattern: "%a%b_c", value: "aaxbbbxc", expect: true},
+		{description: "backtracking mismatch", pattern: "%a%b_c", value: "aaxbbbx", expect: false},
+		{description: "escaped wildcard", pattern: `a\%`, escape: '\\', value: "a%", expect: true},
+		{description: "escaped wildcard mismatch", pattern: `a\%`, escape: '\\', value: "ab", expect: false},
+		{description: "escaped underscore", pattern: "!_%", escape: '!', value: "_x", expect: true},
+		{description: "case insensitive", pattern: "A%c", caseInsensitive: true, value: "abC", expect: true},
+		{description: "case sensitive", pattern: "A%c", value: "abc", expect: false},
+	}
+	for _, testCase := range testCases {
+		matcher, err := NewLike(testCase.pattern, testCase.escape, testCase.caseInsensitive)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.Equal(t, testCase.expect, matcher(testCase.value), testCase.description)
+	}
+	_, err := NewLike(`a\`, '\\', false)
+	assert.NotNil(t, err)
+}
+
+func TestMatcher_Match(t *testing.T) {
+	matcher := NewPrefix("a")
+	value := "abc"
+	assert.True(t, matcher.Match(value))
+	assert.True(t, matcher.Match(&value))
+	assert.False(t, matcher.Match((*string)(nil)))
+	assert.False(t, matcher.Match(1))
+}
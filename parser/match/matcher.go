@@ -0,0 +1,41 @@
+package match
+
+import "strings"
+
+// Matcher represents compiled string predicate
+type Matcher func(value string) bool
+
+// Match matches string or *string value, nil pointer never matches
+func (m Matcher) Match(value interface{}) bool {
+	switch actual := value.(type) {
+	case string:
+		return m(actual)
+	case *string:
+		if actual == nil {
+			return false
+		}
+		return m(*actual)
+	}
+	return false
+}
+
+// NewPrefix returns STARTS_WITH matcher
+func NewPrefix(prefix string) Matcher {
+	return func(value string) bool {
+		return strings.HasPrefix(value, prefix)
+	}
+}
+
+// NewSuffix returns ENDS_WITH matcher
+func NewSuffix(suffix string) Matcher {
+	return func(value string) bool {
+		return strings.HasSuffix(value, suffix)
+	}
+}
+
+// NewContains returns CONTAINS matcher
+func NewContains(fragment string) Matcher {
+	return func(value string) bool {
+		return strings.Contains(value, fragment)
+	}
+}
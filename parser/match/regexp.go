@@ -0,0 +1,26 @@
+package match
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// NewRegexp returns regular expression matcher, supported flags: 'i' case-insensitive, 's' dot matches new line,
+// 'm' multi line mode
+func NewRegexp(expr string, flags string) (Matcher, error) {
+	if flags != "" {
+		for _, flag := range flags {
+			switch flag {
+			case 'i', 's', 'm':
+			default:
+				return nil, fmt.Errorf("unsupported regexp flag: %q", flag)
+			}
+		}
+		expr = "(?" + flags + ")" + expr
+	}
+	compiled, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.MatchString, nil
+}
@@ -0,0 +1,443 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	node2 "github.com/viant/structql/node"
+	"github.com/viant/structql/parser/match"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// Predicate represents natively compiled criteria evaluated against a struct pointer
+type Predicate func(ptr unsafe.Pointer) bool
+
+// AsPredicate compiles SQL criteria into closures over xunsafe field accessors,
+// it is an alternative to igo evaluated go expression returned by AsBinaryGoExpr
+func AsPredicate(n node.Node, lookup func(name string) *xunsafe.Field, values *node2.Values) (Predicate, error) {
+	c := &compiler{qualifier: &qualifier{lookup: lookup, binding: values.Bindings, params: values}}
+	return c.predicate(n, false)
+}
+
+// Expression represents natively compiled value expression evaluated against a struct pointer, it returns false for NULL
+type Expression func(ptr unsafe.Pointer) (interface{}, bool)
+
+// AsExpression compiles SQL value expression: field, literal, placeholder, arithmetic or scalar function call,
+// field value keeps its original type, other values use int, float64, string, bool or time.Time type.
+// It also returns expression result type, nil for NULL and interface{} when the type is known only at runtime.
+func AsExpression(n node.Node, lookup func(name string) *xunsafe.Field, values *node2.Values) (Expression, reflect.Type, error) {
+	c := &compiler{qualifier: &qualifier{lookup: lookup, binding: values.Bindings, params: values}}
+	ret, err := c.value(n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ret.originalFn(), ret.resultType(), nil
+}
+
+// compiler compiles SQL criteria into native predicate, compile time values (placeholders, IN lists, patterns)
+// are resolved by the qualifier, negation is pushed down in the same way
+type compiler struct {
+	*qualifier
+}
+
+func alwaysFalse(ptr unsafe.Pointer) bool {
+	return false
+}
+
+func constantPredicate(result bool) Predicate {
+	return func(ptr unsafe.Pointer) bool { return result }
+}
+
+func (c *compiler) predicate(n node.Node, negate bool) (Predicate, error) {
+	switch actual := n.(type) {
+	case *expr.Qualify:
+		return c.predicate(actual.X, negate)
+	case *expr.Parenthesis:
+		if _, isList := actual.X.([]node.Node); isList || actual.X == nil {
+			return nil, fmt.Errorf("unsupported predicate: %v", actual.Raw)
+		}
+		return c.predicate(actual.X, negate)
+	case *expr.Unary:
+		if strings.ToUpper(actual.Op) != "NOT" {
+			return nil, fmt.Errorf("unsupported predicate: %v", sqlparser.Stringify(actual))
+		}
+		return c.predicate(actual.X, !negate)
+	case *expr.Binary:
+		switch op := strings.ToUpper(actual.Op); op {
+		case "AND", "OR":
+			x, err := c.predicate(actual.X, negate)
+			if err != nil {
+				return nil, err
+			}
+			y, err := c.predicate(actual.Y, negate)
+			if err != nil {
+				return nil, err
+			}
+			if (op == "AND") != negate {
+				return func(ptr unsafe.Pointer) bool { return x(ptr) && y(ptr) }, nil
+			}
+			return func(ptr unsafe.Pointer) bool { return x(ptr) || y(ptr) }, nil
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			return c.comparison(actual, negate)
+		case "IS", "IS NOT":
+			return c.isNull(actual, negate)
+		case "IN", "NOT IN":
+			return c.in(actual, negate)
+		case "LIKE", "NOT LIKE", "ILIKE", "NOT ILIKE":
+			return c.like(actual, negate)
+		case "BETWEEN", "NOT BETWEEN":
+			return c.between(actual, negate)
+		default:
+			return nil, fmt.Errorf("unsupported operator: %v", actual.Op)
+		}
+	case *expr.Call:
+		return c.call(actual, negate)
+	case *expr.Ident:
+		aField, err := c.field(actual.Name)
+		if err != nil {
+			return nil, err
+		}
+		flag := fieldValue(aField)
+		if flag.kind != boolOperand {
+			return nil, fmt.Errorf("invalid predicate: %v, expected bool but had %s", actual.Name, aField.Type.String())
+		}
+		fn := flag.boolFn
+		return func(ptr unsafe.Pointer) bool {
+			ret, ok := fn(ptr)
+			return ok && ret != negate
+		}, nil
+	case *expr.Literal:
+		if actual.Kind != "bool" {
+			return nil, fmt.Errorf("invalid predicate: %v", actual.Value)
+		}
+		result, err := strconv.ParseBool(strings.ToLower(actual.Value))
+		if err != nil {
+			return nil, err
+		}
+		return constantPredicate(result != negate), nil
+	case nil:
+		return nil, fmt.Errorf("empty predicate")
+	}
+	return nil, fmt.Errorf("unsupported predicate: %T", n)
+}
+
+func (c *compiler) comparison(binary *expr.Binary, negate bool) (Predicate, error) {
+	x, err := c.value(binary.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := c.value(binary.Y)
+	if err != nil {
+		return nil, err
+	}
+	if x.kind == nullOperand || y.kind == nullOperand { //SQL: comparison with NULL is UNKNOWN
+		if err = nullParameter(binary, x.kind == nullOperand, y.kind == nullOperand); err != nil {
+			return nil, err
+		}
+		return alwaysFalse, nil
+	}
+	op := goComparison(binary.Op)
+	if negate {
+		op = negatedComparison[op]
+	}
+	ret := compare(x, op, y)
+	if ret == nil {
+		return nil, fmt.Errorf("invalid comparison: %v, incompatible operands", sqlparser.Stringify(binary))
+	}
+	return ret, nil
+}
+
+// compare returns comparison predicate or nil for incompatible operands, int operand is promoted when compared with float
+func compare(x *value, op string, y *value) Predicate {
+	switch {
+	case x.kind == timeOperand && y.kind == timeOperand:
+		return comparePredicate(x.timeFn, y.timeFn, timeComparisons[op].fn)
+	case x.kind == floatOperand && y.isNumeric(), x.isNumeric() && y.kind == floatOperand:
+		return comparePredicate(x.float(), y.float(), ordered[float64](op))
+	case x.kind == intOperand && y.kind == intOperand:
+		return comparePredicate(x.intFn, y.intFn, ordered[int](op))
+	case x.kind == stringOperand && y.kind == stringOperand:
+		return comparePredicate(x.stringFn, y.stringFn, ordered[string](op))
+	case x.kind == boolOperand && y.kind == boolOperand && (op == "==" || op == "!="):
+		equal := op == "=="
+		return comparePredicate(x.boolFn, y.boolFn, func(a, b bool) bool { return (a == b) == equal })
+	}
+	return nil
+}
+
+func comparePredicate[T any](x, y func(ptr unsafe.Pointer) (T, bool), cmp func(a, b T) bool) Predicate {
+	return func(ptr unsafe.Pointer) bool {
+		a, ok := x(ptr)
+		if !ok {
+			return false
+		}
+		b, ok := y(ptr)
+		return ok && cmp(a, b)
+	}
+}
+
+func ordered[T int | float64 | string](op string) func(a, b T) bool {
+	switch op {
+	case "==":
+		return func(a, b T) bool { return a == b }
+	case "!=":
+		return func(a, b T) bool { return a != b }
+	case "<":
+		return func(a, b T) bool { return a < b }
+	case "<=":
+		return func(a, b T) bool { return a <= b }
+	case ">":
+		return func(a, b T) bool { return a > b }
+	}
+	return func(a, b T) bool { return a >= b }
+}
+
+// between compiles [NOT] BETWEEN into range comparison
+func (c *compiler) between(binary *expr.Binary, negate bool) (Predicate, error) {
+	bounds, ok := binary.Y.(*expr.Range)
+	if !ok {
+		return nil, fmt.Errorf("unsupported BETWEEN operand: %v", sqlparser.Stringify(binary.Y))
+	}
+	x, err := c.value(binary.X)
+	if err != nil {
+		return nil, err
+	}
+	min, err := c.value(bounds.Min)
+	if err != nil {
+		return nil, err
+	}
+	max, err := c.value(bounds.Max)
+	if err != nil {
+		return nil, err
+	}
+	if x.kind == nullOperand || min.kind == nullOperand || max.kind == nullOperand {
+		return alwaysFalse, nil
+	}
+	outside := negate != (strings.ToUpper(binary.Op) == "NOT BETWEEN")
+	lowerOp, upperOp := ">=", "<="
+	if outside {
+		lowerOp, upperOp = "<", ">"
+	}
+	lower, upper := compare(x, lowerOp, min), compare(x, upperOp, max)
+	if lower == nil || upper == nil {
+		return nil, fmt.Errorf("invalid BETWEEN: %v, incompatible operands", sqlparser.Stringify(binary))
+	}
+	if outside {
+		return func(ptr unsafe.Pointer) bool { return lower(ptr) || upper(ptr) }, nil
+	}
+	return func(ptr unsafe.Pointer) bool { return lower(ptr) && upper(ptr) }, nil
+}
+
+func (c *compiler) isNull(binary *expr.Binary, negate bool) (Predicate, error) {
+	if !isNullLiteral(binary.Y) {
+		return nil, fmt.Errorf("unsupported IS operand: %v", sqlparser.Stringify(binary.Y))
+	}
+	ident, ok := binary.X.(*expr.Ident)
+	if !ok {
+		return nil, fmt.Errorf("unsupported IS operand: %v", sqlparser.Stringify(binary.X))
+	}
+	aField, err := c.field(ident.Name)
+	if err != nil {
+		return nil, err
+	}
+	isNull := (strings.ToUpper(binary.Op) == "IS") != negate
+	switch aField.Type.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		//the first word of pointer, map, slice and interface is nil for nil value
+		return func(ptr unsafe.Pointer) bool {
+			return (*(*unsafe.Pointer)(aField.Pointer(ptr)) == nil) == isNull
+		}, nil
+	}
+	return constantPredicate(!isNull), nil //non nullable type
+}
+
+func (c *compiler) in(binary *expr.Binary, negate bool) (Predicate, error) {
+	if _, ok := binary.Y.(*expr.Ident); ok {
+		negate = negate != (strings.ToUpper(binary.Op) == "NOT IN")
+		return c.arrayMatch(binary.Y, []node.Node{binary.X}, true, negate)
+	}
+	x, err := c.value(binary.X)
+	if err != nil {
+		return nil, err
+	}
+	if x.kind == nullOperand { //SQL: NULL IN (...) is UNKNOWN
+		return alwaysFalse, nil
+	}
+	fType, err := c.inType(binary.X, x)
+	if err != nil {
+		return nil, err
+	}
+	set, hasNull, err := c.inSet(binary, fType)
+	if err != nil {
+		return nil, err
+	}
+	negate = negate != (strings.ToUpper(binary.Op) == "NOT IN")
+	if negate && hasNull { //SQL: x NOT IN (..., NULL) is either false or UNKNOWN
+		return alwaysFalse, nil
+	}
+	get := x.originalFn()
+	return func(ptr unsafe.Pointer) bool {
+		item, ok := get(ptr)
+		return ok && set.Has(item) != negate
+	}, nil
+}
+
+// inType returns IN set type, field is used with its original type
+func (c *compiler) inType(n node.Node, x *value) (reflect.Type, error) {
+	if ident, ok := n.(*expr.Ident); ok {
+		fType := c.lookup(ident.Name).Type
+		if fType.Kind() == reflect.Ptr {
+			fType = fType.Elem()
+		}
+		return fType, nil
+	}
+	switch x.kind {
+	case intOperand:
+		return intType, nil
+	case floatOperand:
+		return float64Type, nil
+	case stringOperand:
+		return stringType, nil
+	case boolOperand:
+		return reflect.TypeOf(true), nil
+	case timeOperand:
+		return timeType, nil
+	}
+	return nil, fmt.Errorf("unsupported IN operand: %v", sqlparser.Stringify(n))
+}
+
+// array compiles ARRAY_CONTAINS(slice, value) and ARRAY_INTERSECTS(slice, values)
+func (c *compiler) array(name string, call *expr.Call, negate bool) (Predicate, error) {
+	if len(call.Args) != 2 {
+		return nil, fmt.Errorf("invalid %v arguments count: %v", name, len(call.Args))
+	}
+	return c.arrayMatch(call.Args[0], call.Args[1:], name == "ARRAY_CONTAINS", negate)
+}
+
+// arrayMatch returns slice field and compile time values set intersection predicate
+func (c *compiler) arrayMatch(array node.Node, items []node.Node, single bool, negate bool) (Predicate, error) {
+	var fType reflect.Type
+	x, err := c.value(array)
+	if err != nil {
+		return nil, err
+	}
+	if ident, ok := array.(*expr.Ident); ok {
+		if fType = c.lookup(ident.Name).Type; fType.Kind() == reflect.Ptr {
+			fType = fType.Elem()
+		}
+	}
+	set, err := c.arraySet(array, fType, items, single)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil { //SQL: NULL IN array is UNKNOWN
+		return alwaysFalse, nil
+	}
+	get := x.originalFn()
+	return func(ptr unsafe.Pointer) bool {
+		slice, ok := get(ptr)
+		return ok && set.Intersects(slice) != negate
+	}, nil
+}
+
+func (c *compiler) like(binary *expr.Binary, negate bool) (Predicate, error) {
+	matcher, negated, err := c.likeMatcher(binary)
+	if err != nil {
+		return nil, err
+	}
+	return c.match(binary.X, matcher, negate != negated)
+}
+
+// call compiles predicate functions: STARTS_WITH, ENDS_WITH, CONTAINS, REGEXP_LIKE, collection ANY, ALL, EXISTS
+// and array ARRAY_CONTAINS, ARRAY_INTERSECTS
+func (c *compiler) call(call *expr.Call, negate bool) (Predicate, error) {
+	ident, ok := call.X.(*expr.Ident)
+	if !ok {
+		return nil, fmt.Errorf("unsupported predicate: %v", sqlparser.Stringify(call))
+	}
+	name := strings.ToUpper(ident.Name)
+	if _, ok := collectionFunctions[name]; ok {
+		return c.collection(name, call, negate)
+	}
+	switch name {
+	case "ARRAY_CONTAINS", "ARRAY_INTERSECTS":
+		return c.array(name, call, negate)
+	}
+	matcher, err := c.callMatcher(name, call)
+	if err != nil {
+		return nil, err
+	}
+	return c.match(call.Args[0], matcher, negate)
+}
+
+// match returns string operand matcher predicate
+func (c *compiler) match(n node.Node, matcher match.Matcher, negate bool) (Predicate, error) {
+	x, err := c.value(n)
+	if err != nil {
+		return nil, err
+	}
+	if x.kind != stringOperand {
+		return nil, fmt.Errorf("invalid operand: %v, expected string", sqlparser.Stringify(n))
+	}
+	get := x.stringFn
+	return func(ptr unsafe.Pointer) bool {
+		text, ok := get(ptr)
+		return ok && matcher.Match(text) != negate
+	}, nil
+}
+
+// collection compiles ANY, ALL and EXISTS over child slice field, item criteria is compiled into nested predicate
+func (c *compiler) collection(name string, call *expr.Call, negate bool) (Predicate, error) {
+	aField, fType, criteria, err := c.collectionArgument(name, call)
+	if err != nil {
+		return nil, err
+	}
+	xSlice := xunsafe.NewSlice(fType)
+	isPointer := fType.Elem().Kind() == reflect.Ptr
+	matches := constantPredicate(true)
+	if criteria != "" {
+		if matches, err = c.compileCollection(fType.Elem(), criteria); err != nil {
+			return nil, fmt.Errorf("invalid %v criteria: %w", name, err)
+		}
+	}
+	isSlicePointer := aField.Type.Kind() == reflect.Ptr
+	isAll := name == "ALL"
+	return func(ptr unsafe.Pointer) bool {
+		slicePtr := aField.Pointer(ptr)
+		if isSlicePointer {
+			if slicePtr = *(*unsafe.Pointer)(slicePtr); slicePtr == nil {
+				return false
+			}
+		}
+		for i := 0; i < xSlice.Len(slicePtr); i++ {
+			itemPtr := xSlice.PointerAt(slicePtr, uintptr(i))
+			if isPointer {
+				if itemPtr = *(*unsafe.Pointer)(itemPtr); itemPtr == nil { //nil items are skipped
+					continue
+				}
+			}
+			if matches(itemPtr) != isAll {
+				return !isAll != negate
+			}
+		}
+		return isAll != negate
+	}, nil
+}
+
+// compileCollection compiles collection item criteria sharing query binding
+func (c *compiler) compileCollection(itemType reflect.Type, criteria string) (Predicate, error) {
+	itemType, err := collectionItemType(itemType)
+	if err != nil {
+		return nil, err
+	}
+	aNode, err := ParseCriteria("", []byte(criteria), 0)
+	if err != nil {
+		return nil, err
+	}
+	return AsPredicate(aNode, node2.LookupFieldType(itemHolder, itemType), c.params)
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/structql/node"
+	"reflect"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestAsPredicate(t *testing.T) {
+	type Code string
+	type Item struct {
+		Price   float64
+		Shipped bool
+	}
+	type Record struct {
+		ID       int
+		Name     string
+		Code     Code
+		Qty      int32
+		Price    float64
+		Discount *float64
+		Active   bool
+		Created  time.Time
+		Tags     []string
+		Items    []*Item
+	}
+	discount := 0.5
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []*Record{
+		{ID: 1, Name: "Alpha", Code: "a1", Qty: 3, Price: 10.5, Active: true, Created: created, Tags: []string{"promo", "new"}, Items: []*Item{{Price: 150, Shipped: true}, nil}},
+		{ID: 2, Name: "beta", Code: "b2", Qty: 0, Price: 2, Discount: &discount, Created: created.AddDate(0, 1, 0), Items: []*Item{{Price: 50}}},
+		{ID: 3, Name: "O'Brien", Code: "c3", Qty: 7, Price: 0.25, Active: true, Created: created.AddDate(1, 0, 0), Tags: []string{"sale"}},
+	}
+	var testCases = []struct {
+		description string
+		expr        string
+		values      []interface{}
+		expect      []int
+		expectErr   bool
+	}{
+		{description: "comparison", expr: "ID > 1 AND Name != 'beta'", expect: []int{3}},
+		{description: "negated or", expr: "NOT (ID = 1 OR Active)", expect: []int{2}},
+		{description: "named type", expr: "Code = ?", values: []interface{}{"b2"}, expect: []int{2}},
+		{description: "escaped literal", expr: `Name = 'O''Brien'`, expect: []int{3}},
+		{description: "int promotion", expr: "Qty * Price > 20", expect: []int{1}},
+		{description: "division by zero is NULL", expr: "10 / Qty > 1 OR NOT 10 / Qty > 1", expect: []int{1, 3}},
+		{description: "nil pointer", expr: "Discount > 0 OR NOT Discount > 0", expect: []int{2}},
+		{description: "is null", expr: "Discount IS NULL AND Tags IS NOT NULL", expect: []int{1, 3}},
+		{description: "between", expr: "Price NOT BETWEEN 1 AND ?", values: []interface{}{5}, expect: []int{1, 3}},
+		{description: "in", expr: "Code IN ('a1', ?)", values: []interface{}{"c3"}, expect: []int{1, 3}},
+		{description: "not in with null", expr: "ID NOT IN (1, NULL)", expect: nil},
+		{description: "like", expr: `Name ILIKE 'a%' OR Name LIKE '%''%'`, expect: []int{1, 3}},
+		{description: "string function", expr: "LOWER(Name) = 'alpha' OR STARTS_WITH(Name, 'be')", expect: []int{1, 2}},
+		{description: "nullable function", expr: "COALESCE(Discount, 0) = 0", expect: []int{1, 3}},
+		{description: "time", expr: "Created > ?", values: []interface{}{created}, expect: []int{2, 3}},
+		{description: "array", expr: "'promo' IN Tags OR ARRAY_CONTAINS(Tags, 'sale')", expect: []int{1, 3}},
+		{description: "any", expr: "ANY(Items[Price > 100])", expect: []int{1}},
+		{description: "all", expr: "ALL(Items[Shipped])", expect: []int{1, 3}},
+		{description: "not exists", expr: "NOT EXISTS(Items)", expect: []int{3}},
+		{description: "bool field", expr: "Active = true AND NOT Active = false", expect: []int{1, 3}},
+		{description: "incompatible operands", expr: "Name > 1", expectErr: true},
+		{description: "constant division by zero", expr: "ID / 0 > 1", expectErr: true},
+		{description: "unknown field", expr: "Foo = 1", expectErr: true},
+	}
+	recordType := reflect.TypeOf(&Record{})
+	for _, testCase := range testCases {
+		aNode, err := ParseCriteria("", []byte(testCase.expr), 0)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		values := &node.Values{Values: testCase.values, Bindings: &node.Binding{}}
+		predicate, err := AsPredicate(aNode, node.LookupFieldType("r", recordType), values)
+		if testCase.expectErr {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		var actual []int
+		for _, record := range records {
+			if predicate(unsafe.Pointer(record)) {
+				actual = append(actual, record.ID)
+			}
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
@@ -0,0 +1,438 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// value represents natively compiled operand, accessor matching the value kind is defined,
+// accessors return false for NULL
+type value struct {
+	kind     int
+	constant bool
+	intFn    func(ptr unsafe.Pointer) (int, bool)
+	floatFn  func(ptr unsafe.Pointer) (float64, bool)
+	stringFn func(ptr unsafe.Pointer) (string, bool)
+	boolFn   func(ptr unsafe.Pointer) (bool, bool)
+	timeFn   func(ptr unsafe.Pointer) (time.Time, bool)
+	valueFn  func(ptr unsafe.Pointer) (interface{}, bool)
+	rType    reflect.Type //field value type
+}
+
+func (v *value) isNumeric() bool {
+	return v.kind == intOperand || v.kind == floatOperand
+}
+
+// float returns float64 accessor, int value is promoted
+func (v *value) float() func(ptr unsafe.Pointer) (float64, bool) {
+	if v.floatFn != nil {
+		return v.floatFn
+	}
+	intFn := v.intFn
+	return func(ptr unsafe.Pointer) (float64, bool) {
+		ret, ok := intFn(ptr)
+		return float64(ret), ok
+	}
+}
+
+// resultType returns value type, field value keeps its original type, nil is returned for NULL
+func (v *value) resultType() reflect.Type {
+	switch {
+	case v.kind == nullOperand:
+		return nil
+	case v.rType != nil:
+		return v.rType
+	}
+	return scalarKind(v.kind).Type()
+}
+
+// originalFn returns generic accessor, field value keeps its original type
+func (v *value) originalFn() func(ptr unsafe.Pointer) (interface{}, bool) {
+	if v.valueFn != nil {
+		return v.valueFn
+	}
+	return v.interfaceFn()
+}
+
+// interfaceFn returns generic accessor, value is converted to its kind go type
+func (v *value) interfaceFn() func(ptr unsafe.Pointer) (interface{}, bool) {
+	switch v.kind {
+	case intOperand:
+		return asInterface(v.intFn)
+	case floatOperand:
+		return asInterface(v.floatFn)
+	case stringOperand:
+		return asInterface(v.stringFn)
+	case boolOperand:
+		return asInterface(v.boolFn)
+	case timeOperand:
+		return asInterface(v.timeFn)
+	}
+	if v.valueFn != nil {
+		return v.valueFn
+	}
+	return func(ptr unsafe.Pointer) (interface{}, bool) { return nil, false }
+}
+
+func asInterface[T any](fn func(ptr unsafe.Pointer) (T, bool)) func(ptr unsafe.Pointer) (interface{}, bool) {
+	return func(ptr unsafe.Pointer) (interface{}, bool) {
+		ret, ok := fn(ptr)
+		if !ok {
+			return nil, false
+		}
+		return ret, true
+	}
+}
+
+func constantFn[T any](v T) func(ptr unsafe.Pointer) (T, bool) {
+	return func(ptr unsafe.Pointer) (T, bool) { return v, true }
+}
+
+var nullValue = &value{kind: nullOperand}
+
+// constantValue returns value for a literal or placeholder value, pointers are dereferenced and nil value is NULL
+func constantValue(v interface{}) (*value, error) {
+	rValue := reflect.ValueOf(v)
+	for rValue.Kind() == reflect.Ptr {
+		if rValue.IsNil() {
+			break
+		}
+		rValue = rValue.Elem()
+	}
+	switch rValue.Kind() {
+	case reflect.Invalid, reflect.Ptr:
+		return nullValue, nil
+	case reflect.String:
+		return &value{kind: stringOperand, stringFn: constantFn(rValue.String())}, nil
+	case reflect.Bool:
+		return &value{kind: boolOperand, boolFn: constantFn(rValue.Bool())}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &value{kind: intOperand, intFn: constantFn(int(rValue.Int()))}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rValue.Uint() > math.MaxInt64 {
+			return &value{kind: floatOperand, floatFn: constantFn(float64(rValue.Uint()))}, nil
+		}
+		return &value{kind: intOperand, intFn: constantFn(int(rValue.Uint()))}, nil
+	case reflect.Float32, reflect.Float64:
+		return &value{kind: floatOperand, floatFn: constantFn(rValue.Float())}, nil
+	}
+	if !rValue.Type().ConvertibleTo(timeType) {
+		return nil, errs.UnsupportedType("unsupported placeholder value type: %T", v)
+	}
+	return &value{kind: timeOperand, timeFn: constantFn(rValue.Convert(timeType).Interface().(time.Time))}, nil
+}
+
+// fieldValue returns field value, pointer field is dereferenced and nil pointer is NULL
+func fieldValue(aField *xunsafe.Field) *value {
+	fType := aField.Type
+	pointer := aField.Pointer
+	if fType.Kind() == reflect.Ptr {
+		fType = fType.Elem()
+		pointer = func(ptr unsafe.Pointer) unsafe.Pointer {
+			return *(*unsafe.Pointer)(aField.Pointer(ptr))
+		}
+	}
+	return readValue(fType, pointer)
+}
+
+// readValue returns value of supplied type located by pointer function, nil pointer is NULL
+func readValue(rType reflect.Type, pointer func(ptr unsafe.Pointer) unsafe.Pointer) *value {
+	ret := &value{kind: otherOperand, rType: rType}
+	switch rType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		ret.kind, ret.intFn = intOperand, read(pointer, intReader(rType.Kind()))
+	case reflect.Float32:
+		ret.kind, ret.floatFn = floatOperand, read(pointer, func(p unsafe.Pointer) float64 { return float64(*(*float32)(p)) })
+	case reflect.Float64:
+		ret.kind, ret.floatFn = floatOperand, read(pointer, func(p unsafe.Pointer) float64 { return *(*float64)(p) })
+	case reflect.String:
+		ret.kind, ret.stringFn = stringOperand, read(pointer, func(p unsafe.Pointer) string { return *(*string)(p) })
+	case reflect.Bool:
+		ret.kind, ret.boolFn = boolOperand, read(pointer, func(p unsafe.Pointer) bool { return *(*bool)(p) })
+	case reflect.Slice:
+		ret.kind = sliceOperand
+	default:
+		if rType.ConvertibleTo(timeType) {
+			ret.kind, ret.timeFn = timeOperand, read(pointer, func(p unsafe.Pointer) time.Time { return *(*time.Time)(p) })
+		}
+	}
+	switch rType {
+	case intType, float64Type, stringType, timeType, reflect.TypeOf(true):
+	default: //keeps original type i.e. for IN set lookup or function argument
+		ret.valueFn = read(pointer, func(p unsafe.Pointer) interface{} { return reflect.NewAt(rType, p).Elem().Interface() })
+	}
+	return ret
+}
+
+func read[T any](pointer func(ptr unsafe.Pointer) unsafe.Pointer, reader func(p unsafe.Pointer) T) func(ptr unsafe.Pointer) (T, bool) {
+	return func(ptr unsafe.Pointer) (T, bool) {
+		p := pointer(ptr)
+		if p == nil {
+			var zero T
+			return zero, false
+		}
+		return reader(p), true
+	}
+}
+
+func intReader(kind reflect.Kind) func(p unsafe.Pointer) int {
+	switch kind {
+	case reflect.Int8:
+		return func(p unsafe.Pointer) int { return int(*(*int8)(p)) }
+	case reflect.Int16:
+		return func(p unsafe.Pointer) int { return int(*(*int16)(p)) }
+	case reflect.Int32:
+		return func(p unsafe.Pointer) int { return int(*(*int32)(p)) }
+	case reflect.Int64:
+		return func(p unsafe.Pointer) int { return int(*(*int64)(p)) }
+	case reflect.Uint:
+		return func(p unsafe.Pointer) int { return int(*(*uint)(p)) }
+	case reflect.Uint8:
+		return func(p unsafe.Pointer) int { return int(*(*uint8)(p)) }
+	case reflect.Uint16:
+		return func(p unsafe.Pointer) int { return int(*(*uint16)(p)) }
+	case reflect.Uint32:
+		return func(p unsafe.Pointer) int { return int(*(*uint32)(p)) }
+	case reflect.Uint64:
+		return func(p unsafe.Pointer) int { return int(*(*uint64)(p)) }
+	}
+	return func(p unsafe.Pointer) int { return *(*int)(p) }
+}
+
+// value returns natively compiled value operand
+func (c *compiler) value(n node.Node) (*value, error) {
+	switch actual := n.(type) {
+	case *expr.Ident:
+		aField, err := c.field(actual.Name)
+		if err != nil {
+			return nil, err
+		}
+		return fieldValue(aField), nil
+	case *expr.Literal:
+		switch actual.Kind {
+		case "string":
+			return &value{kind: stringOperand, stringFn: constantFn(unquote(actual.Value)), constant: true}, nil
+		case "null":
+			return nullValue, nil
+		case "bool":
+			flag, err := strconv.ParseBool(strings.ToLower(actual.Value))
+			if err != nil {
+				return nil, err
+			}
+			return &value{kind: boolOperand, boolFn: constantFn(flag), constant: true}, nil
+		case "int":
+			if number, err := strconv.Atoi(actual.Value); err == nil {
+				return &value{kind: intOperand, intFn: constantFn(number), constant: true}, nil
+			}
+		}
+		number, err := strconv.ParseFloat(actual.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid numeric literal: %v", actual.Value)
+		}
+		return &value{kind: floatOperand, floatFn: constantFn(number), constant: true}, nil
+	case *expr.Placeholder:
+		v, err := c.placeholder(actual)
+		if err != nil {
+			return nil, err
+		}
+		return constantValue(v)
+	case *expr.Parenthesis:
+		if _, isList := actual.X.([]node.Node); isList || actual.X == nil {
+			return nil, fmt.Errorf("unsupported operand: %v", actual.Raw)
+		}
+		return c.value(actual.X)
+	case *expr.Unary:
+		if actual.Op != "-" {
+			return nil, fmt.Errorf("unsupported operand: %v", sqlparser.Stringify(actual))
+		}
+		ret, err := c.value(actual.X)
+		if err != nil {
+			return nil, err
+		}
+		switch ret.kind {
+		case intOperand:
+			fn := ret.intFn
+			return &value{kind: intOperand, constant: ret.constant, intFn: func(ptr unsafe.Pointer) (int, bool) {
+				v, ok := fn(ptr)
+				return -v, ok
+			}}, nil
+		case floatOperand:
+			fn := ret.floatFn
+			return &value{kind: floatOperand, constant: ret.constant, floatFn: func(ptr unsafe.Pointer) (float64, bool) {
+				v, ok := fn(ptr)
+				return -v, ok
+			}}, nil
+		case nullOperand:
+			return ret, nil
+		}
+		return nil, fmt.Errorf("invalid operand: %v, expected numeric", sqlparser.Stringify(actual.X))
+	case *expr.Binary:
+		return c.arithmetic(actual)
+	case *expr.Call:
+		return c.scalar(actual)
+	case nil:
+		return nil, fmt.Errorf("missing operand")
+	}
+	return nil, fmt.Errorf("unsupported operand: %v", sqlparser.Stringify(n))
+}
+
+// arithmetic returns arithmetic value, int operands are promoted to float64 when mixed with float,
+// int division by zero is NULL
+func (c *compiler) arithmetic(binary *expr.Binary) (*value, error) {
+	op := binary.Op
+	switch op {
+	case "+", "-", "*", "/", "%":
+	default:
+		return nil, fmt.Errorf("unsupported operand: %v", sqlparser.Stringify(binary))
+	}
+	x, err := c.value(binary.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := c.value(binary.Y)
+	if err != nil {
+		return nil, err
+	}
+	if x.kind == nullOperand || y.kind == nullOperand { //SQL: arithmetic with NULL is NULL
+		return nullValue, nil
+	}
+	if !x.isNumeric() || !y.isNumeric() {
+		return nil, fmt.Errorf("invalid arithmetic operands: %v, expected numeric", sqlparser.Stringify(binary))
+	}
+	constant := x.constant && y.constant
+	if x.kind == floatOperand || y.kind == floatOperand {
+		if op == "%" {
+			return nil, fmt.Errorf("invalid operator %% for float operands: %v", sqlparser.Stringify(binary))
+		}
+		return &value{kind: floatOperand, constant: constant, floatFn: arithmeticFn(x.float(), y.float(), floatOperators[op])}, nil
+	}
+	if y.constant && (op == "/" || op == "%") {
+		if divisor, _ := y.intFn(nil); divisor == 0 {
+			return nil, fmt.Errorf("division by zero: %v", sqlparser.Stringify(binary))
+		}
+	}
+	return &value{kind: intOperand, constant: constant, intFn: arithmeticFn(x.intFn, y.intFn, intOperators[op])}, nil
+}
+
+func arithmeticFn[T int | float64](x, y func(ptr unsafe.Pointer) (T, bool), op func(a, b T) (T, bool)) func(ptr unsafe.Pointer) (T, bool) {
+	return func(ptr unsafe.Pointer) (T, bool) {
+		a, ok := x(ptr)
+		if !ok {
+			return a, false
+		}
+		b, ok := y(ptr)
+		if !ok {
+			return b, false
+		}
+		return op(a, b)
+	}
+}
+
+var intOperators = map[string]func(a, b int) (int, bool){
+	"+": func(a, b int) (int, bool) { return a + b, true },
+	"-": func(a, b int) (int, bool) { return a - b, true },
+	"*": func(a, b int) (int, bool) { return a * b, true },
+	"/": func(a, b int) (int, bool) {
+		if b == 0 {
+			return 0, false
+		}
+		return a / b, true
+	},
+	"%": func(a, b int) (int, bool) {
+		if b == 0 {
+			return 0, false
+		}
+		return a % b, true
+	},
+}
+
+var floatOperators = map[string]func(a, b float64) (float64, bool){
+	"+": func(a, b float64) (float64, bool) { return a + b, true },
+	"-": func(a, b float64) (float64, bool) { return a - b, true },
+	"*": func(a, b float64) (float64, bool) { return a * b, true },
+	"/": func(a, b float64) (float64, bool) { return a / b, true },
+}
+
+// scalar returns scalar function call value, non nullable function of NULL is NULL
+func (c *compiler) scalar(call *expr.Call) (*value, error) {
+	ident, ok := call.X.(*expr.Ident)
+	if !ok {
+		return nil, fmt.Errorf("unsupported function: %v", sqlparser.Stringify(call))
+	}
+	function := c.params.Functions.Lookup(ident.Name)
+	if function == nil {
+		return nil, fmt.Errorf("unsupported function: %v", ident.Name)
+	}
+	var args []func(ptr unsafe.Pointer) (interface{}, bool)
+	var kinds []scalar.Kind
+	for _, arg := range call.Args {
+		argument, err := c.value(arg)
+		if err != nil {
+			return nil, err
+		}
+		if argument.kind == nullOperand {
+			if !function.Nullable { //SQL: function of NULL is NULL
+				return argument, nil
+			}
+			continue
+		}
+		args = append(args, argument.interfaceFn())
+		kinds = append(kinds, scalarKind(argument.kind))
+	}
+	if len(args) == 0 {
+		return nullValue, nil
+	}
+	result, err := function.Validate(kinds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v call: %w", ident.Name, err)
+	}
+	fn, nullable := function.Fn, function.Nullable
+	compute := func(ptr unsafe.Pointer) (interface{}, bool) {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			v, ok := arg(ptr)
+			if !ok && !nullable {
+				return nil, false
+			}
+			values[i] = v
+		}
+		ret := result.Convert(fn(values))
+		return ret, ret != nil
+	}
+	ret := &value{kind: operandKind(result)}
+	switch result {
+	case scalar.String:
+		ret.stringFn = typed[string](compute)
+	case scalar.Int:
+		ret.intFn = typed[int](compute)
+	case scalar.Float:
+		ret.floatFn = typed[float64](compute)
+	case scalar.Bool:
+		ret.boolFn = typed[bool](compute)
+	case scalar.Time:
+		ret.timeFn = typed[time.Time](compute)
+	default:
+		ret.valueFn = compute
+	}
+	return ret, nil
+}
+
+func typed[T any](fn func(ptr unsafe.Pointer) (interface{}, bool)) func(ptr unsafe.Pointer) (T, bool) {
+	return func(ptr unsafe.Pointer) (T, bool) {
+		v, ok := fn(ptr)
+		ret, _ := v.(T)
+		return ret, ok
+	}
+}
@@ -0,0 +1,390 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/parser/scalar"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// operand kinds used for numeric promotion
+const (
+	otherOperand = iota
+	intOperand
+	floatOperand
+	nullOperand
+	stringOperand
+	boolOperand
+	timeOperand
+	sliceOperand
+)
+
+var (
+	intType     = reflect.TypeOf(0)
+	float64Type = reflect.TypeOf(0.0)
+	stringType  = reflect.TypeOf("")
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// operand represents translated value expression, numeric constants keep their value to be rendered with promoted type
+type operand struct {
+	expr     string
+	kind     int
+	constant interface{}
+}
+
+func (o *operand) isNumeric() bool {
+	return o.kind == intOperand || o.kind == floatOperand
+}
+
+// render returns go expression, int operand is converted to float64 if asFloat is set
+func (o *operand) render(asFloat bool) string {
+	switch value := o.constant.(type) {
+	case int:
+		if asFloat {
+			return formatFloat(float64(value))
+		}
+		if value < 0 { //igo does not support negative literals
+			return "(0 - " + strconv.Itoa(-value) + ")"
+		}
+		return strconv.Itoa(value)
+	case float64:
+		return formatFloat(value)
+	}
+	if asFloat && o.kind == intOperand {
+		return "float64(" + o.expr + ")"
+	}
+	return o.expr
+}
+
+func formatFloat(value float64) string {
+	if value < 0 {
+		return "(0.0 - " + formatFloat(-value) + ")"
+	}
+	ret := strconv.FormatFloat(value, 'g', -1, 64)
+	if !strings.ContainsAny(ret, ".e") {
+		ret += ".0"
+	}
+	return ret
+}
+
+func newConstant(value interface{}) *operand {
+	switch value.(type) {
+	case int:
+		return &operand{kind: intOperand, constant: value}
+	default:
+		return &operand{kind: floatOperand, constant: value}
+	}
+}
+
+// promote returns true if operands have to be rendered as float64
+func promote(x, y *operand) bool {
+	return x.kind == floatOperand || y.kind == floatOperand
+}
+
+// operand returns go expression for a value operand, pointer dereference and division guards are added to guards
+func (q *qualifier) operand(n node.Node, guards *[]string) (*operand, error) {
+	switch actual := n.(type) {
+	case *expr.Ident:
+		aField, err := q.field(actual.Name)
+		if err != nil {
+			return nil, err
+		}
+		name := q.holder + aField.Name
+		fType := aField.Type
+		if fType.Kind() == reflect.Ptr {
+			*guards = append(*guards, name+" != nil")
+			name = "*" + name
+			fType = fType.Elem()
+		}
+		return q.fieldOperand(name, fType), nil
+	case *expr.Literal:
+		switch actual.Kind {
+		case "string":
+			text := unquote(actual.Value)
+			if quoted := strconv.Quote(text); quoted[1:len(quoted)-1] == text {
+				return &operand{expr: quoted, kind: stringOperand}, nil
+			}
+			return q.param(text) //igo does not unescape string literals
+		case "null":
+			return &operand{expr: "nil", kind: nullOperand}, nil
+		case "bool":
+			return &operand{expr: strings.ToLower(actual.Value), kind: boolOperand}, nil
+		case "int":
+			if value, err := strconv.Atoi(actual.Value); err == nil {
+				return newConstant(value), nil
+			}
+		}
+		value, err := strconv.ParseFloat(actual.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid numeric literal: %v", actual.Value)
+		}
+		return newConstant(value), nil
+	case *expr.Placeholder:
+		value, err := q.placeholder(actual)
+		if err != nil {
+			return nil, err
+		}
+		return q.param(value)
+	case *expr.Parenthesis:
+		if _, isList := actual.X.([]node.Node); isList || actual.X == nil {
+			return nil, fmt.Errorf("unsupported operand: %v", actual.Raw)
+		}
+		ret, err := q.operand(actual.X, guards)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := actual.X.(*expr.Binary); ok && ret.constant == nil { //igo does not support redundant parenthesis
+			ret.expr = "(" + ret.expr + ")"
+		}
+		return ret, nil
+	case *expr.Unary:
+		if actual.Op != "-" {
+			return nil, fmt.Errorf("unsupported operand: %v", sqlparser.Stringify(actual))
+		}
+		ret, err := q.operand(actual.X, guards)
+		if err != nil {
+			return nil, err
+		}
+		switch value := ret.constant.(type) {
+		case int:
+			return newConstant(-value), nil
+		case float64:
+			return newConstant(-value), nil
+		}
+		switch ret.kind {
+		case intOperand:
+			ret.expr = "(0 - " + ret.expr + ")"
+		case floatOperand:
+			ret.expr = "(0.0 - " + ret.expr + ")"
+		case nullOperand:
+		default:
+			return nil, fmt.Errorf("invalid operand: %v, expected numeric", sqlparser.Stringify(actual.X))
+		}
+		return ret, nil
+	case *expr.Binary:
+		return q.arithmetic(actual, guards)
+	case *expr.Call:
+		return q.scalar(actual, guards)
+	case nil:
+		return nil, fmt.Errorf("missing operand")
+	}
+	return nil, fmt.Errorf("unsupported operand: %v", sqlparser.Stringify(n))
+}
+
+// arithmetic returns arithmetic expression operand, int operands are promoted to float64 when mixed with float
+func (q *qualifier) arithmetic(binary *expr.Binary, guards *[]string) (*operand, error) {
+	op := binary.Op
+	switch op {
+	case "+", "-", "*", "/", "%":
+	default:
+		return nil, fmt.Errorf("unsupported operand: %v", sqlparser.Stringify(binary))
+	}
+	x, err := q.operand(binary.X, guards)
+	if err != nil {
+		return nil, err
+	}
+	y, err := q.operand(binary.Y, guards)
+	if err != nil {
+		return nil, err
+	}
+	if x.kind == nullOperand || y.kind == nullOperand { //SQL: arithmetic with NULL is NULL
+		return &operand{expr: "nil", kind: nullOperand}, nil
+	}
+	if !x.isNumeric() || !y.isNumeric() {
+		return nil, fmt.Errorf("invalid arithmetic operands: %v, expected numeric", sqlparser.Stringify(binary))
+	}
+	asFloat := promote(x, y)
+	if asFloat && op == "%" {
+		return nil, fmt.Errorf("invalid operator %% for float operands: %v", sqlparser.Stringify(binary))
+	}
+	if x.constant != nil && y.constant != nil {
+		return foldConstants(x, op, y, asFloat)
+	}
+	if !asFloat && (op == "/" || op == "%") {
+		if y.constant != nil {
+			if y.constant == 0 {
+				return nil, fmt.Errorf("division by zero: %v", sqlparser.Stringify(binary))
+			}
+		} else {
+			*guards = append(*guards, y.render(false)+" != 0")
+		}
+	}
+	if asFloat && op == "/" {
+		name := q.binding.AddNamedFunction("FloatQuo", floatQuo)
+		return &operand{expr: name + "(" + x.render(asFloat) + ", " + y.render(asFloat) + ")", kind: floatOperand}, nil
+	}
+	kind := intOperand
+	if asFloat {
+		kind = floatOperand
+	}
+	return &operand{expr: x.render(asFloat) + " " + op + " " + y.render(asFloat), kind: kind}, nil
+}
+
+func foldConstants(x *operand, op string, y *operand, asFloat bool) (*operand, error) {
+	if asFloat {
+		a, b := scalar.AsFloat(x.constant), scalar.AsFloat(y.constant)
+		switch op {
+		case "+":
+			return newConstant(a + b), nil
+		case "-":
+			return newConstant(a - b), nil
+		case "*":
+			return newConstant(a * b), nil
+		default:
+			return newConstant(a / b), nil
+		}
+	}
+	a, b := x.constant.(int), y.constant.(int)
+	if b == 0 && (op == "/" || op == "%") {
+		return nil, fmt.Errorf("division by zero")
+	}
+	switch op {
+	case "+":
+		return newConstant(a + b), nil
+	case "-":
+		return newConstant(a - b), nil
+	case "*":
+		return newConstant(a * b), nil
+	case "/":
+		return newConstant(a / b), nil
+	default:
+		return newConstant(a % b), nil
+	}
+}
+
+// fieldOperand returns field operand, numeric fields other than int and float64 are converted with registered functions
+func (q *qualifier) fieldOperand(name string, fType reflect.Type) *operand {
+	switch fType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if fType != intType {
+			name = q.binding.AddNamedFunction("AsInt", scalar.AsInt) + "(" + name + ")"
+		}
+		return &operand{expr: name, kind: intOperand}
+	case reflect.Float32, reflect.Float64:
+		if fType != float64Type {
+			name = q.binding.AddNamedFunction("AsFloat", scalar.AsFloat) + "(" + name + ")"
+		}
+		return &operand{expr: name, kind: floatOperand}
+	case reflect.String:
+		if fType != stringType {
+			name = q.binding.AddNamedFunction("AsString", scalar.AsString) + "(" + name + ")"
+		}
+		return &operand{expr: name, kind: stringOperand}
+	case reflect.Bool:
+		return &operand{expr: name, kind: boolOperand}
+	case reflect.Slice:
+		return &operand{expr: name, kind: sliceOperand}
+	}
+	if fType.ConvertibleTo(timeType) {
+		return &operand{expr: name, kind: timeOperand}
+	}
+	return &operand{expr: name}
+}
+
+// scalar translates scalar function call into registered function call
+func (q *qualifier) scalar(call *expr.Call, guards *[]string) (*operand, error) {
+	ident, ok := call.X.(*expr.Ident)
+	if !ok {
+		return nil, fmt.Errorf("unsupported function: %v", sqlparser.Stringify(call))
+	}
+	function := q.params.Functions.Lookup(ident.Name)
+	if function == nil {
+		return nil, fmt.Errorf("unsupported function: %v", ident.Name)
+	}
+	var args []string
+	var kinds []scalar.Kind
+	for _, arg := range call.Args {
+		var argument *operand
+		var err error
+		if function.Nullable {
+			argument, err = q.nullableOperand(arg, guards)
+		} else {
+			argument, err = q.operand(arg, guards)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if argument.kind == nullOperand {
+			if !function.Nullable { //SQL: function of NULL is NULL
+				return argument, nil
+			}
+			continue
+		}
+		args = append(args, argument.render(false))
+		kinds = append(kinds, scalarKind(argument.kind))
+	}
+	if len(args) == 0 {
+		return &operand{expr: "nil", kind: nullOperand}, nil
+	}
+	result, err := function.Validate(kinds)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v call: %w", ident.Name, err)
+	}
+	caller := scalar.NewCaller(result, function.Fn)
+	if caller == nil {
+		return nil, fmt.Errorf("unsupported %v result kind: %v", ident.Name, result)
+	}
+	name := q.binding.AddNamedFunction(function.Name+"_"+result.String(), caller)
+	return &operand{expr: name + "(" + strings.Join(args, ", ") + ")", kind: operandKind(result)}, nil
+}
+
+// nullableOperand returns operand, pointer field is passed as is
+func (q *qualifier) nullableOperand(n node.Node, guards *[]string) (*operand, error) {
+	ident, ok := n.(*expr.Ident)
+	if !ok {
+		return q.operand(n, guards)
+	}
+	aField, err := q.field(ident.Name)
+	if err != nil {
+		return nil, err
+	}
+	if aField.Type.Kind() != reflect.Ptr {
+		return q.operand(n, guards)
+	}
+	ret := q.fieldOperand(q.holder+aField.Name, aField.Type.Elem())
+	ret.expr = q.holder + aField.Name
+	return ret, nil
+}
+
+func scalarKind(kind int) scalar.Kind {
+	switch kind {
+	case stringOperand:
+		return scalar.String
+	case intOperand:
+		return scalar.Int
+	case floatOperand:
+		return scalar.Float
+	case boolOperand:
+		return scalar.Bool
+	case timeOperand:
+		return scalar.Time
+	case sliceOperand:
+		return scalar.Slice
+	}
+	return scalar.Any
+}
+
+func operandKind(kind scalar.Kind) int {
+	switch kind {
+	case scalar.String:
+		return stringOperand
+	case scalar.Int:
+		return intOperand
+	case scalar.Float:
+		return floatOperand
+	case scalar.Bool:
+		return boolOperand
+	case scalar.Time:
+		return timeOperand
+	case scalar.Slice:
+		return sliceOperand
+	}
+	return otherOperand
+}
@@ -0,0 +1,107 @@
+package parser
+
+import (
+	"fmt"
+	"github.com/viant/igo/exec"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/structql/errs"
+	"math"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
+// parameter callers deliver placeholder values to compiled expression as typed values
+type (
+	stringParam func() string
+	intParam    func() int
+	floatParam  func() float64
+	boolParam   func() bool
+	timeParam   func() time.Time
+)
+
+// Call returns parameter value
+func (p stringParam) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	value := p()
+	return unsafe.Pointer(&value)
+}
+
+// Call returns parameter value
+func (p intParam) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	value := p()
+	return unsafe.Pointer(&value)
+}
+
+// Call returns parameter value
+func (p floatParam) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	value := p()
+	return unsafe.Pointer(&value)
+}
+
+// Call returns parameter value
+func (p boolParam) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	value := p()
+	return unsafe.Pointer(&value)
+}
+
+// Call returns parameter value
+func (p timeParam) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	value := p()
+	return unsafe.Pointer(&value)
+}
+
+// nullParameter returns an error when NULL placeholder value is compared, comparison with NULL never matches
+func nullParameter(binary *expr.Binary, xNull, yNull bool) error {
+	_, xPlaceholder := binary.X.(*expr.Placeholder)
+	_, yPlaceholder := binary.Y.(*expr.Placeholder)
+	if (xPlaceholder && xNull) || (yPlaceholder && yNull) {
+		return fmt.Errorf("invalid comparison: %v, NULL parameter never matches, use IS NULL instead", sqlparser.Stringify(binary))
+	}
+	return nil
+}
+
+// param returns operand for a placeholder value, the value is registered as typed parameter function,
+// pointers are dereferenced and nil value is NULL
+func (q *qualifier) param(value interface{}) (*operand, error) {
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Ptr {
+		if rValue.IsNil() {
+			break
+		}
+		rValue = rValue.Elem()
+	}
+	var fn interface{}
+	kind := otherOperand
+	switch rValue.Kind() {
+	case reflect.Invalid, reflect.Ptr:
+		return &operand{expr: "nil", kind: nullOperand}, nil
+	case reflect.String:
+		text := rValue.String()
+		fn, kind = stringParam(func() string { return text }), stringOperand
+	case reflect.Bool:
+		flag := rValue.Bool()
+		fn, kind = boolParam(func() bool { return flag }), boolOperand
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number := int(rValue.Int())
+		fn, kind = intParam(func() int { return number }), intOperand
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rValue.Uint() > math.MaxInt64 {
+			number := float64(rValue.Uint())
+			fn, kind = floatParam(func() float64 { return number }), floatOperand
+			break
+		}
+		number := int(rValue.Uint())
+		fn, kind = intParam(func() int { return number }), intOperand
+	case reflect.Float32, reflect.Float64:
+		number := rValue.Float()
+		fn, kind = floatParam(func() float64 { return number }), floatOperand
+	default:
+		if !rValue.Type().ConvertibleTo(timeType) {
+			return nil, errs.UnsupportedType("unsupported placeholder value type: %T", value)
+		}
+		ts := rValue.Convert(timeType).Interface().(time.Time)
+		fn, kind = timeParam(func() time.Time { return ts }), timeOperand
+	}
+	return &operand{expr: q.binding.AddFunction("Param", fn) + "()", kind: kind}, nil
+}
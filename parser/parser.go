@@ -1,11 +1,11 @@
 package parser
 
 import (
-	"fmt"
 	"github.com/viant/parsly"
-	"github.com/viant/sqlparser"
 	"github.com/viant/sqlparser/expr"
+	"github.com/viant/structql/errs"
 	"github.com/viant/structql/node"
+	"strconv"
 	"strings"
 )
 
@@ -14,12 +14,7 @@ func ParseSelector(expr string) (*node.Selector, error) {
 	root := &node.Selector{}
 	expr = strings.Trim(expr, "`")
 	cursor := parsly.NewCursor("", []byte(expr), 0)
-	err := parseSelector(cursor, root)
-	if err != nil {
-		return root, fmt.Errorf("%s", expr)
-	}
-
-	return root, err
+	return root, errs.Syntax(parseSelector(cursor, root))
 }
 
 func parseSelector(cursor *parsly.Cursor, parent *node.Selector) error {
@@ -31,22 +26,44 @@ outer:
 		case identifier:
 			selector.Name = match.Text(cursor)
 			pos := cursor.Pos
+			selector.Offset = pos - len(selector.Name)
 			selector.Child = &node.Selector{}
-			if match = cursor.MatchOne(conditionalBlockMatcher); match.Code == conditionalBlock {
+			for {
+				if match = cursor.MatchOne(conditionalBlockMatcher); match.Code != conditionalBlock {
+					break
+				}
 				block := match.Text(cursor)
-				qualify, err := ParseQualify(selector.Name, []byte(block[1:len(block)-1]), pos+1)
+				content := block[1 : len(block)-1]
+				if position, ok := parsePosition(content); ok {
+					if selector.Child.Position != nil {
+						return errs.Syntaxf(pos, "duplicate positional selector: %v at pos: %d", block, pos)
+					}
+					if selector.Child.Criteria != nil {
+						//position applies to all items, criteria block following position filters positioned items
+						return errs.Syntaxf(pos, "invalid selector: %v, positional block has to precede criteria block at pos: %d", block, pos)
+					}
+					selector.Child.Position = position
+					pos = cursor.Pos
+					continue
+				}
+				if selector.Child.Criteria != nil {
+					return errs.Syntaxf(pos, "duplicate criteria selector: %v at pos: %d", block, pos)
+				}
+				qualify, err := ParseQualify(selector.Name, []byte(content), pos+1)
 				if err != nil {
 					return err
 				}
 				selector.Child.Criteria = qualify.X
-				selector.Child.Holder = selector.Name
+				selector.Child.CriteriaOffset = pos + 1
+				pos = cursor.Pos
 			}
 
 		case selectorSeparator:
 			if selector.Name != "" {
-				child := &node.Selector{}
-				selector.Child = child
-				selector = child
+				if selector.Child == nil {
+					selector.Child = &node.Selector{}
+				}
+				selector = selector.Child
 			}
 		case parsly.EOF:
 			break outer
@@ -54,12 +71,55 @@ outer:
 			return cursor.NewError(identifierMatcher, selectorSeparatorMatcher)
 		}
 	}
+	//leaf criteria holder is set on the parsed selector, intermediate criteria holder is resolved from the parent selector name
+	if selector.Child != nil && selector.Child.Criteria != nil {
+		selector.Child.Holder = selector.Name
+	}
 	return nil
 }
 
+// parsePosition parses positional selector content: index (0, -1) or slice (1:4, :2, -3:)
+func parsePosition(content string) (*node.Position, bool) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, false
+	}
+	idx := strings.Index(content, ":")
+	if idx == -1 {
+		index, err := strconv.Atoi(content)
+		if err != nil {
+			return nil, false
+		}
+		return &node.Position{Index: &index}, true
+	}
+	ret := &node.Position{}
+	var ok bool
+	if ret.From, ok = parseBound(content[:idx]); !ok {
+		return nil, false
+	}
+	if ret.To, ok = parseBound(content[idx+1:]); !ok {
+		return nil, false
+	}
+	return ret, true
+}
+
+func parseBound(text string) (*int, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, true
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return nil, false
+	}
+	return &value, true
+}
+
 // ParseQualify parses SQL crtieria
 func ParseQualify(path string, cond []byte, offset int) (*expr.Qualify, error) {
-	cursor := parsly.NewCursor(path, cond, offset)
-	qualify := &expr.Qualify{}
-	return qualify, sqlparser.ParseQualify(cursor, qualify)
+	x, err := ParseCriteria(path, cond, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &expr.Qualify{X: x}, nil
 }
@@ -12,11 +12,12 @@ func TestParseSelector(t *testing.T) {
 		description string
 		expr        string
 		expect      interface{}
+		hasError    bool
 	}{
 		{
 			description: "basic selector",
 			expr:        "/Records",
-			expect:      &node.Selector{Name: "Records", Child: &node.Selector{}},
+			expect:      &node.Selector{Name: "Records", Offset: 1, Child: &node.Selector{}},
 		},
 		{
 			description: "basic selector relative",
@@ -26,20 +27,99 @@ func TestParseSelector(t *testing.T) {
 		{
 			description: "basic selector relative",
 			expr:        "Root/Records",
-			expect:      &node.Selector{Name: "Root", Child: &node.Selector{Name: "Records", Child: &node.Selector{}}},
+			expect:      &node.Selector{Name: "Root", Child: &node.Selector{Name: "Records", Offset: 5, Child: &node.Selector{}}},
 		},
 		{
 			description: "node with condition",
 			expr:        "Items[Active=true]/Nodes",
-			expect:      &node.Selector{Name: "Items", Child: &node.Selector{Name: "Nodes", Child: &node.Selector{}}},
+			expect:      &node.Selector{Name: "Items", Child: &node.Selector{Name: "Nodes", Offset: 19, CriteriaOffset: 6, Child: &node.Selector{}}},
+		},
+		{
+			description: "node with index",
+			expr:        "Items[0]/Nodes",
+			expect:      &node.Selector{Name: "Items", Child: &node.Selector{Name: "Nodes", Offset: 9, Position: &node.Position{Index: intPtr(0)}, Child: &node.Selector{}}},
+		},
+		{
+			description: "node with negative index",
+			expr:        "Items[-1]",
+			expect:      &node.Selector{Name: "Items", Child: &node.Selector{Position: &node.Position{Index: intPtr(-1)}}},
+		},
+		{
+			description: "node with slice",
+			expr:        "Items[1:4]",
+			expect:      &node.Selector{Name: "Items", Child: &node.Selector{Position: &node.Position{From: intPtr(1), To: intPtr(4)}}},
+		},
+		{
+			description: "node with open slice and condition",
+			expr:        "Items[:2][Active=true]",
+			expect:      &node.Selector{Name: "Items", Child: &node.Selector{Holder: "Items", CriteriaOffset: 10, Position: &node.Position{To: intPtr(2)}}},
+		},
+		{
+			description: "condition followed by index",
+			expr:        "Items[Active=true][0]",
+			hasError:    true,
 		},
 	}
 
 	for _, testCase := range testCases {
 		actual, err := ParseSelector(testCase.expr)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
 		if !assert.Nil(t, err, testCase.description) {
 			continue
 		}
 		assertly.AssertValues(t, testCase.expect, actual, testCase.description)
 	}
 }
+
+func TestParseSelector_IntermediateCriteria(t *testing.T) {
+	actual, err := ParseSelector("Items[Active=true]/Nodes")
+	if !assert.Nil(t, err) {
+		return
+	}
+	child := actual.Child
+	assert.EqualValues(t, "Nodes", child.Name)
+	assert.NotNil(t, child.Criteria, "criteria is kept on intermediate node")
+	assert.NotNil(t, child.Child)
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestSplitCriteria(t *testing.T) {
+	var testCases = []struct {
+		description string
+		SQL         string
+		statement   string
+		criteria    string
+	}{
+		{
+			description: "no criteria",
+			SQL:         "SELECT * FROM `/Records[Active=true]` LIMIT 1",
+			statement:   "SELECT * FROM `/Records[Active=true]` LIMIT 1",
+		},
+		{
+			description: "criteria",
+			SQL:         "SELECT * FROM `/` WHERE ID <> 1 AND Name = 'where limit'",
+			statement:   "SELECT * FROM `/`  ",
+			criteria:    " ID <> 1 AND Name = 'where limit'",
+		},
+		{
+			description: "criteria with clause",
+			SQL:         "SELECT ID FROM `/` WHERE NOT (ID = 1 OR ID = 2) ORDER BY ID LIMIT 2",
+			statement:   "SELECT ID FROM `/`  ORDER BY ID LIMIT 2",
+			criteria:    " NOT (ID = 1 OR ID = 2) ",
+		},
+	}
+	for _, testCase := range testCases {
+		statement, criteria, offset := SplitCriteria(testCase.SQL)
+		assert.EqualValues(t, testCase.statement, statement, testCase.description)
+		assert.EqualValues(t, testCase.criteria, criteria, testCase.description)
+		if criteria != "" {
+			assert.EqualValues(t, testCase.criteria, testCase.SQL[offset:offset+len(criteria)], testCase.description)
+		}
+	}
+}
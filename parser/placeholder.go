@@ -0,0 +1,31 @@
+package parser
+
+import "github.com/viant/parsly"
+
+// placeholderToken represents positional (?) or named (@name, :name) placeholder matcher
+type placeholderToken struct{}
+
+// Match matches a placeholder
+func (p *placeholderToken) Match(cursor *parsly.Cursor) int {
+	input := cursor.Input
+	pos := cursor.Pos
+	switch input[pos] {
+	case '?':
+		return 1
+	case '@', ':':
+	default:
+		return 0
+	}
+	if pos+1 >= len(input) || !(IsLetter(input[pos+1]) || input[pos+1] == '_') {
+		return 0
+	}
+	matched := 2
+	for i := pos + 2; i < len(input) && isIdentByte(input[i]); i++ {
+		matched++
+	}
+	return matched
+}
+
+func newPlaceholderToken() *placeholderToken {
+	return &placeholderToken{}
+}
@@ -2,110 +2,613 @@ package parser
 
 import (
 	"fmt"
+	"github.com/viant/sqlparser"
 	"github.com/viant/sqlparser/expr"
 	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
 	node2 "github.com/viant/structql/node"
 	"github.com/viant/structql/parser/in"
+	"github.com/viant/structql/parser/match"
 	"github.com/viant/xunsafe"
 	"reflect"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // AsBinaryGoExpr converts SQL criteria to golang expr
 func AsBinaryGoExpr(holder string, node node.Node, lookup func(name string) *xunsafe.Field, values *node2.Values) (string, error) {
 	output := strings.Builder{}
-	if err := qualifyExpr(holder, node, lookup, &output, values.Bindings, ""); err != nil {
+	if err := qualifyExpr(holder, node, lookup, &output, values, false); err != nil {
 		return "", err
 	}
-	expr := strings.TrimSpace(output.String())
-	return values.Bindings.Expand(expr, values.Values)
+	return strings.TrimSpace(output.String()), nil
 }
 
-func qualifyExpr(holder string, node node.Node, lookup func(name string) *xunsafe.Field, output *strings.Builder, binding *node2.Binding, op string) error {
-	switch actual := node.(type) {
+// qualifier translates SQL criteria into go boolean expression.
+// Negation is pushed down to predicates (De Morgan), so that predicates on nil pointers
+// evaluate as SQL UNKNOWN (false) in both positive and negated form.
+type qualifier struct {
+	holder  string
+	lookup  func(name string) *xunsafe.Field
+	binding *node2.Binding
+	params  *node2.Values
+	output  *strings.Builder
+}
+
+func qualifyExpr(holder string, node node.Node, lookup func(name string) *xunsafe.Field, output *strings.Builder, values *node2.Values, negate bool) error {
+	q := &qualifier{holder: holder, lookup: lookup, binding: values.Bindings, params: values, output: output}
+	return q.predicate(node, negate)
+}
+
+func (q *qualifier) predicate(n node.Node, negate bool) error {
+	switch actual := n.(type) {
 	case *expr.Qualify:
-		if err := qualifyExpr(holder, actual.X, lookup, output, binding, ""); err != nil {
+		return q.predicate(actual.X, negate)
+	case *expr.Parenthesis:
+		if _, isList := actual.X.([]node.Node); isList || actual.X == nil {
+			return fmt.Errorf("unsupported predicate: %v", actual.Raw)
+		}
+		q.output.WriteByte('(')
+		if err := q.predicate(actual.X, negate); err != nil {
 			return err
 		}
+		q.output.WriteByte(')')
+		return nil
+	case *expr.Unary:
+		if strings.ToUpper(actual.Op) != "NOT" {
+			return fmt.Errorf("unsupported predicate: %v", sqlparser.Stringify(actual))
+		}
+		return q.predicate(actual.X, !negate)
+	case *expr.Binary:
+		switch op := strings.ToUpper(actual.Op); op {
+		case "AND", "OR":
+			if negate {
+				op = map[string]string{"AND": "OR", "OR": "AND"}[op]
+			}
+			if negate { //flipped operator changes precedence, operands are grouped
+				return q.group(actual.X, op, actual.Y)
+			}
+			if err := q.predicate(actual.X, negate); err != nil {
+				return err
+			}
+			q.output.WriteString(" " + map[string]string{"AND": "&&", "OR": "||"}[op] + " ")
+			return q.predicate(actual.Y, negate)
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			return q.comparison(actual, negate)
+		case "IS", "IS NOT":
+			return q.isNull(actual, negate)
+		case "IN", "NOT IN":
+			return q.in(actual, negate)
+		case "LIKE", "NOT LIKE", "ILIKE", "NOT ILIKE":
+			return q.like(actual, negate)
+		case "BETWEEN", "NOT BETWEEN":
+			return q.between(actual, negate)
+		default:
+			return fmt.Errorf("unsupported operator: %v", actual.Op)
+		}
+	case *expr.Call:
+		return q.call(actual, negate)
 	case *expr.Ident:
+		aField, err := q.field(actual.Name)
+		if err != nil {
+			return err
+		}
+		name := q.holder + aField.Name
+		fType := aField.Type
+		if fType.Kind() == reflect.Ptr {
+			q.output.WriteString(name + " != nil && ")
+			name = "*" + name
+			fType = fType.Elem()
+		}
+		if fType.Kind() != reflect.Bool {
+			return fmt.Errorf("invalid predicate: %v, expected bool but had %s", actual.Name, aField.Type.String())
+		}
+		q.output.WriteString(name + " == " + strconv.FormatBool(!negate)) //igo does not support bare bool selector
+		return nil
+	case *expr.Literal:
+		if actual.Kind != "bool" {
+			return fmt.Errorf("invalid predicate: %v", actual.Value)
+		}
+		value, err := strconv.ParseBool(strings.ToLower(actual.Value))
+		if err != nil {
+			return err
+		}
+		q.output.WriteString(constant(value != negate))
+		return nil
+	case nil:
+		return fmt.Errorf("empty predicate")
+	}
+	return fmt.Errorf("unsupported predicate: %T", n)
+}
+
+// group writes negated operands grouped with parentheses joined with the flipped operator
+func (q *qualifier) group(x node.Node, op string, y node.Node) error {
+	q.output.WriteByte('(')
+	if err := q.predicate(x, true); err != nil {
+		return err
+	}
+	q.output.WriteString(") " + map[string]string{"AND": "&&", "OR": "||"}[op] + " (")
+	if err := q.predicate(y, true); err != nil {
+		return err
+	}
+	q.output.WriteByte(')')
+	return nil
+}
+
+// constant returns constant predicate expression, igo does not support bare bool literal
+func constant(value bool) string {
+	if value {
+		return "1 == 1"
+	}
+	return "1 == 0"
+}
 
-		name := actual.Name
-		binding.ContextField = lookup(actual.Name)
-		if binding.ContextField != nil {
-			name = binding.ContextField.Name
+func (q *qualifier) comparison(binary *expr.Binary, negate bool) error {
+	var guards []string
+	x, err := q.operand(binary.X, &guards)
+	if err != nil {
+		return err
+	}
+	y, err := q.operand(binary.Y, &guards)
+	if err != nil {
+		return err
+	}
+	if x.kind == nullOperand || y.kind == nullOperand { //SQL: comparison with NULL is UNKNOWN
+		if err = nullParameter(binary, x.kind == nullOperand, y.kind == nullOperand); err != nil {
+			return err
 		}
-		if binding.ContextField == nil {
-			return fmt.Errorf("unknown field: %v", actual.Name)
+		q.output.WriteString(constant(false))
+		return nil
+	}
+	op := goComparison(binary.Op)
+	if negate {
+		op = negatedComparison[op]
+	}
+	q.writeGuards(guards)
+	q.output.WriteString(q.compare(x, op, y))
+	return nil
+}
+
+// compare returns go comparison expression, int operand is promoted when compared with float,
+// float64 ordering and time.Time comparison use registered functions
+func (q *qualifier) compare(x *operand, op string, y *operand) string {
+	if x.kind == timeOperand && y.kind == timeOperand {
+		comparison := timeComparisons[op]
+		return q.binding.AddNamedFunction(comparison.name, comparison.fn) + "(" + x.expr + ", " + y.expr + ")"
+	}
+	asFloat := x.isNumeric() && y.isNumeric() && promote(x, y)
+	if comparison, ok := floatComparisons[op]; ok && asFloat {
+		return q.binding.AddNamedFunction(comparison.name, comparison.fn) + "(" + x.render(asFloat) + ", " + y.render(asFloat) + ")"
+	}
+	return x.render(asFloat) + " " + op + " " + y.render(asFloat)
+}
+
+// between translates [NOT] BETWEEN into range comparison
+func (q *qualifier) between(binary *expr.Binary, negate bool) error {
+	bounds, ok := binary.Y.(*expr.Range)
+	if !ok {
+		return fmt.Errorf("unsupported BETWEEN operand: %v", sqlparser.Stringify(binary.Y))
+	}
+	var guards []string
+	x, err := q.operand(binary.X, &guards)
+	if err != nil {
+		return err
+	}
+	min, err := q.operand(bounds.Min, &guards)
+	if err != nil {
+		return err
+	}
+	max, err := q.operand(bounds.Max, &guards)
+	if err != nil {
+		return err
+	}
+	if x.kind == nullOperand || min.kind == nullOperand || max.kind == nullOperand {
+		q.output.WriteString(constant(false))
+		return nil
+	}
+	q.writeGuards(guards)
+	if negate != (strings.ToUpper(binary.Op) == "NOT BETWEEN") {
+		q.output.WriteString("(" + q.compare(x, "<", min) + " || " + q.compare(x, ">", max) + ")")
+		return nil
+	}
+	q.output.WriteString("(" + q.compare(x, ">=", min) + " && " + q.compare(x, "<=", max) + ")")
+	return nil
+}
+
+func (q *qualifier) isNull(binary *expr.Binary, negate bool) error {
+	if !isNullLiteral(binary.Y) {
+		return fmt.Errorf("unsupported IS operand: %v", sqlparser.Stringify(binary.Y))
+	}
+	ident, ok := binary.X.(*expr.Ident)
+	if !ok {
+		return fmt.Errorf("unsupported IS operand: %v", sqlparser.Stringify(binary.X))
+	}
+	aField, err := q.field(ident.Name)
+	if err != nil {
+		return err
+	}
+	isNull := strings.ToUpper(binary.Op) == "IS"
+	if negate {
+		isNull = !isNull
+	}
+	switch aField.Type.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		op := "=="
+		if !isNull {
+			op = "!="
 		}
-		fType := binding.ContextField.Type
+		q.output.WriteString(q.holder + aField.Name + " " + op + " nil")
+	default: //non nullable type
+		q.output.WriteString(constant(!isNull))
+	}
+	return nil
+}
 
-		if strings.ToLower(op) == "in" {
-			return nil
+func (q *qualifier) in(binary *expr.Binary, negate bool) error {
+	if _, ok := binary.Y.(*expr.Ident); ok {
+		return q.arrayIn(binary, negate)
+	}
+	var guards []string
+	value, fType, err := q.inOperand(binary.X, &guards)
+	if err != nil {
+		return err
+	}
+	if value == "" { //SQL: NULL IN (...) is UNKNOWN
+		q.output.WriteString(constant(false))
+		return nil
+	}
+	set, hasNull, err := q.inSet(binary, fType)
+	if err != nil {
+		return err
+	}
+	negate = negate != (strings.ToUpper(binary.Op) == "NOT IN")
+	if negate && hasNull { //SQL: x NOT IN (..., NULL) is either false or UNKNOWN
+		q.output.WriteString(constant(false))
+		return nil
+	}
+	name := q.binding.AddFunction("In", set.Has)
+	q.writeGuards(guards)
+	q.output.WriteString(name + "(" + value + ")")
+	if negate { //igo does not support negated call expression
+		q.output.WriteString(" == false")
+	}
+	return nil
+}
+
+// inSet returns IN list values set of supplied type, it also returns true if the list contains NULL
+func (q *qualifier) inSet(binary *expr.Binary, fType reflect.Type) (*in.Set, bool, error) {
+	label := sqlparser.Stringify(binary.X)
+	set, err := in.NewSet(fType)
+	if err != nil {
+		return nil, false, err
+	}
+	items := []node.Node{binary.Y}
+	if list, ok := binary.Y.(*expr.Parenthesis); ok {
+		items, _ = list.X.([]node.Node)
+	}
+	hasNull := false
+	for _, item := range items {
+		values, err := q.listValues(item)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid %v IN operand: %w", label, err)
 		}
-		if fType.Kind() == reflect.Ptr && op != "is" {
-			output.WriteString(holder + name + " != nil AND *")
+		for _, item := range values {
+			if isNil(item) {
+				hasNull = true
+				continue
+			}
+			if err = set.Add(item); err != nil {
+				return nil, false, fmt.Errorf("invalid %v IN operand: %w", label, err)
+			}
 		}
-		output.WriteString(holder + name)
-	case *expr.Placeholder:
-		if actual.Name == "?" {
-			binding.AddPlaceholder()
-			output.WriteByte('?')
+	}
+	return set, hasNull, nil
+}
+
+// inOperand returns IN operand expression and type, field is used with its original type
+func (q *qualifier) inOperand(n node.Node, guards *[]string) (string, reflect.Type, error) {
+	if ident, ok := n.(*expr.Ident); ok {
+		aField, err := q.field(ident.Name)
+		if err != nil {
+			return "", nil, err
 		}
+		fieldName := q.holder + aField.Name
+		fType := aField.Type
+		if fType.Kind() == reflect.Ptr {
+			*guards = append(*guards, fieldName+" != nil")
+			fieldName = "*" + fieldName
+			fType = fType.Elem()
+		}
+		return fieldName, fType, nil
+	}
+	ret, err := q.operand(n, guards)
+	if err != nil {
+		return "", nil, err
+	}
+	switch ret.kind {
+	case nullOperand:
+		return "", nil, nil
+	case intOperand:
+		return ret.render(false), intType, nil
+	case floatOperand:
+		return ret.render(false), float64Type, nil
+	case stringOperand:
+		return ret.expr, reflect.TypeOf(""), nil
+	case boolOperand:
+		return ret.expr, reflect.TypeOf(true), nil
+	case timeOperand:
+		return ret.expr, timeType, nil
+	}
+	return "", nil, fmt.Errorf("unsupported IN operand: %v", sqlparser.Stringify(n))
+}
 
+// listValues returns IN list item values, placeholder slice value is expanded
+func (q *qualifier) listValues(n node.Node) ([]interface{}, error) {
+	switch actual := n.(type) {
 	case *expr.Literal:
-		switch actual.Kind {
-		case "string":
-			output.WriteByte('"')
-			value := strings.Trim(actual.Value, `"'`)
-			output.WriteString(value)
-			output.WriteByte('"')
-		case "null":
-			output.WriteString("nil")
-		default:
-			output.WriteString(actual.Value)
-		}
-	case *expr.Binary:
-		output.WriteString(" ")
-		if err := qualifyExpr(holder, actual.X, lookup, output, binding, actual.Op); err != nil {
-			return err
+		value, err := literalValue(actual)
+		if err != nil {
+			return nil, err
 		}
-		output.WriteString(" ")
-		switch strings.ToLower(actual.Op) {
-		case "and":
-			output.WriteString("&&")
-		case "or":
-			output.WriteString("||")
-		case "=":
-			output.WriteString("==")
-		case "is":
-			output.WriteString("==")
-		case "in":
-			y := actual.Y.(*expr.Parenthesis)
-			group := binding.AddPlaceholders(strings.Count(y.Raw, "?"))
-			switch binding.ContextField.Type.Kind() {
-			case reflect.String:
-				group.InStrings = in.NewStrings()
-				output.WriteString("In" + group.Name)
-			case reflect.Int:
-				group.InInts = in.NewInts()
-				output.WriteString("In" + group.Name)
-			default:
-				return fmt.Errorf("unsupported type: %v for in operator", binding.ContextField.Type.Kind())
+		return []interface{}{value}, nil
+	case *expr.Unary:
+		if literal, ok := actual.X.(*expr.Literal); ok && actual.Op == "-" && (literal.Kind == "int" || literal.Kind == "numeric") {
+			value, err := literalValue(&expr.Literal{Kind: literal.Kind, Value: "-" + literal.Value})
+			if err != nil {
+				return nil, err
 			}
-			fieldName := group.Name
-			if holder != "" {
-				fieldName = holder + fieldName
+			return []interface{}{value}, nil
+		}
+	case *expr.Placeholder:
+		value, err := q.placeholder(actual)
+		if err != nil {
+			return nil, err
+		}
+		rValue := reflect.ValueOf(value)
+		if rValue.Kind() != reflect.Slice || rValue.Type().Elem().Kind() == reflect.Uint8 {
+			return []interface{}{value}, nil
+		}
+		var ret = make([]interface{}, rValue.Len())
+		for i := range ret {
+			ret[i] = rValue.Index(i).Interface()
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("unsupported operand: %v, expected literal or placeholder", sqlparser.Stringify(n))
+}
+
+// literalValue returns go value of the literal
+func literalValue(literal *expr.Literal) (interface{}, error) {
+	switch literal.Kind {
+	case "string":
+		return unquote(literal.Value), nil
+	case "int":
+		return strconv.Atoi(literal.Value)
+	case "numeric":
+		return strconv.ParseFloat(literal.Value, 64)
+	case "bool":
+		return strconv.ParseBool(strings.ToLower(literal.Value))
+	case "null":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported literal: %v", literal.Value)
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rValue := reflect.ValueOf(value)
+	return rValue.Kind() == reflect.Ptr && rValue.IsNil()
+}
+
+func (q *qualifier) like(binary *expr.Binary, negate bool) error {
+	matcher, negated, err := q.likeMatcher(binary)
+	if err != nil {
+		return err
+	}
+	fieldName, err := q.stringField(binary.X)
+	if err != nil {
+		return err
+	}
+	return q.match(fieldName, matcher, negate != negated)
+}
+
+// likeMatcher returns [NOT] [I]LIKE pattern matcher, it also returns true for negated operator
+func (q *qualifier) likeMatcher(binary *expr.Binary) (match.Matcher, bool, error) {
+	op := strings.ToUpper(binary.Op)
+	pattern, escape := binary.Y, ""
+	if escaped, ok := pattern.(*expr.Binary); ok && strings.ToUpper(escaped.Op) == "ESCAPE" {
+		pattern = escaped.X
+		var err error
+		if escape, err = q.stringValue(escaped.Y); err != nil {
+			return nil, false, err
+		}
+		if utf8.RuneCountInString(escape) != 1 {
+			return nil, false, fmt.Errorf("invalid LIKE escape: %q, expected single character", escape)
+		}
+	}
+	value, err := q.stringValue(pattern)
+	if err != nil {
+		return nil, false, err
+	}
+	var escapeRune rune
+	if escape != "" {
+		escapeRune, _ = utf8.DecodeRuneInString(escape)
+	}
+	matcher, err := match.NewLike(value, escapeRune, strings.HasSuffix(op, "ILIKE"))
+	if err != nil {
+		return nil, false, err
+	}
+	return matcher, strings.HasPrefix(op, "NOT "), nil
+}
+
+// call translates predicate functions: STARTS_WITH, ENDS_WITH, CONTAINS, REGEXP_LIKE, collection ANY, ALL, EXISTS
+// and array ARRAY_CONTAINS, ARRAY_INTERSECTS
+func (q *qualifier) call(call *expr.Call, negate bool) error {
+	ident, ok := call.X.(*expr.Ident)
+	if !ok {
+		return fmt.Errorf("unsupported predicate: %v", sqlparser.Stringify(call))
+	}
+	name := strings.ToUpper(ident.Name)
+	if _, ok := collectionFunctions[name]; ok {
+		return q.collection(name, call, negate)
+	}
+	switch name {
+	case "ARRAY_CONTAINS", "ARRAY_INTERSECTS":
+		return q.array(name, call, negate)
+	}
+	matcher, err := q.callMatcher(name, call)
+	if err != nil {
+		return err
+	}
+	fieldName, err := q.stringField(call.Args[0])
+	if err != nil {
+		return err
+	}
+	return q.match(fieldName, matcher, negate)
+}
+
+// callMatcher returns matcher for string predicate function, the first function argument is matched
+func (q *qualifier) callMatcher(name string, call *expr.Call) (match.Matcher, error) {
+	argsCount := 2
+	if name == "REGEXP_LIKE" && len(call.Args) == 3 {
+		argsCount = 3
+	}
+	if len(call.Args) != argsCount {
+		return nil, fmt.Errorf("invalid %v arguments count: %v", name, len(call.Args))
+	}
+	value, err := q.stringValue(call.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "STARTS_WITH":
+		return match.NewPrefix(value), nil
+	case "ENDS_WITH":
+		return match.NewSuffix(value), nil
+	case "CONTAINS":
+		return match.NewContains(value), nil
+	case "REGEXP_LIKE":
+		flags := ""
+		if argsCount == 3 {
+			if flags, err = q.stringValue(call.Args[2]); err != nil {
+				return nil, err
 			}
-			output.WriteString(fmt.Sprintf("(%s,?)", fieldName))
-			return nil
-		default:
-			output.WriteString(actual.Op)
 		}
-		output.WriteString(" ")
-		if err := qualifyExpr(holder, actual.Y, lookup, output, binding, ""); err != nil {
-			return err
+		matcher, err := match.NewRegexp(value, flags)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REGEXP_LIKE pattern: %w", err)
 		}
+		return matcher, nil
+	}
+	return nil, fmt.Errorf("unsupported function: %v", name)
+}
 
+// match writes registered matcher function call
+func (q *qualifier) match(fieldName string, matcher match.Matcher, negate bool) error {
+	name := q.binding.AddFunction("Match", matcher.Match)
+	q.output.WriteString(name + "(" + fieldName + ")")
+	if negate { //igo does not support negated call expression
+		q.output.WriteString(" == false")
 	}
 	return nil
 }
+
+// stringField returns string operand expression, nil pointer guards are written to the output
+func (q *qualifier) stringField(n node.Node) (string, error) {
+	var guards []string
+	ret, err := q.operand(n, &guards)
+	if err != nil {
+		return "", err
+	}
+	if ret.kind != stringOperand {
+		return "", fmt.Errorf("invalid operand: %v, expected string", sqlparser.Stringify(n))
+	}
+	q.writeGuards(guards)
+	return ret.expr, nil
+}
+
+// stringValue returns string literal or placeholder value consumed at compile time
+func (q *qualifier) stringValue(n node.Node) (string, error) {
+	switch actual := n.(type) {
+	case *expr.Literal:
+		if actual.Kind == "string" {
+			return unquote(actual.Value), nil
+		}
+	case *expr.Placeholder:
+		value, err := q.placeholder(actual)
+		if err != nil {
+			return "", err
+		}
+		switch actual := value.(type) {
+		case string:
+			return actual, nil
+		case *string:
+			if actual != nil {
+				return *actual, nil
+			}
+		}
+		return "", fmt.Errorf("invalid placeholder %v value: %v, expected string", actual.Name, value)
+	}
+	return "", fmt.Errorf("unsupported operand: %v, expected string literal or placeholder", sqlparser.Stringify(n))
+}
+
+// placeholder returns positional (?) or named (@name, :name) placeholder value consumed at compile time
+func (q *qualifier) placeholder(placeholder *expr.Placeholder) (interface{}, error) {
+	if placeholder.Name == "?" {
+		return q.params.Positional(q.binding.AddResolvedPlaceholder())
+	}
+	return q.params.Named(placeholder.Name[1:])
+}
+
+func (q *qualifier) writeGuards(guards []string) {
+	for _, guard := range guards {
+		q.output.WriteString(guard + " && ")
+	}
+}
+
+func (q *qualifier) field(name string) (*xunsafe.Field, error) {
+	ret := q.lookup(name)
+	if ret == nil {
+		return nil, errs.UnknownField("unknown field: %v", name)
+	}
+	q.binding.ContextField = ret
+	return ret, nil
+}
+
+var negatedComparison = map[string]string{
+	"==": "!=",
+	"!=": "==",
+	"<":  ">=",
+	"<=": ">",
+	">":  "<=",
+	">=": "<",
+}
+
+func goComparison(op string) string {
+	switch op {
+	case "=":
+		return "=="
+	case "<>":
+		return "!="
+	}
+	return op
+}
+
+func isNullLiteral(n node.Node) bool {
+	literal, ok := n.(*expr.Literal)
+	return ok && literal.Kind == "null"
+}
+
+// unquote returns SQL string literal value, doubled quote represents the quote character
+func unquote(literal string) string {
+	if len(literal) >= 2 {
+		if quote := literal[0]; (quote == '\'' || quote == '"') && literal[len(literal)-1] == quote {
+			literal = literal[1 : len(literal)-1]
+			literal = strings.ReplaceAll(literal, string(quote)+string(quote), string(quote))
+		}
+	}
+	return literal
+}
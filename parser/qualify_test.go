@@ -6,9 +6,11 @@ import (
 	"github.com/viant/xunsafe"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestQualifyExpr(t *testing.T) {
+	type Code string
 	var testCases = []struct {
 		description string
 		fieldType   reflect.Type
@@ -19,20 +21,20 @@ func TestQualifyExpr(t *testing.T) {
 		{
 			description: "binary expr",
 			expr:        "Field1 = 'abc' AND Field2 IS NULL",
-			expect:      `Field1 ==  "abc" &&  Field2 == nil`,
+			expect:      `Field1 == "abc" && 1 == 0`,
 		},
 		{
 			description: "binary expr",
 			values:      []interface{}{"abc", "xyz"},
 			expr:        "Field1 =  ? AND Field2 =  3 AND Field3 =  ? ",
-			expect:      `Field1 ==  "abc" &&  Field2 ==  3 &&  Field3 == "xyz"`,
+			expect:      `Field1 == Param0() && Field2 == 3 && Field3 == Param1()`,
 		},
 		{
 			description: "binary ptr expr",
 			fieldType:   reflect.PtrTo(reflect.TypeOf("")),
 			values:      []interface{}{"abc"},
 			expr:        "Field1 = ?",
-			expect:      `Field1 != nil AND *Field1 == "abc"`,
+			expect:      `Field1 != nil && *Field1 == Param0()`,
 		},
 		{
 			description: "binary ptr is nil",
@@ -46,7 +48,253 @@ func TestQualifyExpr(t *testing.T) {
 			fieldType:   reflect.TypeOf(0),
 			values:      []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 			expr:        "Field1 in(?,?,?,?,?,?,?,?,?,?)",
-			expect:      `InField1(Field1,1)`,
+			expect:      `In0(Field1)`,
+		},
+		{
+			description: "not in",
+			fieldType:   reflect.TypeOf(0),
+			values:      []interface{}{1, 2},
+			expr:        "Field1 NOT IN (?, ?)",
+			expect:      `In0(Field1) == false`,
+		},
+		{
+			description: "in mixed literal and placeholder list",
+			fieldType:   reflect.TypeOf(0.0),
+			values:      []interface{}{2.5},
+			expr:        "Field1 IN (1, -2, ?) AND Field2 = 3",
+			expect:      `In0(Field1) && Field2 == 3.0`,
+		},
+		{
+			description: "in ptr with slice placeholder",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(uint(0))),
+			values:      []interface{}{[]int{1, 2}},
+			expr:        "NOT Field1 IN ?",
+			expect:      `Field1 != nil && In0(*Field1) == false`,
+		},
+		{
+			description: "not in with null",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "Field1 NOT IN (1, NULL)",
+			expect:      `1 == 0`,
+		},
+		{
+			description: "ptr is not null",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(0)),
+			expr:        "Field1 IS NOT NULL",
+			expect:      `Field1 != nil`,
+		},
+		{
+			description: "non nullable is not null",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "Field1 IS NOT NULL AND Field2 > 1",
+			expect:      `1 == 1 && Field2 > 1`,
+		},
+		{
+			description: "<> operator",
+			expr:        "Field1 <> 'abc'",
+			expect:      `Field1 != "abc"`,
+		},
+		{
+			description: "ptr != operator",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(0)),
+			expr:        "Field1 != 3",
+			expect:      `Field1 != nil && *Field1 != 3`,
+		},
+		{
+			description: "ptr comparison between fields",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(0)),
+			expr:        "Field1 >= Field2",
+			expect:      `Field1 != nil && Field2 != nil && *Field1 >= *Field2`,
+		},
+		{
+			description: "comparison with null literal",
+			expr:        "Field1 = NULL OR Field2 = 'x'",
+			expect:      `1 == 0 || Field2 == "x"`,
+		},
+		{
+			description: "and/or precedence",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "Field1 = 1 OR Field2 = 2 AND Field3 = 3",
+			expect:      `Field1 == 1 || Field2 == 2 && Field3 == 3`,
+		},
+		{
+			description: "parenthesis",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "(Field1 = 1 OR Field2 = 2) AND Field3 = 3",
+			expect:      `(Field1 == 1 || Field2 == 2) && Field3 == 3`,
+		},
+		{
+			description: "not",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "NOT Field1 = 1 AND Field2 < 2",
+			expect:      `Field1 != 1 && Field2 < 2`,
+		},
+		{
+			description: "not parenthesis",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "NOT (Field1 = 1 OR Field2 <= 2) AND Field3 = 3",
+			expect:      `((Field1 != 1) && (Field2 > 2)) && Field3 == 3`,
+		},
+		{
+			description: "not ptr keeps null semantic",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(0)),
+			expr:        "NOT (Field1 = 1)",
+			expect:      `(Field1 != nil && *Field1 != 1)`,
+		},
+		{
+			description: "not is null",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(0)),
+			expr:        "NOT Field1 IS NULL",
+			expect:      `Field1 != nil`,
+		},
+		{
+			description: "bool field",
+			fieldType:   reflect.TypeOf(true),
+			expr:        "Field1 AND NOT Field2",
+			expect:      `Field1 == true && Field2 == false`,
+		},
+		{
+			description: "bool ptr field",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(true)),
+			expr:        "NOT Field1",
+			expect:      `Field1 != nil && *Field1 == false`,
+		},
+		{
+			description: "quoted literal",
+			expr:        `Field1 = 'a"b''c'`,
+			expect:      `Field1 == Param0()`,
+		},
+		{
+			description: "between",
+			fieldType:   reflect.TypeOf(0.0),
+			values:      []interface{}{1, 2.5},
+			expr:        "Field1 BETWEEN ? AND ? AND Field2 NOT BETWEEN -1 AND 3",
+			expect:      `(FloatGe(Field1, float64(Param0())) && FloatLe(Field1, Param1())) && (FloatLt(Field2, (0.0 - 1.0)) || FloatGt(Field2, 3.0))`,
+		},
+		{
+			description: "negated between ptr",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(0)),
+			expr:        "NOT Field1 BETWEEN 1 AND 2 + 3",
+			expect:      `Field1 != nil && (*Field1 < 1 || *Field1 > 5)`,
+		},
+		{
+			description: "arithmetic with int to float promotion",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "Field1 * Field2 > 100.5 AND -Field3 < 2",
+			expect:      `FloatGt(float64(Field1 * Field2), 100.5) && (0 - Field3) < 2`,
+		},
+		{
+			description: "arithmetic on converted int field with division guard",
+			fieldType:   reflect.TypeOf(int64(0)),
+			expr:        "(Field1 - Field2) / Field3 > 3",
+			expect:      `AsInt(Field3) != 0 && (AsInt(Field1) - AsInt(Field2)) / AsInt(Field3) > 3`,
+		},
+		{
+			description: "float32 arithmetic with ptr",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(float32(0))),
+			expr:        "Field1 * 2 >= Field2",
+			expect:      `Field1 != nil && Field2 != nil && FloatGe(AsFloat(*Field1) * 2.0, AsFloat(*Field2))`,
+		},
+		{
+			description: "arithmetic with null",
+			fieldType:   reflect.TypeOf(0),
+			expr:        "Field1 + NULL > 1",
+			expect:      `1 == 0`,
+		},
+		{
+			description: "string scalar function",
+			expr:        "LOWER(Field1) = 'abc' AND LENGTH(Field2) > 2",
+			expect:      `LOWER_string(Field1) == "abc" && LENGTH_int(Field2) > 2`,
+		},
+		{
+			description: "math scalar function on ptr",
+			fieldType:   reflect.PtrTo(reflect.TypeOf(0)),
+			expr:        "ABS(Field1 - 10) > 5.5",
+			expect:      `Field1 != nil && FloatGt(float64(ABS_int(*Field1 - 10)), 5.5)`,
+		},
+		{
+			description: "null handling scalar function",
+			fieldType:   reflect.PtrTo(reflect.TypeOf("")),
+			expr:        "COALESCE(Field1, NULL, 'x') = 'x'",
+			expect:      `COALESCE_string(Field1, "x") == "x"`,
+		},
+		{
+			description: "scalar function with like",
+			expr:        "LOWER(Field1) LIKE 'a%'",
+			expect:      `Match1(LOWER_string(Field1))`,
+		},
+		{
+			description: "like",
+			expr:        "Field1 LIKE 'ab%' AND Field2 NOT LIKE ?",
+			values:      []interface{}{"%z"},
+			expect:      `Match0(Field1) && Match1(Field2) == false`,
+		},
+		{
+			description: "not ilike ptr",
+			fieldType:   reflect.PtrTo(reflect.TypeOf("")),
+			expr:        "NOT Field1 ILIKE 'a!_%' ESCAPE '!'",
+			expect:      `Field1 != nil && Match0(*Field1) == false`,
+		},
+		{
+			description: "like with backslash escape",
+			expr:        `Field1 LIKE 'a\%' ESCAPE '\' AND Field2 = 'c:\'`,
+			expect:      `Match0(Field1) && Field2 == Param1()`,
+		},
+		{
+			description: "string predicate functions",
+			values:      []interface{}{"x"},
+			expr:        "STARTS_WITH(Field1, 'a') OR ENDS_WITH(Field2, ?) OR NOT CONTAINS(Field3, 'b') AND Field4 = 'c'",
+			expect:      `Match0(Field1) || Match1(Field2) || Match2(Field3) == false && Field4 == "c"`,
+		},
+		{
+			description: "regexp like",
+			expr:        "REGEXP_LIKE(Field1, '^a.+z$', 'i')",
+			expect:      `Match0(Field1)`,
+		},
+		{
+			description: "collection predicates",
+			fieldType:   reflect.TypeOf([]struct{ Price int }{}),
+			expr:        "ANY(Items[Price > 100]) AND NOT EXISTS(Tags) OR ALL(Items[Price BETWEEN 1 AND 5])",
+			expect:      `Any0(Items) && Exists1(Tags) == false || All2(Items)`,
+		},
+		{
+			description: "cardinality ptr",
+			fieldType:   reflect.PtrTo(reflect.TypeOf([]string{})),
+			expr:        "CARDINALITY(Items) > 0",
+			expect:      `Items != nil && CARDINALITY_int(*Items) > 0`,
+		},
+		{
+			description: "named placeholders",
+			values:      []interface{}{map[string]interface{}{"name": "abc", "ids": []string{"x", "y"}}},
+			expr:        "Field1 = @name OR Field2 = :name AND Field3 IN (@ids)",
+			expect:      `Field1 == Param0() || Field2 == Param1() && In2(Field3)`,
+		},
+		{
+			description: "named string type",
+			fieldType:   reflect.TypeOf(Code("")),
+			values:      []interface{}{Code(`a"b\c`)},
+			expr:        "Field1 = ? OR Field2 <> 'x'",
+			expect:      `AsString(Field1) == Param1() || AsString(Field2) != "x"`,
+		},
+		{
+			description: "time parameter",
+			fieldType:   reflect.TypeOf(time.Time{}),
+			values:      []interface{}{time.Now()},
+			expr:        "Field1 BETWEEN ? AND Field2 OR Field3 = NULL",
+			expect:      `(TimeGe(Field1, Param0()) && TimeLe(Field1, Field2)) || 1 == 0`,
+		},
+		{
+			description: "array membership",
+			fieldType:   reflect.TypeOf([]string{}),
+			values:      []interface{}{"a", []string{"b", "c"}},
+			expr:        "'promo' IN Tags AND ARRAY_CONTAINS(Tags, ?) OR NOT ARRAY_INTERSECTS(Tags, ?) OR 'x' NOT IN Tags",
+			expect:      `Intersects0(Tags) && Intersects1(Tags) || Intersects2(Tags) == false || Intersects3(Tags) == false`,
+		},
+		{
+			description: "array functions",
+			fieldType:   reflect.TypeOf([]int{}),
+			expr:        "ARRAY_LENGTH(Segments) > 1 AND ARRAY_JOIN(Segments, '-') = '1-2' AND NULL IN Segments",
+			expect:      `ARRAY_LENGTH_int(Segments) > 1 && ARRAY_JOIN_string(Segments, "-") == "1-2" && 1 == 0`,
 		},
 	}
 
@@ -61,9 +309,12 @@ func TestQualifyExpr(t *testing.T) {
 		}
 
 		binding := &node.Binding{}
-		expr, _ := AsBinaryGoExpr("", qualify, func(name string) *xunsafe.Field {
+		expr, err := AsBinaryGoExpr("", qualify, func(name string) *xunsafe.Field {
 			return &xunsafe.Field{Name: name, Type: fType}
 		}, &node.Values{Bindings: binding, Values: testCase.values})
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
 		assert.EqualValues(t, testCase.expect, expr, testCase.description)
 	}
 }
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"github.com/viant/sqlparser"
+	del "github.com/viant/sqlparser/delete"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/query"
+	"github.com/viant/sqlparser/update"
+	"github.com/viant/structql/errs"
+	"strings"
+)
+
+// clauses terminating WHERE clause
+var criteriaTerminators = []*keyword{
+	newKeyword("group by"),
+	newKeyword("having"),
+	newKeyword("order by"),
+	newKeyword("limit"),
+	newKeyword("offset"),
+	newKeyword("union"),
+}
+
+var whereKeyword = newKeyword("where")
+
+// ParseQuery parses SQL query, WHERE clause is parsed with criteria parser
+func ParseQuery(SQL string) (*query.Select, error) {
+	statement, criteria, offset := SplitCriteria(SQL)
+	ret, err := sqlparser.ParseQuery(statement)
+	if err != nil {
+		return nil, errs.Syntax(err)
+	}
+	if strings.TrimSpace(criteria) == "" {
+		return ret, nil
+	}
+	x, err := ParseCriteria("", []byte(criteria), offset)
+	if err != nil {
+		return nil, err
+	}
+	ret.Qualify = &expr.Qualify{X: x}
+	return ret, nil
+}
+
+// ParseUpdate parses UPDATE statement, WHERE clause is parsed with criteria parser
+func ParseUpdate(SQL string) (*update.Statement, error) {
+	statement, criteria, offset := SplitCriteria(SQL)
+	ret, err := sqlparser.ParseUpdate(statement)
+	if err != nil {
+		return nil, errs.Syntax(err)
+	}
+	if ret.Qualify, err = parseQualify(criteria, offset); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ParseDelete parses DELETE statement, WHERE clause is parsed with criteria parser
+func ParseDelete(SQL string) (*del.Statement, error) {
+	statement, criteria, offset := SplitCriteria(SQL)
+	ret, err := sqlparser.ParseDelete(statement)
+	if err != nil {
+		return nil, errs.Syntax(err)
+	}
+	if ret.Qualify, err = parseQualify(criteria, offset); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// parseQualify parses WHERE clause criteria, it returns nil for empty criteria
+func parseQualify(criteria string, offset int) (*expr.Qualify, error) {
+	if strings.TrimSpace(criteria) == "" {
+		return nil, nil
+	}
+	x, err := ParseCriteria("", []byte(criteria), offset)
+	if err != nil {
+		return nil, err
+	}
+	return &expr.Qualify{X: x}, nil
+}
+
+// SplitCriteria splits top level WHERE clause from SQL statement, it returns statement without WHERE clause,
+// criteria and criteria offset in the original SQL
+func SplitCriteria(SQL string) (string, string, int) {
+	begin, end := -1, len(SQL)
+	input := []byte(SQL)
+	depth := 0
+	for i := 0; i < len(input); i++ {
+		switch input[i] {
+		case '\'', '"', '`':
+			i = skipQuoted(input, i)
+			continue
+		case '(', '[':
+			depth++
+			continue
+		case ')', ']':
+			depth--
+			continue
+		}
+		if depth > 0 || (i > 0 && isIdentByte(input[i-1])) {
+			continue
+		}
+		if begin == -1 {
+			if matched := whereKeyword.match(input, i); matched > 0 {
+				begin = i + matched
+				i = begin - 1
+			}
+			continue
+		}
+		for _, terminator := range criteriaTerminators {
+			if terminator.match(input, i) > 0 {
+				end = i
+				break
+			}
+		}
+		if end != len(SQL) {
+			break
+		}
+	}
+	if begin == -1 {
+		return SQL, "", 0
+	}
+	whereBegin := begin - len("where")
+	return SQL[:whereBegin] + " " + SQL[end:], SQL[begin:end], begin
+}
+
+// skipQuoted returns position of closing quote, doubled quote is skipped as escaped quote
+func skipQuoted(input []byte, pos int) int {
+	quote := input[pos]
+	for i := pos + 1; i < len(input); i++ {
+		if input[i] != quote {
+			continue
+		}
+		if i+1 < len(input) && input[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return len(input)
+}
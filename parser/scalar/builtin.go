@@ -0,0 +1,202 @@
+package scalar
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+func init() {
+	registerString()
+	registerMath()
+	registerDate()
+	registerNull()
+	registerCollection()
+}
+
+func registerString() {
+	unary := func(name string, fn func(string) string) {
+		register(&Function{Name: name, MinArgs: 1, MaxArgs: 1, Result: returns(String, String), Fn: func(args []interface{}) interface{} {
+			return fn(AsString(args[0]))
+		}})
+	}
+	unary("LOWER", strings.ToLower)
+	unary("UPPER", strings.ToUpper)
+	unary("TRIM", strings.TrimSpace)
+	unary("LTRIM", func(s string) string { return strings.TrimLeft(s, " \t\r\n") })
+	unary("RTRIM", func(s string) string { return strings.TrimRight(s, " \t\r\n") })
+	length := func(args []interface{}) interface{} {
+		return utf8.RuneCountInString(AsString(args[0]))
+	}
+	register(&Function{Name: "LENGTH", MinArgs: 1, MaxArgs: 1, Result: returns(Int, String), Fn: length})
+	register(&Function{Name: "CHAR_LENGTH", MinArgs: 1, MaxArgs: 1, Result: returns(Int, String), Fn: length})
+	register(&Function{Name: "CONCAT", MinArgs: 1, MaxArgs: -1, Result: returns(String, Any), Fn: func(args []interface{}) interface{} {
+		builder := strings.Builder{}
+		for _, arg := range args {
+			builder.WriteString(AsString(arg))
+		}
+		return builder.String()
+	}})
+	register(&Function{Name: "REPLACE", MinArgs: 3, MaxArgs: 3, Result: returns(String, String), Fn: func(args []interface{}) interface{} {
+		return strings.ReplaceAll(AsString(args[0]), AsString(args[1]), AsString(args[2]))
+	}})
+	substr := func(args []interface{}) interface{} {
+		runes := []rune(AsString(args[0]))
+		from := AsInt(args[1])
+		switch {
+		case from < 0:
+			from = len(runes) + from
+		case from > 0:
+			from--
+		}
+		if from < 0 {
+			from = 0
+		}
+		if from > len(runes) {
+			return ""
+		}
+		to := len(runes)
+		if len(args) > 2 {
+			if size := AsInt(args[2]); size < 0 {
+				to = from
+			} else if from+size < to {
+				to = from + size
+			}
+		}
+		return string(runes[from:to])
+	}
+	register(&Function{Name: "SUBSTR", MinArgs: 2, MaxArgs: 3, Result: returns(String, String, Int, Int), Fn: substr})
+	register(&Function{Name: "SUBSTRING", MinArgs: 2, MaxArgs: 3, Result: returns(String, String, Int, Int), Fn: substr})
+}
+
+func registerMath() {
+	unary := func(name string, intFn func(int) int, floatFn func(float64) float64) {
+		register(&Function{Name: name, MinArgs: 1, MaxArgs: 1, Result: numeric, Fn: func(args []interface{}) interface{} {
+			if value, ok := args[0].(float64); ok || isFloat(args[0]) {
+				if !ok {
+					value = AsFloat(args[0])
+				}
+				return floatFn(value)
+			}
+			return intFn(AsInt(args[0]))
+		}})
+	}
+	unary("ABS", func(v int) int {
+		if v < 0 {
+			return -v
+		}
+		return v
+	}, math.Abs)
+	unary("FLOOR", func(v int) int { return v }, math.Floor)
+	unary("CEIL", func(v int) int { return v }, math.Ceil)
+	unary("CEILING", func(v int) int { return v }, math.Ceil)
+	register(&Function{Name: "ROUND", MinArgs: 1, MaxArgs: 2, Result: numericFirst, Fn: func(args []interface{}) interface{} {
+		if !isFloat(args[0]) {
+			return AsInt(args[0])
+		}
+		scale := 1.0
+		if len(args) > 1 {
+			scale = math.Pow(10, float64(AsInt(args[1])))
+		}
+		return math.Round(AsFloat(args[0])*scale) / scale
+	}})
+	register(&Function{Name: "SIGN", MinArgs: 1, MaxArgs: 1, Result: returns(Int, Float), Fn: func(args []interface{}) interface{} {
+		value := AsFloat(args[0])
+		switch {
+		case value > 0:
+			return 1
+		case value < 0:
+			return -1
+		}
+		return 0
+	}})
+	register(&Function{Name: "MOD", MinArgs: 2, MaxArgs: 2, Result: numeric, Fn: func(args []interface{}) interface{} {
+		if isFloat(args[0]) || isFloat(args[1]) {
+			return math.Mod(AsFloat(args[0]), AsFloat(args[1]))
+		}
+		divisor := AsInt(args[1])
+		if divisor == 0 {
+			return 0
+		}
+		return AsInt(args[0]) % divisor
+	}})
+	power := func(args []interface{}) interface{} {
+		return math.Pow(AsFloat(args[0]), AsFloat(args[1]))
+	}
+	register(&Function{Name: "POWER", MinArgs: 2, MaxArgs: 2, Result: returns(Float, Float), Fn: power})
+	register(&Function{Name: "POW", MinArgs: 2, MaxArgs: 2, Result: returns(Float, Float), Fn: power})
+	register(&Function{Name: "SQRT", MinArgs: 1, MaxArgs: 1, Result: returns(Float, Float), Fn: func(args []interface{}) interface{} {
+		return math.Sqrt(AsFloat(args[0]))
+	}})
+}
+
+// numericFirst returns kind of the first numeric argument
+func numericFirst(args []Kind) (Kind, error) {
+	if _, err := numeric(args); err != nil {
+		return Any, err
+	}
+	return args[0], nil
+}
+
+func registerDate() {
+	part := func(name string, fn func(ts time.Time) int) {
+		register(&Function{Name: name, MinArgs: 1, MaxArgs: 1, Result: returns(Int, Time), Fn: func(args []interface{}) interface{} {
+			return fn(AsTime(args[0]))
+		}})
+	}
+	part("YEAR", func(ts time.Time) int { return ts.Year() })
+	part("MONTH", func(ts time.Time) int { return int(ts.Month()) })
+	part("DAY", func(ts time.Time) int { return ts.Day() })
+	part("HOUR", func(ts time.Time) int { return ts.Hour() })
+	part("MINUTE", func(ts time.Time) int { return ts.Minute() })
+	part("SECOND", func(ts time.Time) int { return ts.Second() })
+	part("DAYOFWEEK", func(ts time.Time) int { return int(ts.Weekday()) + 1 })
+	part("UNIX_TIMESTAMP", func(ts time.Time) int { return int(ts.Unix()) })
+}
+
+func registerNull() {
+	coalesce := func(args []interface{}) interface{} {
+		for _, arg := range args {
+			if !IsNil(arg) {
+				return arg
+			}
+		}
+		return nil
+	}
+	register(&Function{Name: "COALESCE", MinArgs: 1, MaxArgs: -1, Nullable: true, Result: common, Fn: coalesce})
+	register(&Function{Name: "IFNULL", MinArgs: 2, MaxArgs: 2, Nullable: true, Result: common, Fn: coalesce})
+}
+
+func registerCollection() {
+	length := func(args []interface{}) interface{} {
+		if rValue := indirect(args[0]); rValue.Kind() == reflect.Slice {
+			return rValue.Len()
+		}
+		return 0
+	}
+	register(&Function{Name: "CARDINALITY", MinArgs: 1, MaxArgs: 1, Result: returns(Int, Slice), Fn: length})
+	register(&Function{Name: "ARRAY_LENGTH", MinArgs: 1, MaxArgs: 1, Result: returns(Int, Slice), Fn: length})
+	register(&Function{Name: "ARRAY_JOIN", MinArgs: 2, MaxArgs: 2, Result: returns(String, Slice, String), Fn: func(args []interface{}) interface{} {
+		rValue := indirect(args[0])
+		if rValue.Kind() != reflect.Slice {
+			return ""
+		}
+		items := make([]string, 0, rValue.Len())
+		for i := 0; i < rValue.Len(); i++ {
+			if item := rValue.Index(i).Interface(); !IsNil(item) {
+				items = append(items, AsString(item))
+			}
+		}
+		return strings.Join(items, AsString(args[1]))
+	}})
+}
+
+func isFloat(value interface{}) bool {
+	switch value.(type) {
+	case float64, float32, *float64, *float32:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package scalar
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBuiltin(t *testing.T) {
+	text := "abc"
+	var testCases = []struct {
+		name   string
+		args   []interface{}
+		kinds  []Kind
+		expect interface{}
+	}{
+		{name: "lower", args: []interface{}{"AbC"}, kinds: []Kind{String}, expect: "abc"},
+		{name: "UPPER", args: []interface{}{"AbC"}, kinds: []Kind{String}, expect: "ABC"},
+		{name: "TRIM", args: []interface{}{" a "}, kinds: []Kind{String}, expect: "a"},
+		{name: "LENGTH", args: []interface{}{"żółw"}, kinds: []Kind{String}, expect: 4},
+		{name: "CONCAT", args: []interface{}{"a", 1, 2.5}, kinds: []Kind{String, Int, Float}, expect: "a12.5"},
+		{name: "REPLACE", args: []interface{}{"abab", "b", "c"}, kinds: []Kind{String, String, String}, expect: "acac"},
+		{name: "SUBSTR", args: []interface{}{"abcdef", 2, 3}, kinds: []Kind{String, Int, Int}, expect: "bcd"},
+		{name: "SUBSTRING", args: []interface{}{"abcdef", -2}, kinds: []Kind{String, Int}, expect: "ef"},
+		{name: "SUBSTR", args: []interface{}{"abc", 5}, kinds: []Kind{String, Int}, expect: ""},
+		{name: "ABS", args: []interface{}{-3}, kinds: []Kind{Int}, expect: 3},
+		{name: "ABS", args: []interface{}{-3.5}, kinds: []Kind{Float}, expect: 3.5},
+		{name: "ROUND", args: []interface{}{2.345, 2}, kinds: []Kind{Float, Int}, expect: 2.35},
+		{name: "ROUND", args: []interface{}{2.5}, kinds: []Kind{Float}, expect: 3.0},
+		{name: "FLOOR", args: []interface{}{2.5}, kinds: []Kind{Float}, expect: 2.0},
+		{name: "CEIL", args: []interface{}{2.1}, kinds: []Kind{Float}, expect: 3.0},
+		{name: "MOD", args: []interface{}{7, 3}, kinds: []Kind{Int, Int}, expect: 1},
+		{name: "POWER", args: []interface{}{2, 3}, kinds: []Kind{Int, Int}, expect: 8.0},
+		{name: "SIGN", args: []interface{}{-2.5}, kinds: []Kind{Float}, expect: -1},
+		{name: "YEAR", args: []interface{}{time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)}, kinds: []Kind{Time}, expect: 2024},
+		{name: "SECOND", args: []interface{}{time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)}, kinds: []Kind{Time}, expect: 7},
+		{name: "COALESCE", args: []interface{}{(*string)(nil), &text, "x"}, kinds: []Kind{String, String, String}, expect: &text},
+		{name: "IFNULL", args: []interface{}{(*int)(nil), 3}, kinds: []Kind{Int, Int}, expect: 3},
+	}
+	for _, testCase := range testCases {
+		function := Lookup(testCase.name)
+		if !assert.NotNil(t, function, testCase.name) {
+			continue
+		}
+		_, err := function.Validate(testCase.kinds)
+		if !assert.Nil(t, err, testCase.name) {
+			continue
+		}
+		assert.Equal(t, testCase.expect, function.Fn(testCase.args), testCase.name)
+	}
+}
+
+func TestFunction_Validate(t *testing.T) {
+	var testCases = []struct {
+		name   string
+		kinds  []Kind
+		expect Kind
+		hasErr bool
+	}{
+		{name: "LOWER", kinds: []Kind{String}, expect: String},
+		{name: "LOWER", kinds: []Kind{Int}, hasErr: true},
+		{name: "LOWER", kinds: []Kind{String, String}, hasErr: true},
+		{name: "ABS", kinds: []Kind{Int}, expect: Int},
+		{name: "ABS", kinds: []Kind{Float}, expect: Float},
+		{name: "ABS", kinds: []Kind{String}, hasErr: true},
+		{name: "MOD", kinds: []Kind{Int, Float}, expect: Float},
+		{name: "COALESCE", kinds: []Kind{Int, Float}, expect: Float},
+		{name: "COALESCE", kinds: []Kind{Int, String}, hasErr: true},
+		{name: "YEAR", kinds: []Kind{Time}, expect: Int},
+		{name: "SUBSTR", kinds: []Kind{String, Int}, expect: String},
+	}
+	for _, testCase := range testCases {
+		actual, err := Lookup(testCase.name).Validate(testCase.kinds)
+		if testCase.hasErr {
+			assert.NotNil(t, err, testCase.name)
+			continue
+		}
+		assert.Nil(t, err, testCase.name)
+		assert.Equal(t, testCase.expect, actual, testCase.name)
+	}
+}
@@ -0,0 +1,59 @@
+package scalar
+
+import (
+	"github.com/viant/igo/exec"
+	"unsafe"
+)
+
+type (
+	stringCaller func(args []interface{}) string
+	intCaller    func(args []interface{}) int
+	floatCaller  func(args []interface{}) float64
+	boolCaller   func(args []interface{}) bool
+)
+
+func (c stringCaller) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	result := c(values(ptr, args))
+	return unsafe.Pointer(&result)
+}
+
+func (c intCaller) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	result := c(values(ptr, args))
+	return unsafe.Pointer(&result)
+}
+
+func (c floatCaller) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	result := c(values(ptr, args))
+	return unsafe.Pointer(&result)
+}
+
+func (c boolCaller) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	result := c(values(ptr, args))
+	return unsafe.Pointer(&result)
+}
+
+func values(ptr unsafe.Pointer, args []*exec.Operand) []interface{} {
+	ret := make([]interface{}, len(args))
+	for i, arg := range args {
+		ret[i] = arg.Interface(arg.Compute(ptr))
+	}
+	return ret
+}
+
+// NewCaller returns igo callable function returning supplied kind, result is converted to the kind go type
+func NewCaller(kind Kind, fn Func) interface{} {
+	switch kind {
+	case String:
+		return stringCaller(func(args []interface{}) string { return AsString(fn(args)) })
+	case Int:
+		return intCaller(func(args []interface{}) int { return AsInt(fn(args)) })
+	case Float:
+		return floatCaller(func(args []interface{}) float64 { return AsFloat(fn(args)) })
+	case Bool:
+		return boolCaller(func(args []interface{}) bool {
+			ret, _ := fn(args).(bool)
+			return ret
+		})
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package scalar
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// AsInt converts numeric value to int
+func AsInt(value interface{}) int {
+	switch actual := value.(type) {
+	case int:
+		return actual
+	case int64:
+		return int(actual)
+	case int32:
+		return int(actual)
+	case uint:
+		return int(actual)
+	case uint64:
+		return int(actual)
+	case float64:
+		return int(actual)
+	}
+	rValue := indirect(value)
+	switch rValue.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rValue.Uint())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rValue.Int())
+	case reflect.Float32, reflect.Float64:
+		return int(rValue.Float())
+	}
+	return 0
+}
+
+// AsFloat converts numeric value to float64
+func AsFloat(value interface{}) float64 {
+	switch actual := value.(type) {
+	case float64:
+		return actual
+	case float32:
+		return float64(actual)
+	case int:
+		return float64(actual)
+	}
+	rValue := indirect(value)
+	switch rValue.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rValue.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rValue.Uint())
+	}
+	return 0
+}
+
+// AsString converts value to string
+func AsString(value interface{}) string {
+	switch actual := value.(type) {
+	case string:
+		return actual
+	case *string:
+		if actual == nil {
+			return ""
+		}
+		return *actual
+	}
+	rValue := indirect(value)
+	switch rValue.Kind() {
+	case reflect.Invalid:
+		return ""
+	case reflect.String:
+		return rValue.String()
+	}
+	return fmt.Sprintf("%v", rValue.Interface())
+}
+
+// AsTime converts value to time.Time
+func AsTime(value interface{}) time.Time {
+	switch actual := value.(type) {
+	case time.Time:
+		return actual
+	case *time.Time:
+		if actual == nil {
+			return time.Time{}
+		}
+		return *actual
+	}
+	return time.Time{}
+}
+
+// IsNil returns true if value is nil or nil pointer
+func IsNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rValue := reflect.ValueOf(value)
+	return rValue.Kind() == reflect.Ptr && rValue.IsNil()
+}
+
+func indirect(value interface{}) reflect.Value {
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Ptr && !rValue.IsNil() {
+		rValue = rValue.Elem()
+	}
+	return rValue
+}
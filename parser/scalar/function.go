@@ -0,0 +1,105 @@
+package scalar
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type (
+	// Func represents scalar function implementation, arguments are computed values
+	Func func(args []interface{}) interface{}
+
+	// Function represents SQL scalar function
+	Function struct {
+		Name     string
+		MinArgs  int
+		MaxArgs  int  //-1 for variadic function
+		Nullable bool //nullable arguments are passed as is, otherwise nil arguments exclude a record
+		Result   func(args []Kind) (Kind, error)
+		Fn       Func
+	}
+)
+
+// Validate checks arguments count and kinds, it returns result kind
+func (f *Function) Validate(args []Kind) (Kind, error) {
+	if len(args) < f.MinArgs || (f.MaxArgs != -1 && len(args) > f.MaxArgs) {
+		return Any, fmt.Errorf("invalid %v arguments count: %v", f.Name, len(args))
+	}
+	return f.Result(args)
+}
+
+var (
+	functions = map[string]*Function{}
+	mux       sync.RWMutex
+)
+
+// Lookup returns function for supplied name or nil
+func Lookup(name string) *Function {
+	mux.RLock()
+	defer mux.RUnlock()
+	return functions[strings.ToUpper(name)]
+}
+
+// Register registers global function, function with the same name is replaced
+func Register(function *Function) {
+	mux.Lock()
+	defer mux.Unlock()
+	register(function)
+}
+
+func register(function *Function) {
+	functions[strings.ToUpper(function.Name)] = function
+}
+
+// returns returns result resolver checking that all arguments match expected kind
+func returns(result Kind, expected ...Kind) func(args []Kind) (Kind, error) {
+	return func(args []Kind) (Kind, error) {
+		for i, arg := range args {
+			expect := expected[len(expected)-1]
+			if i < len(expected) {
+				expect = expected[i]
+			}
+			if !matches(expect, arg) {
+				return Any, fmt.Errorf("invalid argument %v kind: %v, expected %v", i+1, arg, expect)
+			}
+		}
+		return result, nil
+	}
+}
+
+// numeric returns result resolver for numeric functions, result is Float if any argument is Float
+func numeric(args []Kind) (Kind, error) {
+	result := Int
+	for i, arg := range args {
+		if !arg.IsNumeric() {
+			return Any, fmt.Errorf("invalid argument %v kind: %v, expected numeric", i+1, arg)
+		}
+		if arg == Float {
+			result = Float
+		}
+	}
+	return result, nil
+}
+
+// common returns result resolver for functions returning one of its arguments
+func common(args []Kind) (Kind, error) {
+	result := args[0]
+	for i, arg := range args[1:] {
+		switch {
+		case arg == result:
+		case arg.IsNumeric() && result.IsNumeric():
+			result = Float
+		default:
+			return Any, fmt.Errorf("invalid argument %v kind: %v, expected %v", i+2, arg, result)
+		}
+	}
+	return result, nil
+}
+
+func matches(expected, actual Kind) bool {
+	if expected == Float {
+		return actual.IsNumeric()
+	}
+	return expected == Any || expected == actual
+}
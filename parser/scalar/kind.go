@@ -0,0 +1,112 @@
+package scalar
+
+import (
+	"reflect"
+	"time"
+)
+
+// Kind represents scalar function argument or result kind
+type Kind int
+
+const (
+	// Any represents unsupported or unknown kind
+	Any Kind = iota
+	// String represents string kind
+	String
+	// Int represents int kind
+	Int
+	// Float represents float64 kind
+	Float
+	// Bool represents bool kind
+	Bool
+	// Time represents time.Time kind
+	Time
+	// Slice represents slice kind
+	Slice
+)
+
+// IsNumeric returns true for Int and Float kinds
+func (k Kind) IsNumeric() bool {
+	return k == Int || k == Float
+}
+
+// String returns kind name
+func (k Kind) String() string {
+	switch k {
+	case String:
+		return "string"
+	case Int:
+		return "int"
+	case Float:
+		return "float"
+	case Bool:
+		return "bool"
+	case Time:
+		return "time"
+	case Slice:
+		return "slice"
+	}
+	return "any"
+}
+
+// Type returns kind go type, Any and Slice kinds are represented by interface{}
+func (k Kind) Type() reflect.Type {
+	switch k {
+	case String:
+		return reflect.TypeOf("")
+	case Int:
+		return reflect.TypeOf(0)
+	case Float:
+		return reflect.TypeOf(0.0)
+	case Bool:
+		return reflect.TypeOf(true)
+	case Time:
+		return reflect.TypeOf(time.Time{})
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
+
+// Convert converts value to the kind go type, nil value is returned as is
+func (k Kind) Convert(value interface{}) interface{} {
+	if IsNil(value) {
+		return nil
+	}
+	switch k {
+	case String:
+		return AsString(value)
+	case Int:
+		return AsInt(value)
+	case Float:
+		return AsFloat(value)
+	case Bool:
+		ret, _ := indirect(value).Interface().(bool)
+		return ret
+	case Time:
+		return AsTime(value)
+	}
+	return value
+}
+
+// KindOf returns kind for supplied type, pointer type is dereferenced
+func KindOf(rType reflect.Type) Kind {
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	switch rType.Kind() {
+	case reflect.String:
+		return String
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Int
+	case reflect.Float32, reflect.Float64:
+		return Float
+	case reflect.Bool:
+		return Bool
+	case reflect.Slice:
+		return Slice
+	}
+	if rType.ConvertibleTo(reflect.TypeOf(time.Time{})) {
+		return Time
+	}
+	return Any
+}
@@ -0,0 +1,157 @@
+package scalar
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// Registry represents query scoped functions, functions not found are looked up in global registry
+	Registry struct {
+		functions map[string]*Function
+	}
+
+	// FunctionError represents error returned by user defined function, it is raised as panic
+	// while evaluating a query and recovered with Recover
+	FunctionError struct {
+		Name string
+		Err  error
+	}
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Error returns error message
+func (e *FunctionError) Error() string {
+	return fmt.Sprintf("function %v failed: %v", e.Name, e.Err)
+}
+
+// Unwrap returns function error
+func (e *FunctionError) Unwrap() error {
+	return e.Err
+}
+
+// Recover converts FunctionError panic into error, other panics are re-raised, it has to be deferred
+func Recover(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	fnErr, ok := r.(*FunctionError)
+	if !ok {
+		panic(r)
+	}
+	*err = fnErr
+}
+
+// Register registers query scoped function
+func (r *Registry) Register(function *Function) {
+	if r.functions == nil {
+		r.functions = map[string]*Function{}
+	}
+	r.functions[strings.ToUpper(function.Name)] = function
+}
+
+// Lookup returns query scoped or global function for supplied name or nil
+func (r *Registry) Lookup(name string) *Function {
+	if r != nil {
+		if ret, ok := r.functions[strings.ToUpper(name)]; ok {
+			return ret
+		}
+	}
+	return Lookup(name)
+}
+
+// NewFunction returns function for go func, func can be variadic and can return a value or a value and an error.
+// Argument kinds are checked against func parameters when a query is compiled, argument values are converted to parameter types.
+func NewFunction(name string, fn interface{}) (*Function, error) {
+	fnValue := reflect.ValueOf(fn)
+	if name == "" {
+		return nil, fmt.Errorf("function name was empty")
+	}
+	if fnValue.Kind() != reflect.Func || fnValue.IsNil() {
+		return nil, fmt.Errorf("invalid function %v: %T, expected func", name, fn)
+	}
+	fnType := fnValue.Type()
+	switch fnType.NumOut() {
+	case 1:
+	case 2:
+		if fnType.Out(1) != errorType {
+			return nil, fmt.Errorf("invalid function %v: %s, expected the second result to be error", name, fnType.String())
+		}
+	default:
+		return nil, fmt.Errorf("invalid function %v: %s, expected a result or a result and error", name, fnType.String())
+	}
+	var params []reflect.Type
+	var kinds []Kind
+	for i := 0; i < fnType.NumIn(); i++ {
+		param := fnType.In(i)
+		if fnType.IsVariadic() && i == fnType.NumIn()-1 {
+			param = param.Elem()
+		}
+		params = append(params, param)
+		kinds = append(kinds, paramKind(param))
+	}
+	result := KindOf(fnType.Out(0))
+	ret := &Function{Name: strings.ToUpper(name), MinArgs: len(params), MaxArgs: len(params)}
+	if fnType.IsVariadic() {
+		ret.MinArgs, ret.MaxArgs = len(params)-1, -1
+	}
+	ret.Result = func(args []Kind) (Kind, error) {
+		for i, arg := range args {
+			expect := kinds[len(kinds)-1]
+			if i < len(kinds) {
+				expect = kinds[i]
+			}
+			if !matches(expect, arg) {
+				return Any, fmt.Errorf("invalid argument %v kind: %v, expected %v", i+1, arg, expect)
+			}
+		}
+		return result, nil
+	}
+	ret.Fn = func(args []interface{}) interface{} {
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			param := params[len(params)-1]
+			if i < len(params) {
+				param = params[i]
+			}
+			in[i] = argValue(arg, param)
+		}
+		out := fnValue.Call(in)
+		if len(out) == 2 && !out[1].IsNil() {
+			panic(&FunctionError{Name: ret.Name, Err: out[1].Interface().(error)})
+		}
+		return out[0].Interface()
+	}
+	return ret, nil
+}
+
+// paramKind returns parameter kind, interface parameter accepts any argument
+func paramKind(param reflect.Type) Kind {
+	if param.Kind() == reflect.Interface {
+		return Any
+	}
+	return KindOf(param)
+}
+
+// argValue returns argument value converted to parameter type
+func argValue(arg interface{}, param reflect.Type) reflect.Value {
+	if arg == nil {
+		return reflect.Zero(param)
+	}
+	ret := reflect.ValueOf(arg)
+	if ret.Type().AssignableTo(param) {
+		return ret
+	}
+	if param.Kind() == reflect.Ptr {
+		ptr := reflect.New(param.Elem())
+		ptr.Elem().Set(argValue(arg, param.Elem()))
+		return ptr
+	}
+	if ret.Kind() == reflect.Ptr {
+		return argValue(ret.Elem().Interface(), param)
+	}
+	return ret.Convert(param)
+}
@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"github.com/viant/igo/exec"
+	"time"
+	"unsafe"
+)
+
+// timePredicate represents time.Time comparison callable by igo, igo does not support struct comparison
+type timePredicate func(x, y time.Time) bool
+
+// Call calls the predicate with computed arguments
+func (p timePredicate) Call(ptr unsafe.Pointer, args []*exec.Operand) unsafe.Pointer {
+	result := p(*(*time.Time)(args[0].Compute(ptr)), *(*time.Time)(args[1].Compute(ptr)))
+	return unsafe.Pointer(&result)
+}
+
+var timeComparisons = map[string]struct {
+	name string
+	fn   timePredicate
+}{
+	"==": {"TimeEq", func(x, y time.Time) bool { return x.Equal(y) }},
+	"!=": {"TimeNe", func(x, y time.Time) bool { return !x.Equal(y) }},
+	"<":  {"TimeLt", func(x, y time.Time) bool { return x.Before(y) }},
+	"<=": {"TimeLe", func(x, y time.Time) bool { return !x.After(y) }},
+	">":  {"TimeGt", func(x, y time.Time) bool { return x.After(y) }},
+	">=": {"TimeGe", func(x, y time.Time) bool { return !x.Before(y) }},
+}
@@ -0,0 +1,253 @@
+package parser
+
+import (
+	"github.com/viant/structql/errs"
+	"strconv"
+)
+
+type (
+	// Window represents window function OVER clause
+	Window struct {
+		Partition []string
+		Order     []*WindowOrder
+		Frame     *Frame //nil for the default frame
+	}
+
+	// WindowOrder represents window ORDER BY item
+	WindowOrder struct {
+		Column string
+		Desc   bool
+	}
+
+	// Frame represents ROWS frame, bounds are relative to the current row
+	Frame struct {
+		Start FrameBound
+		End   FrameBound
+	}
+
+	// FrameBound represents frame bound, negative offset is PRECEDING, positive FOLLOWING and zero CURRENT ROW
+	FrameBound struct {
+		Offset    int
+		Unbounded bool
+	}
+)
+
+var (
+	overKeyword         = newKeyword("over")
+	fromKeyword         = newKeyword("from")
+	partitionKeyword    = newKeyword("partition by")
+	orderKeyword        = newKeyword("order by")
+	ascKeyword          = newKeyword("asc")
+	descKeyword         = newKeyword("desc")
+	rowsKeyword         = newKeyword("rows")
+	frameBetweenKeyword = newKeyword("between")
+	frameAndKeyword     = newKeyword("and")
+	unboundedKeyword    = newKeyword("unbounded")
+	precedingKeyword    = newKeyword("preceding")
+	followingKeyword    = newKeyword("following")
+	currentRowKeyword   = newKeyword("current row")
+	windowListKeywords  = []*keyword{orderKeyword, rowsKeyword}
+)
+
+// SplitWindows extracts window function OVER clauses from SELECT projection, it returns SQL with OVER clauses
+// replaced by whitespace, so the remaining positions are preserved, and windows by projection item index
+func SplitWindows(SQL string) (string, map[int]*Window, error) {
+	input := []byte(SQL)
+	begin := -1
+	for i := 0; i < len(input) && begin == -1; i++ {
+		switch input[i] {
+		case '\'', '"', '`':
+			i = skipQuoted(input, i)
+			continue
+		}
+		if (i == 0 || !isIdentByte(input[i-1])) && selectKeyword.match(input, i) > 0 {
+			begin = i + len("select")
+		}
+	}
+	if begin == -1 {
+		return SQL, nil, nil
+	}
+	var ret map[int]*Window
+	item, depth := 0, 0
+outer:
+	for i := begin; i < len(input); i++ {
+		switch input[i] {
+		case '\'', '"', '`':
+			i = skipQuoted(input, i)
+			continue
+		case '(', '[':
+			depth++
+			continue
+		case ')', ']':
+			depth--
+			continue
+		case ',':
+			if depth == 0 {
+				item++
+			}
+			continue
+		}
+		if depth > 0 || isIdentByte(input[i-1]) {
+			continue
+		}
+		if fromKeyword.match(input, i) > 0 {
+			break outer
+		}
+		matched := overKeyword.match(input, i)
+		if matched == 0 {
+			continue
+		}
+		open := skipWhitespace(input, i+matched)
+		if open >= len(input) || input[open] != '(' {
+			continue //OVER used as identifier
+		}
+		end := closingParenthesis(input, open)
+		if end == -1 {
+			return "", nil, errs.Syntaxf(open, "invalid window, unterminated clause at pos: %d", open)
+		}
+		window, err := parseWindow(input, open+1, end)
+		if err != nil {
+			return "", nil, err
+		}
+		if ret == nil {
+			ret = map[int]*Window{}
+		}
+		ret[item] = window
+		for k := i; k <= end; k++ {
+			input[k] = ' '
+		}
+		i = end
+	}
+	return string(input), ret, nil
+}
+
+// parseWindow parses OVER clause content: [PARTITION BY column, ...] [ORDER BY column [ASC|DESC], ...] [ROWS frame]
+func parseWindow(input []byte, begin, end int) (*Window, error) {
+	ret := &Window{}
+	pos := skipWhitespace(input, begin)
+	var err error
+	if matched := partitionKeyword.match(input[:end], pos); matched > 0 {
+		for {
+			var column string
+			if column, pos, err = windowColumn(input, skipWhitespace(input, pos+matched), end); err != nil {
+				return nil, err
+			}
+			ret.Partition = append(ret.Partition, column)
+			if matched = windowListSeparator(input, pos, end); matched == 0 {
+				break
+			}
+		}
+	}
+	if matched := orderKeyword.match(input[:end], pos); matched > 0 {
+		for {
+			item := &WindowOrder{}
+			if item.Column, pos, err = windowColumn(input, skipWhitespace(input, pos+matched), end); err != nil {
+				return nil, err
+			}
+			if matched = ascKeyword.match(input[:end], pos); matched > 0 {
+				pos = skipWhitespace(input, pos+matched)
+			} else if matched = descKeyword.match(input[:end], pos); matched > 0 {
+				item.Desc = true
+				pos = skipWhitespace(input, pos+matched)
+			}
+			ret.Order = append(ret.Order, item)
+			if matched = windowListSeparator(input, pos, end); matched == 0 {
+				break
+			}
+		}
+	}
+	if matched := rowsKeyword.match(input[:end], pos); matched > 0 {
+		if ret.Frame, pos, err = parseFrame(input, skipWhitespace(input, pos+matched), end); err != nil {
+			return nil, err
+		}
+	}
+	if pos < end {
+		return nil, errs.Syntaxf(pos, "invalid window, unexpected token at pos: %d", pos)
+	}
+	return ret, nil
+}
+
+// windowListSeparator returns comma length when another list item follows, or 0
+func windowListSeparator(input []byte, pos, end int) int {
+	if pos < end && input[pos] == ',' {
+		return 1
+	}
+	return 0
+}
+
+// windowColumn parses column name, it returns column and the following non whitespace position
+func windowColumn(input []byte, pos, end int) (string, int, error) {
+	begin := pos
+	for pos < end && (isIdentByte(input[pos]) || input[pos] == '.') {
+		pos++
+	}
+	column := string(input[begin:pos])
+	if column == "" {
+		return "", 0, errs.Syntaxf(begin, "invalid window, expected: column at pos: %d", begin)
+	}
+	for _, reserved := range windowListKeywords {
+		if reserved.match(input[:end], begin) > 0 {
+			return "", 0, errs.Syntaxf(begin, "invalid window, expected: column at pos: %d", begin)
+		}
+	}
+	return column, skipWhitespace(input, pos), nil
+}
+
+// parseFrame parses ROWS frame: BETWEEN bound AND bound, or a start bound ending with the current row
+func parseFrame(input []byte, pos, end int) (*Frame, int, error) {
+	ret := &Frame{}
+	var err error
+	matched := frameBetweenKeyword.match(input[:end], pos)
+	if matched == 0 {
+		if ret.Start, pos, err = parseFrameBound(input, pos, end); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		if ret.Start, pos, err = parseFrameBound(input, skipWhitespace(input, pos+matched), end); err != nil {
+			return nil, 0, err
+		}
+		if matched = frameAndKeyword.match(input[:end], pos); matched == 0 {
+			return nil, 0, errs.Syntaxf(pos, "invalid window frame, expected: AND at pos: %d", pos)
+		}
+		if ret.End, pos, err = parseFrameBound(input, skipWhitespace(input, pos+matched), end); err != nil {
+			return nil, 0, err
+		}
+	}
+	if (ret.Start.Unbounded && ret.Start.Offset > 0) || (ret.End.Unbounded && ret.End.Offset < 0) ||
+		(!ret.Start.Unbounded && !ret.End.Unbounded && ret.Start.Offset > ret.End.Offset) {
+		return nil, 0, errs.Syntaxf(pos, "invalid window frame, start bound follows end bound at pos: %d", pos)
+	}
+	return ret, pos, nil
+}
+
+// parseFrameBound parses UNBOUNDED PRECEDING|FOLLOWING, N PRECEDING|FOLLOWING or CURRENT ROW
+func parseFrameBound(input []byte, pos, end int) (FrameBound, int, error) {
+	ret := FrameBound{}
+	if matched := currentRowKeyword.match(input[:end], pos); matched > 0 {
+		return ret, skipWhitespace(input, pos+matched), nil
+	}
+	if matched := unboundedKeyword.match(input[:end], pos); matched > 0 {
+		ret.Unbounded = true
+		ret.Offset = 1
+		pos = skipWhitespace(input, pos+matched)
+	} else {
+		begin := pos
+		for pos < end && input[pos] >= '0' && input[pos] <= '9' {
+			pos++
+		}
+		offset, err := strconv.Atoi(string(input[begin:pos]))
+		if err != nil {
+			return ret, 0, errs.Syntaxf(begin, "invalid window frame, expected: bound at pos: %d", begin)
+		}
+		ret.Offset = offset
+		pos = skipWhitespace(input, pos)
+	}
+	if matched := precedingKeyword.match(input[:end], pos); matched > 0 {
+		ret.Offset = -ret.Offset
+		return ret, skipWhitespace(input, pos+matched), nil
+	}
+	if matched := followingKeyword.match(input[:end], pos); matched > 0 {
+		return ret, skipWhitespace(input, pos+matched), nil
+	}
+	return ret, 0, errs.Syntaxf(pos, "invalid window frame, expected: PRECEDING or FOLLOWING at pos: %d", pos)
+}
@@ -0,0 +1,70 @@
+package parser
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSplitWindows(t *testing.T) {
+	var testCases = []struct {
+		description string
+		SQL         string
+		expectSQL   string
+		expect      map[int]*Window
+		hasError    bool
+	}{
+		{
+			description: "no windows",
+			SQL:         "SELECT ID, 'over (x)' AS Over FROM `/` WHERE ID > 1",
+			expectSQL:   "SELECT ID, 'over (x)' AS Over FROM `/` WHERE ID > 1",
+		},
+		{
+			description: "partition order and frame",
+			SQL:         "SELECT ID, SUM(Revenue) OVER (PARTITION BY VendorID, Kind ORDER BY Day DESC, ID ROWS BETWEEN 2 PRECEDING AND UNBOUNDED FOLLOWING) AS S, RANK() over(order by ID) AS R FROM `/`",
+			expectSQL:   "SELECT ID, SUM(Revenue)                                                                                                           AS S, RANK()                   AS R FROM `/`",
+			expect: map[int]*Window{
+				1: {
+					Partition: []string{"VendorID", "Kind"},
+					Order:     []*WindowOrder{{Column: "Day", Desc: true}, {Column: "ID"}},
+					Frame:     &Frame{Start: FrameBound{Offset: -2}, End: FrameBound{Offset: 1, Unbounded: true}},
+				},
+				2: {Order: []*WindowOrder{{Column: "ID"}}},
+			},
+		},
+		{
+			description: "start bound frame",
+			SQL:         "SELECT CONCAT(Name, ','), FIRST_VALUE(ID) OVER (ROWS UNBOUNDED PRECEDING) FROM `/`",
+			expectSQL:   "SELECT CONCAT(Name, ','), FIRST_VALUE(ID)                                 FROM `/`",
+			expect: map[int]*Window{
+				1: {Frame: &Frame{Start: FrameBound{Offset: -1, Unbounded: true}}},
+			},
+		},
+		{
+			description: "unterminated clause",
+			SQL:         "SELECT RANK() OVER (ORDER BY ID FROM `/`",
+			hasError:    true,
+		},
+		{
+			description: "invalid frame bound",
+			SQL:         "SELECT SUM(ID) OVER (ROWS BETWEEN 1 PRECEDING AND NOW) FROM `/`",
+			hasError:    true,
+		},
+		{
+			description: "unexpected token",
+			SQL:         "SELECT RANK() OVER (ORDER BY ID LIMIT 1) FROM `/`",
+			hasError:    true,
+		},
+	}
+	for _, testCase := range testCases {
+		actualSQL, actual, err := SplitWindows(testCase.SQL)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectSQL, actualSQL, testCase.description)
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+}
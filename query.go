@@ -1,15 +1,21 @@
 package structql
 
 import (
+	"context"
 	"fmt"
 	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	snode "github.com/viant/sqlparser/node"
 	"github.com/viant/sqlparser/query"
+	"github.com/viant/structql/errs"
 	node "github.com/viant/structql/node"
 	sparser "github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
 	"github.com/viant/xunsafe"
 	"reflect"
 	"strconv"
 	"strings"
+	"unsafe"
 )
 
 // Query represents a selector
@@ -24,6 +30,7 @@ type Query struct {
 	walker    *Walker
 	CompType  reflect.Type
 	Binding   *node.Binding
+	limits    Limits
 }
 
 // Type returns dest slice type
@@ -38,13 +45,14 @@ func (s *Query) StructType() reflect.Type {
 
 // Select returns selection result
 func (s *Query) Select(source interface{}) (interface{}, error) {
-	destSlicePtrValue := reflect.New(s.destSlice.Type)
-	sourceLen := s.walker.Count(source)
-	destSlicePtrValue.Elem().Set(reflect.MakeSlice(s.destSlice.Type, 0, sourceLen))
-	destSlicePtr := destSlicePtrValue.Interface()
-	destPtr := xunsafe.AsPointer(destSlicePtr)
-	appender := s.destSlice.Appender(destPtr)
-	if err := s.mapper.Map(s.walker, source, appender); err != nil {
+	return s.SelectContext(context.Background(), source)
+}
+
+// SelectContext returns selection result, the walk is stopped with context error when context is done
+// or with LimitError when query resource cap is exceeded
+func (s *Query) SelectContext(ctx context.Context, source interface{}) (interface{}, error) {
+	destSlicePtr, _, err := s.selectContext(ctx, source)
+	if err != nil {
 		return nil, err
 	}
 	return destSlicePtr, nil
@@ -52,13 +60,14 @@ func (s *Query) Select(source interface{}) (interface{}, error) {
 
 // First returns the first selection result
 func (s *Query) First(source interface{}) (interface{}, error) {
-	destSlicePtrValue := reflect.New(s.destSlice.Type)
-	sourceLen := s.walker.Count(source)
-	destSlicePtrValue.Elem().Set(reflect.MakeSlice(s.destSlice.Type, 0, sourceLen))
-	destSlicePtr := destSlicePtrValue.Interface()
-	destPtr := xunsafe.AsPointer(destSlicePtr)
-	appender := s.destSlice.Appender(destPtr)
-	if err := s.mapper.Map(s.walker, source, appender); err != nil {
+	return s.FirstContext(context.Background(), source)
+}
+
+// FirstContext returns the first selection result, the walk is stopped with context error when context is done
+// or with LimitError when query resource cap is exceeded
+func (s *Query) FirstContext(ctx context.Context, source interface{}) (interface{}, error) {
+	_, destPtr, err := s.selectContext(ctx, source)
+	if err != nil {
 		return nil, err
 	}
 	if s.destSlice.Len(destPtr) == 0 {
@@ -67,6 +76,38 @@ func (s *Query) First(source interface{}) (interface{}, error) {
 	return s.destSlice.ValuePointerAt(destPtr, 0), nil
 }
 
+// Limits returns query resource caps
+func (s *Query) Limits() Limits {
+	return s.limits
+}
+
+func (s *Query) selectContext(ctx context.Context, source interface{}) (destSlicePtr interface{}, destPtr unsafe.Pointer, err error) {
+	defer scalar.Recover(&err)
+	aGuard := newGuard(ctx, s.limits) //nodes visited by both count and map walks are accounted together
+	sourceLen, err := s.walker.count(aGuard, s.walker.root, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.limits.MaxRows > 0 && sourceLen > s.limits.MaxRows {
+		sourceLen = s.limits.MaxRows
+	}
+	destSlicePtrValue := reflect.New(s.destSlice.Type)
+	destSlicePtrValue.Elem().Set(reflect.MakeSlice(s.destSlice.Type, 0, sourceLen))
+	destSlicePtr = destSlicePtrValue.Interface()
+	destPtr = xunsafe.AsPointer(destSlicePtr)
+	appender := s.destSlice.Appender(destPtr)
+	sources, err := s.mapper.mapWithGuard(aGuard, s.walker, source, appender)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(s.mapper.windows) > 0 {
+		if err = s.mapper.computeWindows(sources, s.destSlice, destPtr); err != nil {
+			return nil, nil, err
+		}
+	}
+	return destSlicePtr, destPtr, nil
+}
+
 func unwrapStruct(p reflect.Type) reflect.Type {
 	if p == nil {
 		return nil
@@ -86,25 +127,38 @@ func unwrapStruct(p reflect.Type) reflect.Type {
 func NewQuery(query string, source, dest reflect.Type, values ...interface{}) (*Query, error) {
 	var err error
 	if unwrapStruct(source) == nil {
-		return nil, fmt.Errorf("invalid source type: %s", source.String())
+		return nil, errs.UnsupportedType("invalid source type: %s", source.String())
 	}
-	ret := &Query{query: query, source: source, Binding: &node.Binding{}}
-	value := &node.Values{Values: values, Bindings: ret.Binding}
-
-	if ret.sel, err = sqlparser.ParseQuery(query); err != nil {
-		return nil, fmt.Errorf("failed to parse %w, %v", err, query)
+	opts, values, err := newOptions(values)
+	if err != nil {
+		return nil, err
 	}
-	from := strings.Trim(sqlparser.Stringify(ret.sel.From.X), "`")
-	sel, err := sparser.ParseSelector(from)
+	value := &node.Values{Values: values, Bindings: &node.Binding{}, Functions: opts.functions}
+	ret, err := newQuery(query, source, dest, value, opts)
 	if err != nil {
-		return nil, fmt.Errorf("invalid from: %w, %v", err, from)
+		return nil, err
+	}
+	if err = value.Validate(); err != nil {
+		return nil, err
 	}
+	return ret, nil
+}
 
-	if ret.node, err = NewNode(source, sel, value); err != nil {
+// newQuery returns a selector compiled with supplied values, values usage is validated by the caller
+func newQuery(query string, source, dest reflect.Type, value *node.Values, opts *options) (*Query, error) {
+	ret := &Query{query: query, source: source, Binding: value.Bindings, limits: opts.limits}
+	statement, windows, err := sparser.SplitWindows(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %w, %v", err, query)
+	}
+	if ret.sel, err = sparser.ParseQuery(statement); err != nil {
+		return nil, fmt.Errorf("failed to parse %w, %v", err, query)
+	}
+	if ret.node, err = newSelectorNode(source, ret.sel.From.X, value, opts); err != nil {
 		return nil, err
 	}
 	src := unwrapStruct(ret.node.LeafType())
-	if ret.mapper, err = NewMapper(src, unwrapStruct(dest), ret.sel); err != nil {
+	if ret.mapper, err = newMapper(src, unwrapStruct(dest), ret.sel, opts.functions, opts.aggregates, windows, opts.tags); err != nil {
 		return nil, err
 	}
 	if limit := ret.sel.Limit; limit != nil {
@@ -120,15 +174,37 @@ func NewQuery(query string, source, dest reflect.Type, values ...interface{}) (*
 	}
 	ret.destSlice = xunsafe.NewSlice(dest)
 
-	if ret.sel.Qualify != nil {
-		leaf := ret.node.Leaf()
-
-		if leaf.expr != nil {
-			return nil, fmt.Errorf("[] expr and WHERE clause can not be used for the same node")
-		}
-		if leaf.expr, leaf.exprSel, err = compileCriteria("t", ret.sel.Qualify, leaf.ownerType, value); err != nil {
-			return nil, err
-		}
+	if err = ret.node.compileQualify(ret.sel.Qualify, value, opts); err != nil {
+		return nil, err
 	}
 	return ret, nil
 }
+
+// newSelectorNode creates node for FROM/target selector path
+func newSelectorNode(source reflect.Type, target snode.Node, values *node.Values, opts *options) (*Node, error) {
+	from := strings.Trim(sqlparser.Stringify(target), "`")
+	sel, err := sparser.ParseSelector(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w, %v", err, from)
+	}
+	return newNode(source, sel, values, opts.evaluator)
+}
+
+// compileQualify compiles WHERE clause criteria on the leaf node and assigns usable indexes
+func (n *Node) compileQualify(qualify *expr.Qualify, values *node.Values, opts *options) error {
+	if qualify == nil {
+		return nil
+	}
+	leaf := n.Leaf()
+	if leaf.hasCriteria() {
+		return fmt.Errorf("[] expr and WHERE clause can not be used for the same node")
+	}
+	offset := values.Bindings.Count
+	if err := leaf.compileCriteria("t", qualify, values, opts.evaluator); err != nil {
+		return err
+	}
+	if len(opts.indexes) > 0 {
+		n.setIndexes(opts.indexes, sparser.Constraints(qualify, values, offset))
+	}
+	return nil
+}
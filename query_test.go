@@ -1,11 +1,17 @@
 package structql
 
 import (
+	"context"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/assertly"
 	"github.com/viant/structql/transform"
+	"github.com/viant/xunsafe"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSelector_Select(t *testing.T) {
@@ -22,6 +28,7 @@ func TestSelector_Select(t *testing.T) {
 		Active   bool
 		Comments string
 		Items    []*Item
+		Tags     []string
 	}
 
 	type Holder struct {
@@ -29,11 +36,37 @@ func TestSelector_Select(t *testing.T) {
 		Records []*Record
 	}
 
+	type Status int
+	type Event struct {
+		ID     int
+		Status Status
+		Score  float64
+		Ref    *int64
+	}
+	type Events struct {
+		Events []*Event
+	}
+
+	type Line struct {
+		ID       int
+		Price    float64
+		Qty      int
+		Starts   int64
+		Ends     int64
+		Discount *float64
+		Created  time.Time
+	}
+	type Lines struct {
+		Lines []*Line
+	}
+
 	type Transformed1 struct {
 		Name   string
 		Active bool
 	}
 	var ptr = "test1"
+	var ref = int64(7)
+	var discount = 0.5
 
 	var testCases = []struct {
 		description string
@@ -41,6 +74,7 @@ func TestSelector_Select(t *testing.T) {
 		source      interface{}
 		sourceFn    func() interface{}
 		dest        interface{}
+		values      []interface{}
 		expect      interface{}
 		IntsField   string
 	}{
@@ -423,33 +457,879 @@ func TestSelector_Select(t *testing.T) {
 			source:      &Record{},
 			expect:      `[{"IDs":[]}]`,
 		},
+		{
+			description: "query with NOT and parenthesis criteria",
+			query:       "SELECT ID FROM `/Records` WHERE NOT (ID = 1 OR Name = 'name 3') AND Active = true",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "name 1", Active: true}, {ID: 2, Name: "name 2", Active: true}, {ID: 3, Name: "name 3", Active: true}, {ID: 4, Name: "name 4"}},
+			},
+			expect: `[{"ID":2}]`,
+		},
+		{
+			description: "query with OR precedence criteria",
+			query:       "SELECT ID FROM `/Records` WHERE ID = 1 OR ID > 2 AND Active",
+			source: &Holder{
+				Records: []*Record{{ID: 1}, {ID: 2, Active: true}, {ID: 3}, {ID: 4, Active: true}},
+			},
+			expect: `[{"ID":1},{"ID":4}]`,
+		},
+		{
+			description: "query with pointer criteria",
+			query:       "SELECT ID FROM `/Records` WHERE Ptr IS NOT NULL AND Ptr <> 'test1'",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Ptr: &ptr}, {ID: 2}, {ID: 3, Ptr: &[]string{"test2"}[0]}},
+			},
+			expect: `[{"ID":3}]`,
+		},
+		{
+			description: "query with negated pointer criteria",
+			query:       "SELECT ID FROM `/Records` WHERE NOT Ptr = 'test1'",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Ptr: &ptr}, {ID: 2}, {ID: 3, Ptr: &[]string{"test2"}[0]}},
+			},
+			expect: `[{"ID":3}]`,
+		},
+		{
+			description: "query with like criteria",
+			query:       "SELECT ID FROM `/Records` WHERE Name LIKE 'a%' OR Name LIKE '_b!_%' ESCAPE '!'",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "abc"}, {ID: 2, Name: "xb_c"}, {ID: 3, Name: "xbyc"}, {ID: 4, Name: "ca"}},
+			},
+			expect: `[{"ID":1},{"ID":2}]`,
+		},
+		{
+			description: "query with like backslash escape criteria",
+			query:       "SELECT ID FROM `/Records` WHERE Name LIKE 'a\\%%' ESCAPE '\\' OR Comments = 'O''Brien\\'",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "a%c"}, {ID: 2, Name: "abc"}, {ID: 3, Comments: `O'Brien\`}},
+			},
+			expect: `[{"ID":1},{"ID":3}]`,
+		},
+		{
+			description: "query with like backslash escape selector",
+			query:       "SELECT ID FROM `/Records[Name LIKE '%\\_' ESCAPE '\\']`",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "a_"}, {ID: 2, Name: "ab"}},
+			},
+			expect: `[{"ID":1}]`,
+		},
+		{
+			description: "query with ilike placeholder and not like pointer criteria",
+			query:       "SELECT ID FROM `/Records` WHERE Name ILIKE ? AND Ptr NOT LIKE '%2'",
+			values:      []interface{}{"%B%"},
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "abc", Ptr: &ptr}, {ID: 2, Name: "ABC"}, {ID: 3, Name: "b", Ptr: &[]string{"test2"}[0]}},
+			},
+			expect: `[{"ID":1}]`,
+		},
+		{
+			description: "query with string predicate functions",
+			query:       "SELECT ID FROM `/Records` WHERE STARTS_WITH(Name, 'na') AND NOT ENDS_WITH(Name, '2') AND (CONTAINS(Comments, 'x') OR REGEXP_LIKE(Comments, '^Y[0-9]+$', 'i'))",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "name 1", Comments: "axb"}, {ID: 2, Name: "name 2", Comments: "x"}, {ID: 3, Name: "name 3", Comments: "y12"}, {ID: 4, Name: "name 4", Comments: "y1a"}},
+			},
+			expect: `[{"ID":1},{"ID":3}]`,
+		},
+		{
+			description: "query with like in selector criteria",
+			query:       "SELECT ID FROM `/Records[Name LIKE '%3']`",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "name 1"}, {ID: 3, Name: "name 3"}},
+			},
+			expect: `[{"ID":3}]`,
+		},
+		{
+			description: "query with in criteria",
+			query:       "SELECT ID FROM `/Records` WHERE ID IN (1, ?) AND Name NOT IN ('x', ?)",
+			values:      []interface{}{3, "name 3"},
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "name 1"}, {ID: 2, Name: "name 2"}, {ID: 3, Name: "name 3"}, {ID: 1, Name: "x"}},
+			},
+			expect: `[{"ID":1}]`,
+		},
+		{
+			description: "query with in criteria on named, float and pointer types",
+			query:       "SELECT ID FROM `/Events` WHERE Status IN (1, 2) AND (Score IN ? OR Ref IN (7))",
+			values:      []interface{}{[]float64{0.5}},
+			source: &Events{
+				Events: []*Event{{ID: 1, Status: 1, Score: 0.5}, {ID: 2, Status: 2, Ref: &ref}, {ID: 3, Status: 3, Score: 0.5}, {ID: 4, Status: 2}},
+			},
+			expect: `[{"ID":1},{"ID":2}]`,
+		},
+		{
+			description: "query with between criteria",
+			query:       "SELECT ID FROM `/Lines` WHERE Price BETWEEN ? AND ? AND Qty NOT BETWEEN 2 AND 3",
+			values:      []interface{}{10, 20.5},
+			source: &Lines{
+				Lines: []*Line{{ID: 1, Price: 10, Qty: 1}, {ID: 2, Price: 20.5, Qty: 2}, {ID: 3, Price: 21, Qty: 1}, {ID: 4, Price: 15, Qty: 4}},
+			},
+			expect: `[{"ID":1},{"ID":4}]`,
+		},
+		{
+			description: "query with arithmetic criteria",
+			query:       "SELECT ID FROM `/Lines` WHERE Price * Qty > 100 AND Ends - Starts > 3600",
+			source: &Lines{
+				Lines: []*Line{{ID: 1, Price: 50.5, Qty: 2, Starts: 0, Ends: 3601}, {ID: 2, Price: 50, Qty: 2, Starts: 0, Ends: 7200}, {ID: 3, Price: 200, Qty: 1, Starts: 100, Ends: 3700}},
+			},
+			expect: `[{"ID":1}]`,
+		},
+		{
+			description: "query with float division and negative values criteria",
+			query:       "SELECT ID FROM `/Lines` WHERE Price / Qty <= 12.5 AND Price - 30 > -25",
+			source: &Lines{
+				Lines: []*Line{{ID: 1, Price: 25, Qty: 2}, {ID: 2, Price: 26, Qty: 2}, {ID: 3, Price: 5, Qty: 1}, {ID: 4, Price: 6, Qty: 1}},
+			},
+			expect: `[{"ID":1},{"ID":4}]`,
+		},
+		{
+			description: "query with arithmetic on pointer and selector criteria",
+			query:       "SELECT ID FROM `/Lines[Price * (1 - Discount) < 10 OR Qty / (Qty - 1) = 2]`",
+			source: &Lines{
+				Lines: []*Line{{ID: 1, Price: 15, Discount: &discount}, {ID: 2, Price: 15}, {ID: 3, Price: 25, Discount: &discount, Qty: 1}, {ID: 4, Price: 25, Qty: 2}},
+			},
+			expect: `[{"ID":1},{"ID":4}]`,
+		},
+		{
+			description: "query with string scalar functions",
+			query:       "SELECT ID FROM `/Records` WHERE LOWER(TRIM(Name)) = 'abc' AND LENGTH(Name) > 3 OR SUBSTR(UPPER(Name), 2, 2) = 'YZ' OR CONCAT(Name, '-', ID) = 'n-4'",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: " ABC"}, {ID: 2, Name: "abc"}, {ID: 3, Name: "xyz"}, {ID: 4, Name: "n"}},
+			},
+			expect: `[{"ID":1},{"ID":3},{"ID":4}]`,
+		},
+		{
+			description: "query with math and null handling scalar functions",
+			query:       "SELECT ID FROM `/Lines` WHERE ABS(Qty - 10) > 5 AND ROUND(Price * COALESCE(Discount, 1), 1) = 2.5",
+			source: &Lines{
+				Lines: []*Line{{ID: 1, Price: 5, Discount: &discount, Qty: 1}, {ID: 2, Price: 2.54, Qty: 20}, {ID: 3, Price: 2.5, Qty: 9}, {ID: 4, Price: 2.46, Qty: 16}},
+			},
+			expect: `[{"ID":1},{"ID":2},{"ID":4}]`,
+		},
+		{
+			description: "query with date scalar functions in selector criteria",
+			query:       "SELECT ID FROM `/Lines[YEAR(Created) = 2024 AND MONTH(Created) IN (1, 2)]`",
+			source: &Lines{
+				Lines: []*Line{{ID: 1, Created: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, {ID: 2, Created: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}, {ID: 3, Created: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			},
+			expect: `[{"ID":1}]`,
+		},
+		{
+			description: "query with collection predicates",
+			query:       "SELECT ID FROM `/Records` WHERE ANY(Items[ID > 10 AND Name LIKE 'item%']) AND NOT ALL(Items[ID = ?])",
+			values:      []interface{}{20},
+			source: &Holder{
+				Records: []*Record{
+					{ID: 1, Items: []*Item{{ID: 10, Name: "item 10"}, {ID: 11, Name: "item 11"}}},
+					{ID: 2, Items: []*Item{{ID: 20, Name: "item 20"}, nil}},
+					{ID: 3, Items: []*Item{{ID: 30, Name: "x"}, {ID: 20, Name: "item 20"}}},
+					{ID: 4},
+				},
+			},
+			expect: `[{"ID":1},{"ID":3}]`,
+		},
+		{
+			description: "query with exists and cardinality in selector criteria",
+			query:       "SELECT ID FROM `/Records[EXISTS(Items) AND CARDINALITY(Items) < 2 OR NOT EXISTS(Items[ID > 0])]`",
+			source: &Holder{
+				Records: []*Record{
+					{ID: 1, Items: []*Item{{ID: 10}}},
+					{ID: 2, Items: []*Item{{ID: 20}, {ID: 21}}},
+					{ID: 3},
+				},
+			},
+			expect: `[{"ID":1},{"ID":3}]`,
+		},
+		{
+			description: "query with array membership criteria",
+			query:       "SELECT ID FROM `/Records` WHERE 'promo' IN Tags OR ARRAY_INTERSECTS(Tags, ?) AND NOT ARRAY_CONTAINS(Tags, ?)",
+			values:      []interface{}{[]string{"a", "b"}, "c"},
+			source: &Holder{
+				Records: []*Record{{ID: 1, Tags: []string{"x", "promo"}}, {ID: 2, Tags: []string{"b"}}, {ID: 3, Tags: []string{"a", "c"}}, {ID: 4}},
+			},
+			expect: `[{"ID":1},{"ID":2}]`,
+		},
+		{
+			description: "query with array functions projection",
+			query:       "SELECT ID, ARRAY_JOIN(Tags, '|') AS Joined, ARRAY_LENGTH(Tags) AS Size, UPPER(Name) AS Name FROM `/Records[ARRAY_LENGTH(Tags) > 0]`",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "a", Tags: []string{"x", "y"}}, {ID: 2, Name: "b"}, {ID: 3, Name: "c", Tags: []string{"z"}}},
+			},
+			expect: `[{"ID":1, "Joined":"x|y", "Size":2, "Name":"A"},{"ID":3, "Joined":"z", "Size":1, "Name":"C"}]`,
+		},
+		{
+			description: "query with named map parameters",
+			query:       "SELECT ID FROM `/Records` WHERE ID > :minID AND (Name = @name OR STARTS_WITH(Name, @name) AND ID < :minID + 10)",
+			values:      []interface{}{map[string]interface{}{"minID": 1, "name": "a"}},
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "a"}, {ID: 2, Name: "a"}, {ID: 3, Name: "ab"}, {ID: 30, Name: "abc"}, {ID: 4, Name: "b"}},
+			},
+			expect: `[{"ID":2},{"ID":3}]`,
+		},
+		{
+			description: "query with named map parameters differing by case",
+			query:       "SELECT ID FROM `/Records` WHERE Name = @name OR Name = @Name",
+			values:      []interface{}{map[string]interface{}{"name": "a", "Name": "b"}},
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}},
+			},
+			expect: `[{"ID":1},{"ID":2}]`,
+		},
+		{
+			description: "query with named struct parameters",
+			query:       "SELECT ID FROM `/Records` WHERE ID >= @min_id AND ID <= @MaxID",
+			values: []interface{}{&struct {
+				MinID int `sqlx:"min_id"`
+				MaxID int
+			}{MinID: 2, MaxID: 3}},
+			source: &Holder{
+				Records: []*Record{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":2},{"ID":3}]`,
+		},
+		{
+			description: "query with string parameters",
+			query:       "SELECT ID FROM `/Records` WHERE Name = ? OR Comments = ? OR Ptr = ? AND Active = ?",
+			values:      []interface{}{`a"b\c`, "x' OR '1'='1", &ptr, true},
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: `a"b\c`}, {ID: 2, Comments: "x"}, {ID: 3, Ptr: &ptr, Active: true}, {ID: 4, Ptr: &ptr}},
+			},
+			expect: `[{"ID":1},{"ID":3}]`,
+		},
+		{
+			description: "query with typed parameters",
+			query:       "SELECT ID FROM `/Lines` WHERE Created >= ? AND Created < ? AND Price > ? AND Qty = ? AND Starts <> ?",
+			values:      []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), float32(1.5), uint(2), int64(5)},
+			source: &Lines{
+				Lines: []*Line{
+					{ID: 1, Created: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Price: 2, Qty: 2},
+					{ID: 2, Created: time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC), Price: 2, Qty: 2},
+					{ID: 3, Created: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Price: 1.5, Qty: 2},
+					{ID: 4, Created: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Price: 2, Qty: 2, Starts: 5},
+				},
+			},
+			expect: `[{"ID":1}]`,
+		},
+		{
+			description: "query with named type and pointer parameters",
+			query:       "SELECT ID FROM `/Events` WHERE Status = ? AND Ref = ?",
+			values:      []interface{}{Status(2), &ref},
+			source: &Events{
+				Events: []*Event{{ID: 1, Status: 2, Ref: &ref}, {ID: 2, Status: 2}, {ID: 3, Status: 1, Ref: &ref}},
+			},
+			expect: `[{"ID":1}]`,
+		},
+		{
+			description: "query with index selector",
+			query:       "SELECT ID, Name FROM `/Records[0]`",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Name: "name 1"}, {ID: 2, Name: "name 2"}, {ID: 3, Name: "name 3"}},
+			},
+			expect: `[{"ID":1, "Name":"name 1"}]`,
+		},
+		{
+			description: "query with negative index selector",
+			query:       "SELECT ID FROM `/Records[-1]`",
+			source: &Holder{
+				Records: []*Record{{ID: 1}, {ID: 2}, {ID: 3}},
+			},
+			expect: `[{"ID":3}]`,
+		},
+		{
+			description: "query with out of range index selector",
+			query:       "SELECT ID FROM `/Records[5]`",
+			source: &Holder{
+				Records: []*Record{{ID: 1}, {ID: 2}, {ID: 3}},
+			},
+			expect: `[]`,
+		},
+		{
+			description: "query with slice selector",
+			query:       "SELECT ID FROM `/Records[1:3]`",
+			source: &Holder{
+				Records: []*Record{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":2},{"ID":3}]`,
+		},
+		{
+			description: "query with slice and criteria selector",
+			query:       "SELECT ID FROM `/Records[-3:][Active=true]`",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Active: true}, {ID: 2, Active: true}, {ID: 3}, {ID: 4, Active: true}},
+			},
+			expect: `[{"ID":2},{"ID":4}]`,
+		},
+		{
+			description: "query with nested index selector",
+			query:       "SELECT ID, Name FROM `/Records[-1]/Items[0]`",
+			source: &Holder{
+				Records: []*Record{
+					{ID: 1, Items: []*Item{{ID: 10, Name: "item 10"}}},
+					{ID: 2, Items: []*Item{{ID: 20, Name: "item 20"}, {ID: 21, Name: "item 21"}}},
+				},
+			},
+			expect: `[{"ID":20, "Name":"item 20"}]`,
+		},
+		{
+			description: "query with intermediate criteria selector",
+			query:       "SELECT ID FROM `/Records[Active=true]/Items`",
+			source: &Holder{
+				Records: []*Record{
+					{ID: 1, Active: true, Items: []*Item{{ID: 10}}},
+					{ID: 2, Items: []*Item{{ID: 20}}},
+					{ID: 3, Active: true, Items: []*Item{{ID: 30}, {ID: 31}}},
+				},
+			},
+			expect: `[{"ID":10},{"ID":30},{"ID":31}]`,
+		},
+		{
+			description: "query with negated AND OR precedence",
+			query:       "SELECT ID FROM `/Records` WHERE NOT (Active = true AND ID = 1 OR ID = 3)",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Active: true}, {ID: 2, Active: true}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":2},{"ID":4}]`,
+		},
+		{
+			description: "query with bool field predicate",
+			query:       "SELECT ID FROM `/Records` WHERE Active",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Active: true}, {ID: 2, Active: true}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":1},{"ID":2}]`,
+		},
+		{
+			description: "query with negated bool field predicate",
+			query:       "SELECT ID FROM `/Records` WHERE NOT Active",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Active: true}, {ID: 2, Active: true}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":3},{"ID":4}]`,
+		},
+		{
+			description: "query with bool literal predicate",
+			query:       "SELECT ID FROM `/Records` WHERE TRUE AND ID > 3",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Active: true}, {ID: 2, Active: true}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":4}]`,
+		},
+		{
+			description: "query with non nullable IS NOT NULL",
+			query:       "SELECT ID FROM `/Records` WHERE ID IS NOT NULL AND ID < 3",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Active: true}, {ID: 2, Active: true}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":1},{"ID":2}]`,
+		},
+		{
+			description: "query with NULL comparison",
+			query:       "SELECT ID FROM `/Records` WHERE ID = NULL OR ID = 3",
+			source: &Holder{
+				Records: []*Record{{ID: 1, Active: true}, {ID: 2, Active: true}, {ID: 3}, {ID: 4}},
+			},
+			expect: `[{"ID":3}]`,
+		},
 	}
 
 	//for _, testCase := range testCases[len(testCases)-1:] {
+	for _, evaluator := range []Evaluator{IgoEvaluator, NativeEvaluator} {
+		for _, testCase := range testCases {
+			if testCase.source == nil {
+				testCase.source = testCase.sourceFn()
+			}
+			description := testCase.description + " (" + string(evaluator) + ")"
+			values := append(append([]interface{}{}, testCase.values...), WithEvaluator(evaluator))
+			sel, err := NewQuery(testCase.query, reflect.TypeOf(testCase.source), reflect.TypeOf(testCase.dest), values...)
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			dest, err := sel.Select(testCase.source)
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+
+			if testCase.IntsField != "" {
+				asInts, err := transform.AsInts(sel.Type(), testCase.IntsField)
+				if !assert.Nil(t, err, description) {
+					continue
+				}
+				dest = asInts(dest)
+			}
+
+			if !assertly.AssertValues(t, testCase.expect, dest, description) {
+				continue
+			}
+		}
+		var nilRef *int64
+		_, err := NewQuery("SELECT ID FROM `/Events` WHERE Ref = ?", reflect.TypeOf(&Events{}), nil, nilRef, WithEvaluator(evaluator))
+		if assert.NotNil(t, err, "NULL parameter comparison ("+string(evaluator)+")") {
+			assert.Contains(t, err.Error(), "IS NULL")
+		}
+	}
+}
+
+func TestNewQuery_NamedParameters(t *testing.T) {
+	type Record struct {
+		ID   int
+		Name string
+	}
+	var testCases = []struct {
+		description string
+		query       string
+		values      []interface{}
+		expectErr   string
+	}{
+		{
+			description: "missing parameter",
+			query:       "SELECT ID FROM `/` WHERE ID = @id AND Name = @name",
+			values:      []interface{}{map[string]interface{}{"id": 1}},
+			expectErr:   "missing parameter: name",
+		},
+		{
+			description: "extra parameter",
+			query:       "SELECT ID FROM `/` WHERE ID = @id",
+			values:      []interface{}{map[string]interface{}{"id": 1, "name": "a", "active": true}},
+			expectErr:   "unused parameters: active, name",
+		},
+		{
+			description: "mixed placeholders",
+			query:       "SELECT ID FROM `/` WHERE ID = ? AND Name = @name",
+			values:      []interface{}{1},
+			expectErr:   "named parameter can not be mixed with positional placeholders",
+		},
+		{
+			description: "invalid parameters",
+			query:       "SELECT ID FROM `/` WHERE ID = @id",
+			values:      []interface{}{1},
+			expectErr:   "unsupported named parameters type: int, expected map or struct",
+		},
+		{
+			description: "ambiguous parameter",
+			query:       "SELECT ID FROM `/` WHERE Name = @name",
+			values:      []interface{}{map[string]interface{}{"Name": "a", "NAME": "b"}},
+			expectErr:   "ambiguous parameter: name, matches: NAME, Name",
+		},
+		{
+			description: "extra positional values",
+			query:       "SELECT ID FROM `/` WHERE ID = ?",
+			values:      []interface{}{1, 2},
+			expectErr:   "invalid placeholder values count: 2, expected: 1",
+		},
+		{
+			description: "values without placeholders",
+			query:       "SELECT ID FROM `/`",
+			values:      []interface{}{1},
+			expectErr:   "invalid placeholder values count: 1, expected: 0",
+		},
+	}
 	for _, testCase := range testCases {
-		if testCase.source == nil {
-			testCase.source = testCase.sourceFn()
+		_, err := NewQuery(testCase.query, reflect.TypeOf([]*Record{}), nil, testCase.values...)
+		if !assert.NotNil(t, err, testCase.description) {
+			continue
 		}
+		assert.Contains(t, err.Error(), testCase.expectErr, testCase.description)
+	}
+}
 
-		sel, err := NewQuery(testCase.query, reflect.TypeOf(testCase.source), reflect.TypeOf(testCase.dest))
+func TestQuery_SelectContext(t *testing.T) {
+	type Item struct {
+		ID   int
+		Name string
+	}
+	type Record struct {
+		ID    int
+		Items []*Item
+	}
+	var records []*Record
+	for i := 0; i < 10; i++ {
+		record := &Record{ID: i}
+		for j := 0; j < 10; j++ {
+			record.Items = append(record.Items, &Item{ID: i*10 + j, Name: "item " + strconv.Itoa(j)})
+		}
+		records = append(records, record)
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	var testCases = []struct {
+		description string
+		query       string
+		ctx         context.Context
+		options     []interface{}
+		expectErr   error
+		expectLen   int
+	}{
+		{description: "no caps", query: "SELECT ID FROM `/Items`", ctx: context.Background(), expectLen: 100},
+		{description: "cancelled", query: "SELECT ID FROM `/Items`", ctx: cancelled, expectErr: context.Canceled},
+		{description: "deadline", query: "SELECT ID FROM `/Items`", ctx: expired, expectErr: context.DeadlineExceeded},
+		{description: "max rows", query: "SELECT ID FROM `/Items`", ctx: context.Background(), options: []interface{}{WithMaxRows(99)}, expectErr: ErrMaxRowsExceeded},
+		{description: "max rows with criteria", query: "SELECT ID FROM `/Items` WHERE ID < 10", ctx: context.Background(), options: []interface{}{WithMaxRows(10)}, expectLen: 10},
+		{description: "max nodes", query: "SELECT ID FROM `/Items`", ctx: context.Background(), options: []interface{}{WithMaxNodes(50)}, expectErr: ErrMaxNodesExceeded},
+		{description: "max nodes across count and map walks", query: "SELECT ID FROM `/Items`", ctx: context.Background(), options: []interface{}{WithMaxNodes(241)}, expectErr: ErrMaxNodesExceeded},
+		{description: "max nodes within limit", query: "SELECT ID FROM `/Items`", ctx: context.Background(), options: []interface{}{WithMaxNodes(242)}, expectLen: 100},
+		{description: "max bytes", query: "SELECT ID, Name FROM `/Items`", ctx: context.Background(), options: []interface{}{WithMaxResultBytes(1024)}, expectErr: ErrMaxBytesExceeded},
+		{description: "max bytes within limit", query: "SELECT ID, Name FROM `/Items[0]`", ctx: context.Background(), options: []interface{}{WithLimits(Limits{MaxBytes: 1024, MaxRows: 10})}, expectLen: 10},
+	}
+	for _, testCase := range testCases {
+		query, err := NewQuery(testCase.query, reflect.TypeOf(records), nil, testCase.options...)
 		if !assert.Nil(t, err, testCase.description) {
 			continue
 		}
-		dest, err := sel.Select(testCase.source)
+		result, err := query.SelectContext(testCase.ctx, records)
+		if testCase.expectErr != nil {
+			assert.ErrorIs(t, err, testCase.expectErr, testCase.description)
+			_, err = query.FirstContext(testCase.ctx, records)
+			assert.ErrorIs(t, err, testCase.expectErr, testCase.description)
+			continue
+		}
 		if !assert.Nil(t, err, testCase.description) {
 			continue
 		}
+		assert.Equal(t, testCase.expectLen, reflect.ValueOf(result).Elem().Len(), testCase.description)
+	}
+	query, err := NewQuery("SELECT ID FROM `/Items`", reflect.TypeOf(records), nil, WithMaxRows(5))
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = query.Select(records)
+	var limitErr *LimitError
+	if assert.ErrorAs(t, err, &limitErr) {
+		assert.Equal(t, RowsResource, limitErr.Resource)
+		assert.Equal(t, 5, limitErr.Limit)
+	}
+}
 
-		if testCase.IntsField != "" {
-			asInts, err := transform.AsInts(sel.Type(), testCase.IntsField)
-			if !assert.Nil(t, err, testCase.description) {
+func TestRegisterFunc(t *testing.T) {
+	type Code string
+	type Item struct {
+		ID     int
+		Amount float64
+		Code   Code
+		Lat    float64
+	}
+	type Holder struct {
+		Items []*Item
+	}
+	rates := map[string]float64{"USD": 1, "EUR": 1.1}
+	assert.Nil(t, RegisterFunc("CURRENCY", func(amount float64, code string) (float64, error) {
+		rate, ok := rates[code]
+		if !ok {
+			return 0, fmt.Errorf("unknown currency: %v", code)
+		}
+		return amount * rate, nil
+	}))
+	assert.Nil(t, RegisterFunc("SEPARATE", func(sep string, values ...string) string {
+		return strings.Join(values, sep)
+	}))
+	assert.NotNil(t, RegisterFunc("INVALID", "abc"))
+	assert.NotNil(t, RegisterFunc("INVALID", func(x int) (int, int) { return x, x }))
+
+	source := &Holder{Items: []*Item{{ID: 1, Amount: 10, Code: "USD"}, {ID: 2, Amount: 100, Code: "EUR", Lat: 1.5}}}
+	var testCases = []struct {
+		description string
+		query       string
+		options     []interface{}
+		expect      string
+		expectErr   string
+	}{
+		{
+			description: "projection and criteria",
+			query:       "SELECT ID, CURRENCY(Amount, Code) AS USD FROM `/Items` WHERE CURRENCY(Amount, Code) > 50",
+			expect:      `[{"ID":2,"USD":110.00000000000001}]`,
+		},
+		{
+			description: "selector criteria with variadic function",
+			query:       "SELECT ID, SEPARATE('-', Code, 'x', 'y') AS Label FROM `/Items[SEPARATE(':', Code) = 'USD']`",
+			expect:      `[{"ID":1,"Label":"USD-x-y"}]`,
+		},
+		{
+			description: "query scoped function",
+			query:       "SELECT ID FROM `/Items` WHERE GEOHASH(Lat, ID) = 'h1.5:2'",
+			options:     []interface{}{WithFunc("GEOHASH", func(lat float64, id int) string { return fmt.Sprintf("h%v:%v", lat, id) })},
+			expect:      `[{"ID":2}]`,
+		},
+		{
+			description: "function error",
+			query:       "SELECT ID FROM `/Items` WHERE CURRENCY(Amount, 'GBP') > 1",
+			expectErr:   "unknown currency: GBP",
+		},
+		{
+			description: "projection function error",
+			query:       "SELECT ID, CURRENCY(Amount, 'GBP') AS GBP FROM `/Items`",
+			expectErr:   "unknown currency: GBP",
+		},
+		{
+			description: "invalid argument kind",
+			query:       "SELECT ID FROM `/Items` WHERE CURRENCY(Code, Amount) > 1",
+			expectErr:   "invalid argument 1 kind",
+		},
+		{
+			description: "invalid arguments count",
+			query:       "SELECT ID FROM `/Items` WHERE CURRENCY(Amount) > 1",
+			expectErr:   "invalid CURRENCY arguments count",
+		},
+		{
+			description: "invalid query scoped function",
+			query:       "SELECT ID FROM `/Items`",
+			options:     []interface{}{WithFunc("GEOHASH", 1)},
+			expectErr:   "expected func",
+		},
+	}
+	for _, evaluator := range []Evaluator{IgoEvaluator, NativeEvaluator} {
+		for _, testCase := range testCases {
+			description := testCase.description + " (" + string(evaluator) + ")"
+			options := append([]interface{}{WithEvaluator(evaluator)}, testCase.options...)
+			query, err := NewQuery(testCase.query, reflect.TypeOf(source), nil, options...)
+			if err == nil {
+				var result interface{}
+				if result, err = query.Select(source); err == nil {
+					assertly.AssertValues(t, testCase.expect, result, description)
+				}
+			}
+			if testCase.expectErr == "" {
+				assert.Nil(t, err, description)
 				continue
 			}
-			dest = asInts(dest)
+			if assert.NotNil(t, err, description) {
+				assert.Contains(t, err.Error(), testCase.expectErr, description)
+			}
 		}
+	}
+
+	query, err := NewQuery("SELECT ID, CURRENCY(Amount, 'GBP') AS GBP FROM `/Items[CURRENCY(Amount, Code) > 0]`", reflect.TypeOf(source), nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	failing := &Holder{Items: []*Item{{ID: 1, Amount: 10, Code: "GBP"}}}
+	walker := NewWalker(query.node)
+	_, err = walker.CountContext(context.Background(), failing)
+	assert.ErrorContains(t, err, "unknown currency: GBP", "walker count")
+	assert.EqualValues(t, 0, walker.Count(failing), "walker count")
+	_, err = query.node.Leaf().Match(failing.Items[0])
+	assert.ErrorContains(t, err, "unknown currency: GBP", "node match")
+	destPtr := xunsafe.AsPointer(reflect.New(query.Type()).Interface())
+	err = query.mapper.Map(walker, source, query.destSlice.Appender(destPtr))
+	assert.ErrorContains(t, err, "unknown currency: GBP", "mapper map")
+	err = query.mapper.MapStruct(xunsafe.AsPointer(source.Items[0]), xunsafe.AsPointer(reflect.New(query.StructType()).Interface()))
+	assert.ErrorContains(t, err, "unknown currency: GBP", "mapper map struct")
+}
+
+type wavgAggregator struct {
+	sum    float64
+	weight float64
+}
+
+func (a *wavgAggregator) Init() {
+	*a = wavgAggregator{}
+}
+
+func (a *wavgAggregator) Accumulate(args ...interface{}) error {
+	if args[0] == nil || args[1] == nil {
+		return nil
+	}
+	weight := float64(args[1].(int))
+	if weight < 0 {
+		return fmt.Errorf("negative weight: %v", weight)
+	}
+	a.sum += args[0].(float64) * weight
+	a.weight += weight
+	return nil
+}
+
+func (a *wavgAggregator) Merge(other Aggregator) error {
+	otherWavg, ok := other.(*wavgAggregator)
+	if !ok {
+		return fmt.Errorf("incompatible aggregator: %T", other)
+	}
+	a.sum += otherWavg.sum
+	a.weight += otherWavg.weight
+	return nil
+}
+
+func (a *wavgAggregator) Result() interface{} {
+	if a.weight == 0 {
+		return nil
+	}
+	return a.sum / a.weight
+}
+
+func TestRegisterAggregate(t *testing.T) {
+	type Sale struct {
+		ID       int
+		Region   string
+		Segment  string
+		Price    float64
+		Qty      int
+		Discount *float64
+	}
+	type Vendor struct {
+		Sales []*Sale
+	}
+	type Summary struct {
+		Region string
+		Total  float64
+		Count  int
+	}
+	assert.Nil(t, RegisterAggregate("WAVG", reflect.TypeOf(0.0), func() Aggregator { return &wavgAggregator{} }))
+	assert.NotNil(t, RegisterAggregate("INVALID", nil, nil))
 
-		if !assertly.AssertValues(t, testCase.expect, dest, testCase.description) {
+	discount := 0.1
+	source := &Vendor{Sales: []*Sale{
+		{ID: 1, Region: "EU", Segment: "B2B", Price: 10, Qty: 1},
+		{ID: 2, Region: "US", Segment: "B2C", Price: 20, Qty: 2, Discount: &discount},
+		{ID: 3, Region: "EU", Segment: "B2C", Price: 40, Qty: 3},
+		{ID: 4, Region: "EU", Segment: "B2B", Price: 30, Qty: 1},
+	}}
+	var testCases = []struct {
+		description string
+		query       string
+		dest        reflect.Type
+		source      interface{}
+		options     []interface{}
+		expect      string
+		expectErr   string
+	}{
+		{
+			description: "group by with user defined aggregate",
+			query:       "SELECT Region, WAVG(Price, Qty) AS AvgPrice, COUNT(*) AS Cnt FROM `/Sales` GROUP BY Region",
+			expect:      `[{"Region":"EU","AvgPrice":32,"Cnt":3},{"Region":"US","AvgPrice":20,"Cnt":1}]`,
+		},
+		{
+			description: "group by multiple columns with criteria and array agg",
+			query:       "SELECT Region, Segment, SUM(Qty) AS Qty, ARRAY_AGG(ID) AS IDs FROM `/Sales` WHERE ID > 1 GROUP BY Region, Segment",
+			expect:      `[{"Region":"US","Segment":"B2C","Qty":2,"IDs":[2]},{"Region":"EU","Segment":"B2C","Qty":3,"IDs":[3]},{"Region":"EU","Segment":"B2B","Qty":1,"IDs":[4]}]`,
+		},
+		{
+			description: "aggregates without group by",
+			query:       "SELECT COUNT(Discount) AS Discounted, MIN(Price) AS MinPrice, MAX(Region) AS MaxRegion, AVG(Qty) AS AvgQty, SUM(Price) AS Total FROM `/Sales`",
+			expect:      `[{"Discounted":1,"MinPrice":10,"MaxRegion":"US","AvgQty":1.75,"Total":100}]`,
+		},
+		{
+			description: "aggregates over empty collection",
+			query:       "SELECT COUNT(*) AS Cnt, SUM(Qty) AS Qty FROM `/Sales`",
+			source:      &Vendor{Sales: []*Sale{}},
+			expect:      `[{"Cnt":0,"Qty":0}]`,
+		},
+		{
+			description: "group by over empty collection",
+			query:       "SELECT Region, COUNT(*) AS Cnt FROM `/Sales` GROUP BY Region",
+			source:      &Vendor{Sales: []*Sale{}},
+			expect:      `[]`,
+		},
+		{
+			description: "group by into value dest slice",
+			query:       "SELECT Region, SUM(Price) AS Total, COUNT(*) AS Count FROM `/Sales` GROUP BY Region",
+			dest:        reflect.TypeOf([]Summary{}),
+			expect:      `[{"Region":"EU","Total":80,"Count":3},{"Region":"US","Total":20,"Count":1}]`,
+		},
+		{
+			description: "query scoped aggregate",
+			query:       "SELECT Region, TOTAL_QTY(Qty) AS Qty FROM `/Sales` GROUP BY Region",
+			options: []interface{}{WithAggregate("TOTAL_QTY", nil, func() Aggregator {
+				return &sumAggregator{}
+			})},
+			expect: `[{"Region":"EU","Qty":5},{"Region":"US","Qty":2}]`,
+		},
+		{
+			description: "aggregator error",
+			query:       "SELECT Region, WAVG(Price, Qty) AS AvgPrice FROM `/Sales` GROUP BY Region",
+			source:      &Vendor{Sales: []*Sale{{ID: 1, Region: "EU", Price: 10, Qty: -1}}},
+			expectErr:   "negative weight",
+		},
+		{
+			description: "invalid aggregate argument",
+			query:       "SELECT SUM(Region) AS Total FROM `/Sales`",
+			expectErr:   "expected numeric",
+		},
+		{
+			description: "invalid group by field",
+			query:       "SELECT COUNT(*) AS Cnt FROM `/Sales` GROUP BY Country",
+			expectErr:   "failed to lookup group by field",
+		},
+	}
+	for _, testCase := range testCases {
+		var src interface{} = source
+		if testCase.source != nil {
+			src = testCase.source
+		}
+		query, err := NewQuery(testCase.query, reflect.TypeOf(src), testCase.dest, testCase.options...)
+		if err == nil {
+			var result interface{}
+			if result, err = query.Select(src); err == nil {
+				assertly.AssertValues(t, testCase.expect, result, testCase.description)
+			}
+		}
+		if testCase.expectErr == "" {
+			assert.Nil(t, err, testCase.description)
 			continue
 		}
+		if assert.NotNil(t, err, testCase.description) {
+			assert.Contains(t, err.Error(), testCase.expectErr, testCase.description)
+		}
+	}
+}
+
+func TestAggregator_Merge(t *testing.T) {
+	var testCases = []struct {
+		description string
+		new         NewAggregator
+		partitions  [][]interface{}
+		expect      interface{}
+	}{
+		{description: "count", new: func() Aggregator { return &countAggregator{} }, partitions: [][]interface{}{{1, nil}, {2}}, expect: 2},
+		{description: "sum", new: func() Aggregator { return &sumAggregator{} }, partitions: [][]interface{}{{1, 2}, {1.5}}, expect: 4.5},
+		{description: "avg", new: func() Aggregator { return &avgAggregator{} }, partitions: [][]interface{}{{1, 2}, {}}, expect: 1.5},
+		{description: "min", new: func() Aggregator { return &extremumAggregator{sign: -1} }, partitions: [][]interface{}{{"b", "c"}, {"a"}}, expect: "a"},
+		{description: "max", new: func() Aggregator { return &extremumAggregator{sign: 1} }, partitions: [][]interface{}{{}, {3, 7}}, expect: 7},
+		{description: "wavg", new: func() Aggregator { return &wavgAggregator{} }, partitions: [][]interface{}{{}, {}}, expect: nil},
+	}
+	for _, testCase := range testCases {
+		merged := testCase.new()
+		merged.Init()
+		for _, partition := range testCase.partitions {
+			aggregator := testCase.new()
+			aggregator.Init()
+			for _, value := range partition {
+				assert.Nil(t, aggregator.Accumulate(value), testCase.description)
+			}
+			assert.Nil(t, merged.Merge(aggregator), testCase.description)
+		}
+		assert.EqualValues(t, testCase.expect, merged.Result(), testCase.description)
+		assert.NotNil(t, merged.Merge(&struct{ Aggregator }{}), testCase.description)
+	}
+}
+
+func BenchmarkQuery_Select(b *testing.B) {
+	type Record struct {
+		ID     int
+		Name   string
+		Price  float64
+		Qty    int
+		Ptr    *int
+		Active bool
+	}
+	var records = make([]*Record, 1000)
+	for i := range records {
+		records[i] = &Record{ID: i, Name: "record " + strconv.Itoa(i), Price: float64(i%50) + 0.5, Qty: i % 7, Active: i%2 == 0}
+		if i%3 == 0 {
+			records[i].Ptr = &records[i].ID
+		}
+	}
+	SQL := "SELECT ID, Name FROM `/` WHERE Active AND Price * Qty > ? AND (Ptr IS NULL OR Ptr > 10) AND Name LIKE 'record 1%'"
+	for _, evaluator := range []Evaluator{IgoEvaluator, NativeEvaluator} {
+		b.Run(string(evaluator), func(b *testing.B) {
+			query, err := NewQuery(SQL, reflect.TypeOf(records), nil, 20, WithEvaluator(evaluator))
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err = query.Select(records); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
 }
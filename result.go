@@ -0,0 +1,134 @@
+package structql
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/viant/structql/errs"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+// Scalar returns the single column value of the single row query result, i.e. SELECT MAX(Revenue) FROM `/Products`,
+// pointer value is dereferenced, NULL value, NULL aggregate or no rows return nil
+func (s *Query) Scalar(source interface{}) (interface{}, error) {
+	return s.ScalarContext(context.Background(), source)
+}
+
+// ScalarContext returns the single column value of the single row query result, it returns an error matching
+// ErrMultipleColumns or ErrMultipleRows when the query yields more than one column or row
+func (s *Query) ScalarContext(ctx context.Context, source interface{}) (value interface{}, err error) {
+	destType := s.StructType()
+	if destType.NumField() != 1 {
+		return nil, errs.New(ErrMultipleColumns, "invalid scalar query: %v, expected 1 column but had %v", s.query, destType.NumField())
+	}
+	column := xunsafe.NewField(destType.Field(0))
+	if s.mapper.countOnly() {
+		count, err := s.count(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		row := reflect.New(destType).Interface()
+		s.mapper.aggregations[0].setComputed(xunsafe.AsPointer(row), count)
+		return fieldValue(column, xunsafe.AsPointer(row)), nil
+	}
+	if s.mapper.aggregate && len(s.mapper.groupBy) == 0 && len(s.mapper.aggregations) == 1 {
+		return s.aggregateScalar(ctx, source, column)
+	}
+	_, destPtr, err := s.selectContext(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	switch rows := s.destSlice.Len(destPtr); rows {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, errs.New(ErrMultipleRows, "invalid scalar query: %v, expected 1 row but had %v", s.query, rows)
+	}
+	return fieldValue(column, xunsafe.AsPointer(s.destSlice.ValuePointerAt(destPtr, 0))), nil
+}
+
+// aggregateScalar returns ungrouped aggregate value, NULL aggregate result, i.e. MAX over no source items, returns nil
+func (s *Query) aggregateScalar(ctx context.Context, source interface{}, column *xunsafe.Field) (value interface{}, err error) {
+	defer scalar.Recover(&err)
+	destPtr := xunsafe.AsPointer(reflect.New(s.destSlice.Type).Interface())
+	aContext := newContext(newGuard(ctx, s.limits), s.mapper, s.destSlice.Appender(destPtr), true)
+	if err = s.walker.mapNode(aContext, s.walker.root, source); err != nil {
+		return nil, err
+	}
+	if err = aContext.flush(); err != nil {
+		return nil, err
+	}
+	if len(aContext.groups) == 0 || aContext.groups[0].aggregators[0].Result() == nil {
+		return nil, nil
+	}
+	return fieldValue(column, xunsafe.AsPointer(aContext.groups[0].value)), nil
+}
+
+// ScalarAs returns the single column value of the single row query result converted to T,
+// NULL value or no rows return an error matching ErrNull, incompatible value returns an error matching ErrConversion
+func ScalarAs[T any](query *Query, source interface{}) (T, error) {
+	var ret T
+	value, err := query.Scalar(source)
+	if err != nil {
+		return ret, err
+	}
+	if value == nil {
+		return ret, errs.New(ErrNull, "invalid scalar query: %v, expected %s value but had NULL", query.query, reflect.TypeOf(&ret).Elem().String())
+	}
+	if actual, ok := value.(T); ok {
+		return actual, nil
+	}
+	target := reflect.TypeOf(&ret).Elem()
+	rValue := reflect.ValueOf(value)
+	if !rValue.CanConvert(target) || scalar.KindOf(rValue.Type()).IsNumeric() != scalar.KindOf(target).IsNumeric() {
+		return ret, errs.Conversion("failed to convert scalar %T value to %s", value, target.String())
+	}
+	return rValue.Convert(target).Interface().(T), nil
+}
+
+// Exists returns true when any source item matches the query path and criteria
+func (s *Query) Exists(source interface{}) (bool, error) {
+	return s.ExistsContext(context.Background(), source)
+}
+
+// ExistsContext returns true when any source item matches the query path and criteria,
+// the walk stops at the first matched item
+func (s *Query) ExistsContext(ctx context.Context, source interface{}) (found bool, err error) {
+	defer scalar.Recover(&err)
+	return s.walker.exists(newGuard(ctx, s.limits), s.walker.root, source)
+}
+
+// Count returns number of rows the query yields
+func (s *Query) Count(source interface{}) (int, error) {
+	return s.CountContext(context.Background(), source)
+}
+
+// CountContext returns number of rows the query yields, matched source items are counted without mapping
+// unless the query aggregates
+func (s *Query) CountContext(ctx context.Context, source interface{}) (int, error) {
+	if !s.mapper.aggregate {
+		return s.count(ctx, source)
+	}
+	_, destPtr, err := s.selectContext(ctx, source)
+	if err != nil {
+		return 0, err
+	}
+	return s.destSlice.Len(destPtr), nil
+}
+
+// count returns number of matched source items
+func (s *Query) count(ctx context.Context, source interface{}) (ret int, err error) {
+	defer scalar.Recover(&err)
+	return s.walker.count(newGuard(ctx, s.limits), s.walker.root, source)
+}
+
+// countOnly returns true for a single COUNT(*) projection without GROUP BY
+func (m *Mapper) countOnly() bool {
+	if len(m.groupBy) > 0 || len(m.fields) > 0 || len(m.aggregations) != 1 || len(m.aggregations[0].aggregation.args) > 0 {
+		return false
+	}
+	_, ok := m.aggregations[0].aggregation.newAggregator().(*countAggregator)
+	return ok
+}
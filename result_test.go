@@ -0,0 +1,154 @@
+package structql
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestQuery_Scalar(t *testing.T) {
+	type Product struct {
+		ID      int
+		Name    string
+		Revenue float64
+		Rating  *int
+	}
+	type Vendor struct {
+		ID       int
+		Products []*Product
+	}
+	rating := 4
+	vendors := []*Vendor{
+		{ID: 1, Products: []*Product{{ID: 1, Name: "a", Revenue: 1.5, Rating: &rating}, {ID: 2, Name: "b", Revenue: 3}}},
+		{ID: 2, Products: []*Product{{ID: 3, Name: "c", Revenue: 2}}},
+		{ID: 3},
+	}
+
+	var testCases = []struct {
+		description  string
+		query        string
+		values       []interface{}
+		expectScalar interface{}
+		expectCount  int
+		expectExists bool
+		expectErr    error
+	}{
+		{
+			description:  "count",
+			query:        "SELECT COUNT(*) AS Cnt FROM `/Products` WHERE Revenue > ?",
+			values:       []interface{}{1.6},
+			expectScalar: 2,
+			expectCount:  1,
+			expectExists: true,
+		},
+		{
+			description:  "count without matches",
+			query:        "SELECT COUNT(*) AS Cnt FROM `/Products` WHERE Revenue > 10",
+			expectScalar: 0,
+			expectCount:  1,
+		},
+		{
+			description:  "max",
+			query:        "SELECT MAX(Revenue) AS Revenue FROM `/Products`",
+			expectScalar: 3.0,
+			expectCount:  1,
+			expectExists: true,
+		},
+		{
+			description: "max without matches",
+			query:       "SELECT MAX(Revenue) AS Revenue FROM `/Products` WHERE Revenue > 10",
+			expectCount: 1,
+		},
+		{
+			description:  "single row column",
+			query:        "SELECT Name FROM `/Products` WHERE ID = ?",
+			values:       []interface{}{3},
+			expectScalar: "c",
+			expectCount:  1,
+			expectExists: true,
+		},
+		{
+			description:  "pointer column",
+			query:        "SELECT Rating FROM `/Products[ID=1]`",
+			expectScalar: 4,
+			expectCount:  1,
+			expectExists: true,
+		},
+		{
+			description:  "null column",
+			query:        "SELECT Rating FROM `/Products[ID=2]`",
+			expectCount:  1,
+			expectExists: true,
+		},
+		{
+			description: "no rows",
+			query:       "SELECT Name FROM `/Products` WHERE ID = 10",
+		},
+		{
+			description:  "multiple rows",
+			query:        "SELECT Name FROM `/Products` WHERE ID > 1",
+			expectErr:    ErrMultipleRows,
+			expectCount:  2,
+			expectExists: true,
+		},
+		{
+			description:  "multiple columns",
+			query:        "SELECT ID, Name FROM `/Products` WHERE ID = 1",
+			expectErr:    ErrMultipleColumns,
+			expectCount:  1,
+			expectExists: true,
+		},
+		{
+			description:  "grouped rows",
+			query:        "SELECT ID, COUNT(*) AS Cnt FROM `/` GROUP BY ID",
+			expectErr:    ErrMultipleColumns,
+			expectCount:  3,
+			expectExists: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		query, err := NewQuery(testCase.query, reflect.TypeOf(vendors), nil, testCase.values...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		actual, err := query.Scalar(vendors)
+		if testCase.expectErr != nil {
+			assert.True(t, errors.Is(err, testCase.expectErr), testCase.description)
+		} else if assert.Nil(t, err, testCase.description) {
+			assert.EqualValues(t, testCase.expectScalar, actual, testCase.description)
+		}
+		count, err := query.Count(vendors)
+		assert.Nil(t, err, testCase.description)
+		assert.EqualValues(t, testCase.expectCount, count, testCase.description)
+		exists, err := query.Exists(vendors)
+		assert.Nil(t, err, testCase.description)
+		assert.EqualValues(t, testCase.expectExists, exists, testCase.description)
+	}
+
+	query, err := NewQuery("SELECT SUM(Revenue) AS Total FROM `/Products`", reflect.TypeOf(vendors), nil)
+	assert.Nil(t, err)
+	total, err := ScalarAs[float32](query, vendors)
+	assert.Nil(t, err)
+	assert.EqualValues(t, float32(6.5), total)
+	_, err = ScalarAs[string](query, vendors)
+	assert.True(t, errors.Is(err, ErrConversion))
+
+	query, err = NewQuery("SELECT MAX(ID) AS ID FROM `/Products` WHERE Revenue > 100", reflect.TypeOf(vendors), nil)
+	assert.Nil(t, err)
+	maxID, err := ScalarAs[int](query, vendors)
+	assert.True(t, errors.Is(err, ErrNull), "max over empty input")
+	assert.EqualValues(t, 0, maxID)
+	query, err = NewQuery("SELECT ID FROM `/Products` WHERE ID > 100", reflect.TypeOf(vendors), nil)
+	assert.Nil(t, err)
+	_, err = ScalarAs[int](query, vendors)
+	assert.True(t, errors.Is(err, ErrNull), "no rows")
+
+	query, err = NewQuery("SELECT COUNT(*) AS Cnt FROM `/Products`", reflect.TypeOf(vendors), nil, WithMaxNodes(4))
+	assert.Nil(t, err)
+	_, err = ScalarAs[int64](query, vendors)
+	assert.True(t, errors.Is(err, ErrMaxNodesExceeded))
+	_, err = query.Exists(vendors)
+	assert.Nil(t, err, "exists stops at the first match")
+}
@@ -0,0 +1,109 @@
+package structql
+
+import (
+	"fmt"
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// compute represents projection expression computed for source item
+type compute func(src unsafe.Pointer) interface{}
+
+// compileFunction compiles scalar function projection i.e. ARRAY_JOIN(Tags, ',')
+func compileFunction(source reflect.Type, call *expr.Call, functions *scalar.Registry) (compute, scalar.Kind, error) {
+	name := sqlparser.Stringify(call.X)
+	function := functions.Lookup(name)
+	if function == nil {
+		return nil, scalar.Any, fmt.Errorf("unsupported function: %v", name)
+	}
+	var args []compute
+	var kinds []scalar.Kind
+	for _, arg := range call.Args {
+		argFn, kind, err := compileArgument(source, arg, functions)
+		if err != nil {
+			return nil, scalar.Any, fmt.Errorf("invalid %v argument: %w", name, err)
+		}
+		args = append(args, argFn)
+		kinds = append(kinds, kind)
+	}
+	result, err := function.Validate(kinds)
+	if err != nil {
+		return nil, scalar.Any, fmt.Errorf("invalid %v call: %w", name, err)
+	}
+	fn := function.Fn
+	return func(src unsafe.Pointer) interface{} {
+		values := make([]interface{}, 0, len(args))
+		for _, arg := range args {
+			value := arg(src)
+			if scalar.IsNil(value) {
+				if !function.Nullable { //SQL: function of NULL is NULL
+					return nil
+				}
+				continue
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		return result.Convert(fn(values))
+	}, result, nil
+}
+
+// compileArgument compiles function argument: source field, literal or nested function call
+func compileArgument(source reflect.Type, n node.Node, functions *scalar.Registry) (compute, scalar.Kind, error) {
+	switch actual := n.(type) {
+	case *expr.Ident, *expr.Selector:
+		name := sqlparser.Stringify(actual)
+		aField := xunsafe.FieldByName(source, name)
+		if aField == nil {
+			return nil, scalar.Any, errs.UnknownField("failed to lookup source field: '%s' at %s", name, source.String())
+		}
+		return func(src unsafe.Pointer) interface{} {
+			return aField.Interface(src)
+		}, scalar.KindOf(aField.Type), nil
+	case *expr.Literal:
+		value, kind, err := literal(actual)
+		if err != nil {
+			return nil, scalar.Any, err
+		}
+		return func(src unsafe.Pointer) interface{} {
+			return value
+		}, kind, nil
+	case *expr.Call:
+		return compileFunction(source, actual, functions)
+	}
+	return nil, scalar.Any, fmt.Errorf("unsupported expression: %v", sqlparser.Stringify(n))
+}
+
+func literal(literal *expr.Literal) (interface{}, scalar.Kind, error) {
+	switch strings.ToLower(literal.Kind) {
+	case "string":
+		value := literal.Value
+		if len(value) >= 2 {
+			quote := value[:1]
+			value = strings.ReplaceAll(value[1:len(value)-1], quote+quote, quote)
+		}
+		return value, scalar.String, nil
+	case "int":
+		value, err := strconv.Atoi(literal.Value)
+		return value, scalar.Int, err
+	case "numeric":
+		value, err := strconv.ParseFloat(literal.Value, 64)
+		return value, scalar.Float, err
+	case "bool":
+		value, err := strconv.ParseBool(strings.ToLower(literal.Value))
+		return value, scalar.Bool, err
+	case "null":
+		return nil, scalar.Any, nil
+	}
+	return nil, scalar.Any, fmt.Errorf("unsupported literal: %v", literal.Value)
+}
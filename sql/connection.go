@@ -28,7 +28,7 @@ func (c *Connection) PrepareContext(ctx context.Context, SQL string) (driver.Stm
 		return nil, fmt.Errorf("unsupported SQL kind: %v", SQL)
 	}
 	c.types.Merge(globalTypes)
-	stmt := &Statement{SQL: SQL, Kind: kind, types: c.types, BaseURL: c.cfg.BaseURL, fs: c.fs}
+	stmt := &Statement{SQL: SQL, Kind: kind, types: c.types, BaseURL: c.cfg.BaseURL, evaluator: c.cfg.Evaluator, fs: c.fs}
 	stmt.checkQueryParameters()
 	if kind.IsSelect() {
 		if err := stmt.prepareSelect(SQL); err != nil {
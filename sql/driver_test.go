@@ -181,6 +181,71 @@ func Test_QueryContext(t *testing.T) {
 				&Foo{Id: 2, Name: "name2"},
 			},
 		},
+		{
+			description: "select 1 row by name with like operator and register named type",
+			dsn:         "file:///testdata/",
+			execSQL:     "REGISTER TYPE Foo AS ?",
+			execParams:  []interface{}{Foo{}},
+			querySQL:    "SELECT * FROM Foo WHERE name LIKE ?",
+			queryParams: []interface{}{"%2"},
+			scanner: func(r *sql.Rows) (interface{}, error) {
+				foo := Foo{}
+				err := r.Scan(&foo.Id, &foo.Name)
+				return &foo, err
+			},
+			expect: []interface{}{
+				&Foo{Id: 2, Name: "name2"},
+			},
+		},
+		{
+			description: "select 1 row by name with scalar function and register named type",
+			dsn:         "file:///testdata/",
+			execSQL:     "REGISTER TYPE Foo AS ?",
+			execParams:  []interface{}{Foo{}},
+			querySQL:    "SELECT * FROM Foo WHERE UPPER(name) = 'NAME1'",
+			queryParams: []interface{}{},
+			scanner: func(r *sql.Rows) (interface{}, error) {
+				foo := Foo{}
+				err := r.Scan(&foo.Id, &foo.Name)
+				return &foo, err
+			},
+			expect: []interface{}{
+				&Foo{Id: 1, Name: "name1"},
+			},
+		},
+		{
+			description: "select 1 row by repeated named parameter and register named type",
+			dsn:         "file:///testdata/",
+			execSQL:     "REGISTER TYPE Foo AS ?",
+			execParams:  []interface{}{Foo{}},
+			querySQL:    "SELECT * FROM Foo WHERE id = @id OR (id > @id AND name = :name)",
+			queryParams: []interface{}{sql.Named("id", 1), sql.Named("name", "name1")},
+			scanner: func(r *sql.Rows) (interface{}, error) {
+				foo := Foo{}
+				err := r.Scan(&foo.Id, &foo.Name)
+				return &foo, err
+			},
+			expect: []interface{}{
+				&Foo{Id: 1, Name: "name1"},
+			},
+		},
+		{
+			description: "select 1 row by repeated named parameter with native evaluator",
+			dsn:         "file:///testdata/?evaluator=native",
+			execSQL:     "REGISTER TYPE Foo AS ?",
+			execParams:  []interface{}{Foo{}},
+			querySQL:    "SELECT * FROM Foo WHERE id = @id OR (id > @id AND UPPER(name) = :name)",
+			queryParams: []interface{}{sql.Named("id", 1), sql.Named("name", "NAME2")},
+			scanner: func(r *sql.Rows) (interface{}, error) {
+				foo := Foo{}
+				err := r.Scan(&foo.Id, &foo.Name)
+				return &foo, err
+			},
+			expect: []interface{}{
+				&Foo{Id: 1, Name: "name1"},
+				&Foo{Id: 2, Name: "name2"},
+			},
+		},
 		{
 			description: "select 1 row by id with register inlined type",
 			dsn:         "file:///testdata/",
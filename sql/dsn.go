@@ -10,9 +10,16 @@ import (
 	"strings"
 )
 
+// criteria evaluators
+const (
+	igoEvaluator    = "igo"
+	nativeEvaluator = "native"
+)
+
 // Config represent Connection config
 type Config struct {
-	BaseURL string
+	BaseURL   string
+	Evaluator string
 	url.Values
 }
 
@@ -36,9 +43,17 @@ func ParseDSN(dsn string) (*Config, error) {
 	}
 
 	cfg.BaseURL = URL.Scheme + "://" + URL.Host + URL.Path
-	if idx := strings.Index(dsn, "?"); idx != -1 {
+	if idx := strings.Index(dsn, "?"); idx != -1 && URL.Scheme != "file" { //file path could have been resolved with cwd
 		cfg.BaseURL = dsn[:idx]
 	}
+	if cfg.Values.Has("evaluator") {
+		switch cfg.Evaluator = cfg.Values.Get("evaluator"); cfg.Evaluator {
+		case igoEvaluator, nativeEvaluator:
+		default:
+			return nil, fmt.Errorf("unsupported evaluator: %v", cfg.Evaluator)
+		}
+		cfg.Values.Del("evaluator")
+	}
 	if len(cfg.Values) > 0 {
 		var unsupported []string
 		for k := range cfg.Values {
@@ -0,0 +1,37 @@
+package sql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseDSN(t *testing.T) {
+	testdata, _ := filepath.Abs("testdata")
+	var testCases = []struct {
+		description string
+		dsn         string
+		expectURL   string
+		evaluator   string
+		hasError    bool
+	}{
+		{description: "relative file path", dsn: "file:///testdata/", expectURL: "file://" + testdata},
+		{description: "relative file path with options", dsn: "file:///testdata/?evaluator=native", expectURL: "file://" + testdata, evaluator: "native"},
+		{description: "absolute file path with options", dsn: "file://" + testdata + "/?evaluator=igo", expectURL: "file://" + testdata + "/", evaluator: "igo"},
+		{description: "memory url with options", dsn: "mem://localhost/data?evaluator=native", expectURL: "mem://localhost/data", evaluator: "native"},
+		{description: "unsupported evaluator", dsn: "file:///testdata/?evaluator=js", hasError: true},
+		{description: "unsupported option", dsn: "file:///testdata/?cache=true", hasError: true},
+	}
+	for _, testCase := range testCases {
+		cfg, err := ParseDSN(testCase.dsn)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expectURL, cfg.BaseURL, testCase.description)
+		assert.EqualValues(t, testCase.evaluator, cfg.Evaluator, testCase.description)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/viant/sqlparser"
 	"github.com/viant/sqlparser/expr"
 	"github.com/viant/sqlparser/query"
+	"github.com/viant/structql/errs"
 	"github.com/viant/xunsafe"
 	"reflect"
 	"strconv"
@@ -74,7 +75,7 @@ func newMapper(recordType reflect.Type, list query.List, args *[]driver.NamedVal
 			fuzzName := strings.ReplaceAll(strings.ToLower(actual.Name), "_", "")
 			pos, ok := fieldPos[fuzzName]
 			if !ok {
-				return nil, fmt.Errorf("unable to match column: %v in type: %s", actual.Name, recordType.Name())
+				return nil, errs.UnknownField("unable to match column: %v in type: %s", actual.Name, recordType.Name())
 			}
 			m.byPos[i] = &field{index: pos, Field: xunsafe.NewField(recordType.Field(pos))}
 		case *expr.Literal:
@@ -84,7 +85,7 @@ func newMapper(recordType reflect.Type, list query.List, args *[]driver.NamedVal
 			fuzzName := item.Alias
 			pos, ok := fieldPos[fuzzName]
 			if !ok {
-				return nil, fmt.Errorf("unable to match column: %v in type: %s", item.Alias, recordType.Name())
+				return nil, errs.UnknownField("unable to match column: %v in type: %s", item.Alias, recordType.Name())
 			}
 			m.byPos[i] = &field{index: pos, Field: xunsafe.NewField(recordType.Field(pos))}
 		case *expr.Call:
@@ -105,7 +106,7 @@ func newMapper(recordType reflect.Type, list query.List, args *[]driver.NamedVal
 			fuzzName := item.Alias
 			pos, ok := fieldPos[fuzzName]
 			if !ok {
-				return nil, fmt.Errorf("unable to match column: %v in type: %s", item.Alias, recordType.Name())
+				return nil, errs.UnknownField("unable to match column: %v in type: %s", item.Alias, recordType.Name())
 			}
 			m.byPos[i] = &field{index: pos, Field: xunsafe.NewField(recordType.Field(pos))}
 
@@ -161,15 +162,15 @@ func (m *mapper) handleCast(actual *expr.Call, args *[]driver.NamedValue, item *
 				intValue = int((*args)[0].Value.(int64))
 			case string:
 				if intValue, err = strconv.Atoi((*args)[0].Value.(string)); err != nil {
-					return fmt.Errorf("%v invalid int: %v %w", item.Alias, (*args)[0].Value, err)
+					return errs.Conversion("%v invalid int: %v %w", item.Alias, (*args)[0].Value, err)
 				}
 			default:
-				return fmt.Errorf("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
+				return errs.UnsupportedType("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
 			}
 			m.values[i] = intValue
 			*args = (*args)[1:]
 		} else {
-			return fmt.Errorf("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
+			return errs.UnsupportedType("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
 		}
 	case "bool":
 		if _, ok := actual.Args[0].(*expr.Placeholder); ok {
@@ -183,15 +184,15 @@ func (m *mapper) handleCast(actual *expr.Call, args *[]driver.NamedValue, item *
 				boolValue = (*args)[0].Value.(bool)
 			case string:
 				if boolValue, err = strconv.ParseBool((*args)[0].Value.(string)); err != nil {
-					return fmt.Errorf("%v invalid bool: %v %w", item.Alias, (*args)[0].Value, err)
+					return errs.Conversion("%v invalid bool: %v %w", item.Alias, (*args)[0].Value, err)
 				}
 			default:
-				return fmt.Errorf("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
+				return errs.UnsupportedType("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
 			}
 			m.values[i] = boolValue
 			*args = (*args)[1:]
 		} else {
-			return fmt.Errorf("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
+			return errs.UnsupportedType("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
 		}
 	case "time", "datetime", "timestamp":
 		if _, ok := actual.Args[0].(*expr.Placeholder); ok {
@@ -210,11 +211,11 @@ func (m *mapper) handleCast(actual *expr.Call, args *[]driver.NamedValue, item *
 			case string:
 				t, err := time.Parse(time.RFC3339, (*args)[0].Value.(string))
 				if err != nil {
-					return fmt.Errorf("%v invalid time: %v %w", item.Alias, (*args)[0].Value, err)
+					return errs.Conversion("%v invalid time: %v %w", item.Alias, (*args)[0].Value, err)
 				}
 				tValue = &t
 			default:
-				return fmt.Errorf("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
+				return errs.UnsupportedType("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
 			}
 			if tValue == nil {
 				m.values[i] = nil
@@ -223,7 +224,7 @@ func (m *mapper) handleCast(actual *expr.Call, args *[]driver.NamedValue, item *
 			}
 			*args = (*args)[1:]
 		} else {
-			return fmt.Errorf("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
+			return errs.UnsupportedType("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
 		}
 
 	case "float":
@@ -239,19 +240,19 @@ func (m *mapper) handleCast(actual *expr.Call, args *[]driver.NamedValue, item *
 				floatValue = (*args)[0].Value.(float64)
 			case string:
 				if floatValue, err = strconv.ParseFloat((*args)[0].Value.(string), 64); err != nil {
-					return fmt.Errorf("%v invalid float: %v %w", item.Alias, (*args)[0].Value, err)
+					return errs.Conversion("%v invalid float: %v %w", item.Alias, (*args)[0].Value, err)
 				}
 			default:
-				return fmt.Errorf("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
+				return errs.UnsupportedType("%v unsupported int argument type: %T", item.Alias, (*args)[0].Value)
 			}
 			m.values[i] = floatValue
 			*args = (*args)[1:]
 		} else {
-			return fmt.Errorf("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
+			return errs.UnsupportedType("%v unsupported cast argument type: %T", item.Alias, actual.Args[0])
 		}
 
 	default:
-		return fmt.Errorf("unsupported cast type: %v", raw)
+		return errs.UnsupportedType("unsupported cast type: %v", raw)
 	}
 	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/viant/sqlparser/expr"
 	"github.com/viant/structql/node"
 	"github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
 
 	"github.com/viant/sqlparser/query"
 	"github.com/viant/xunsafe"
@@ -22,6 +23,7 @@ import (
 // Rows represents rows driver
 type Rows struct {
 	criteria         *iexpr.Bool
+	predicate        parser.Predicate
 	scope            *igo.Scope
 	recordSelector   *exec.Selector
 	recordType       reflect.Type
@@ -85,7 +87,8 @@ func (r *Rows) Close() error {
 }
 
 // Next moves to next row
-func (r *Rows) Next(dest []driver.Value) error {
+func (r *Rows) Next(dest []driver.Value) (err error) {
+	defer scalar.Recover(&err)
 	if r.resourceIndex >= len(r.resources) || r.isFalsePredicate {
 		return io.EOF
 	}
@@ -123,6 +126,9 @@ func (r *Rows) Next(dest []driver.Value) error {
 	}
 
 	ptr := xunsafe.AsPointer(r.record)
+	if r.predicate != nil && !r.predicate(ptr) {
+		return r.Next(dest)
+	}
 	if r.criteria != nil {
 		if err := r.criteria.State.SetValue("r", r.record); err != nil {
 			return err
@@ -190,35 +196,40 @@ func (r *Rows) ColumnTypeNullable(index int) (nullable, ok bool) {
 	return rType.Kind() == reflect.Pointer, true
 }
 
-func (r *Rows) initCriteria(criteria *expr.Qualify, args []driver.NamedValue) error {
+func (r *Rows) initCriteria(criteria *expr.Qualify, args []driver.NamedValue, evaluator string) error {
 	var values = &node.Values{Bindings: &node.Binding{}}
+	var named map[string]interface{}
 	for _, v := range args {
+		if v.Name != "" {
+			if named == nil {
+				named = map[string]interface{}{}
+			}
+			named[v.Name] = v.Value
+			continue
+		}
 		values.Values = append(values.Values, v.Value)
 	}
+	if named != nil {
+		if len(values.Values) > 0 {
+			return fmt.Errorf("named and positional arguments can not be mixed")
+		}
+		values.Values = []interface{}{named}
+	}
+	if evaluator == nativeEvaluator {
+		var err error
+		if r.predicate, err = parser.AsPredicate(criteria, r.mapper.lookup, values); err != nil {
+			return fmt.Errorf("failed to compile criteria: %w", err)
+		}
+		return values.Validate()
+	}
 	scope := igo.NewScope()
 	r.scope = scope
 	goExpr, err := parser.AsBinaryGoExpr("r.", criteria, r.mapper.lookup, values)
 	if err != nil {
 		return fmt.Errorf("failed to compile criteria: %w", err)
 	}
-	for _, group := range values.Bindings.Groups {
-		if group.InStrings != nil {
-			var strs []string
-			for _, v := range values.Values[group.From:group.To] {
-				strs = append(strs, v.(string))
-			}
-			group.InStrings.Set(strs)
-			scope.RegisterFunc("In"+group.Name, group.InStrings.In)
-		}
-
-		if group.InInts != nil {
-			var ints []int
-			for _, v := range values.Values[group.From:group.To] {
-				ints = append(ints, v.(int))
-			}
-			group.InInts.Set(ints)
-			scope.RegisterFunc("In"+group.Name, group.InInts.In)
-		}
+	for _, fn := range values.Bindings.Functions {
+		scope.RegisterFunc(fn.Name, fn.Fn)
 	}
 	if r.recordSelector, err = scope.DefineVariable("r", r.recordType); err != nil {
 		return err
@@ -226,5 +237,5 @@ func (r *Rows) initCriteria(criteria *expr.Qualify, args []driver.NamedValue) er
 	if r.criteria, err = scope.BoolExpression(goExpr); err != nil {
 		return err
 	}
-	return nil
+	return values.Validate()
 }
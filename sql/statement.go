@@ -9,6 +9,8 @@ import (
 	"github.com/viant/sqlparser"
 	"github.com/viant/sqlparser/expr"
 	"github.com/viant/sqlparser/query"
+	"github.com/viant/structql/errs"
+	"github.com/viant/structql/parser"
 	"github.com/viant/x"
 	"github.com/viant/xreflect"
 	"github.com/viant/xunsafe"
@@ -32,6 +34,7 @@ type (
 		recordType reflect.Type
 		mapper     map[int]int
 		numInput   int
+		evaluator  string
 	}
 )
 
@@ -54,7 +57,7 @@ func (s *Statement) ExecContext(ctx context.Context, args []driver.NamedValue) (
 
 func (s *Statement) prepareSelect(SQL string) error {
 	var err error
-	if s.query, err = sqlparser.ParseQuery(SQL); err != nil {
+	if s.query, err = parser.ParseQuery(SQL); err != nil {
 		return err
 	}
 
@@ -238,7 +241,7 @@ func (s *Statement) executeSelect(ctx context.Context, name string, resources re
 	}
 
 	if criteria != "" {
-		if err = rows.initCriteria(s.query.Qualify, args); err != nil {
+		if err = rows.initCriteria(s.query.Qualify, args, s.evaluator); err != nil {
 			return nil, err
 		}
 	}
@@ -282,7 +285,7 @@ func (s *Statement) autodetectType(ctx context.Context, res resources) (reflect.
 				case "float":
 					field = reflect.StructField{Name: item.Alias, Type: reflect.TypeOf(0.0)}
 				default:
-					return nil, fmt.Errorf("unsupported cast type: %v", raw)
+					return nil, errs.UnsupportedType("unsupported cast type: %v", raw)
 				}
 			case "now", "current_timestamp":
 				field = reflect.StructField{Name: item.Alias, Type: reflect.TypeOf(time.Time{})}
@@ -290,7 +293,7 @@ func (s *Statement) autodetectType(ctx context.Context, res resources) (reflect.
 				return nil, fmt.Errorf("unsupported function: %v", name)
 			}
 		default:
-			return nil, fmt.Errorf("unsupported type: %T", actual)
+			return nil, errs.UnsupportedType("unsupported type: %T", actual)
 		}
 		fields = append(fields, field)
 	}
@@ -307,15 +310,23 @@ func checkQueryParameters(query string) int {
 				continue
 			}
 			inQuote = !inQuote
-		case '?', '@':
+		case '?':
 			if !inQuote {
 				count++
 			}
+		case '@', ':':
+			if !inQuote && i+1 < len(query) && isNameStart(query[i+1]) {
+				return -1 //named parameters can be repeated, the driver does not check arguments count
+			}
 		}
 	}
 	return count
 }
 
+func isNameStart(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b == '_'
+}
+
 func isFalsePredicate(qualify *expr.Qualify) bool {
 	binary, ok := qualify.X.(*expr.Binary)
 	if !ok {
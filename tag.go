@@ -0,0 +1,187 @@
+package structql
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/viant/structql/naming"
+	"github.com/viant/xunsafe"
+)
+
+type (
+	// tagStrategy represents autogenerated dest field tag strategy
+	tagStrategy struct {
+		key    string
+		aCase  naming.Case
+		detect bool
+	}
+
+	// tagger generates autogenerated dest field tags, tag names are formatted with the source naming convention
+	tagger struct {
+		strategies []*tagStrategy
+	}
+)
+
+// tag returns dest field tag for the source or computed field, source field tag options and keys order are preserved,
+// with detected strategies source field without the tag key stays untagged and projection without alias keeps source tag value
+func (t *tagger) tag(src *xunsafe.Field, alias string, computed bool) reflect.StructTag {
+	var items []string
+	for _, strategy := range t.ordered(src.Tag) {
+		value, ok := src.Tag.Lookup(strategy.key)
+		if strategy.detect {
+			if !ok && !computed {
+				continue
+			}
+			if ok && alias == src.Name {
+				items = append(items, tagItem(strategy.key, value))
+				continue
+			}
+		}
+		name, options := value, ""
+		if index := strings.IndexByte(value, ','); index != -1 {
+			name, options = value[:index], value[index:]
+		}
+		if name != "-" && (name != "" || !ok || !strategy.detect) {
+			name = strategy.format(name, alias)
+		}
+		items = append(items, tagItem(strategy.key, name+options))
+	}
+	return reflect.StructTag(strings.Join(items, " "))
+}
+
+// ordered returns strategies in the source tag keys order followed by the remaining strategies
+func (t *tagger) ordered(tag reflect.StructTag) []*tagStrategy {
+	if tag == "" {
+		return t.strategies
+	}
+	ret := make([]*tagStrategy, 0, len(t.strategies))
+	used := map[string]bool{}
+	for _, key := range tagKeys(tag) {
+		for _, strategy := range t.strategies {
+			if strategy.key == key && !used[key] {
+				used[key] = true
+				ret = append(ret, strategy)
+			}
+		}
+	}
+	for _, strategy := range t.strategies {
+		if !used[strategy.key] {
+			ret = append(ret, strategy)
+		}
+	}
+	return ret
+}
+
+// format formats alias with the strategy case, multi word source tag name case takes precedence over the detected source convention
+func (s *tagStrategy) format(name, alias string) string {
+	if !s.detect {
+		return s.aCase.Format(alias)
+	}
+	if aCase := naming.Detect(name); aCase != naming.AsIs && len(naming.Words(name)) > 1 {
+		return aCase.Format(alias)
+	}
+	return s.aCase.Format(alias)
+}
+
+// newTagger returns tagger for the configured strategies, or for the tag keys used by the source fields,
+// detected strategy case is the prevailing case of the source field tag names
+func newTagger(source reflect.Type, strategies []*tagStrategy) *tagger {
+	if len(strategies) == 0 {
+		for _, key := range structTagKeys(source) {
+			strategies = append(strategies, &tagStrategy{key: key, detect: true})
+		}
+	}
+	ret := &tagger{}
+	for _, strategy := range strategies {
+		if strategy.detect {
+			strategy = &tagStrategy{key: strategy.key, detect: true, aCase: detectTagCase(source, strategy.key)}
+		}
+		ret.strategies = append(ret.strategies, strategy)
+	}
+	return ret
+}
+
+// detectTagCase returns the prevailing case of the source field tag names, empty and skipped names are ignored, single lower case words count for
+// both lower camel and snake case, lower camel case wins a tie
+func detectTagCase(source reflect.Type, key string) naming.Case {
+	votes := map[naming.Case]int{}
+	for i := 0; i < source.NumField(); i++ {
+		value, ok := source.Field(i).Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name := value
+		if index := strings.IndexByte(value, ','); index != -1 {
+			name = value[:index]
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		aCase := naming.Detect(name)
+		votes[aCase]++
+		if aCase == naming.LowerCamel && len(naming.Words(name)) == 1 {
+			votes[naming.Snake]++
+		}
+	}
+	ret := naming.AsIs
+	for _, aCase := range []naming.Case{naming.LowerCamel, naming.Snake, naming.UpperCamel, naming.UpperSnake} {
+		if votes[aCase] > votes[ret] {
+			ret = aCase
+		}
+	}
+	return ret
+}
+
+// structTagKeys returns tag keys used by the struct fields in the field order
+func structTagKeys(source reflect.Type) []string {
+	var ret []string
+	unique := map[string]bool{}
+	for i := 0; i < source.NumField(); i++ {
+		for _, key := range tagKeys(source.Field(i).Tag) {
+			if !unique[key] {
+				unique[key] = true
+				ret = append(ret, key)
+			}
+		}
+	}
+	return ret
+}
+
+// tagKeys returns conventional struct tag keys, see reflect.StructTag
+func tagKeys(tag reflect.StructTag) []string {
+	var ret []string
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := string(tag[:i])
+		tag = tag[i+1:]
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		ret = append(ret, key)
+		tag = tag[i+1:]
+	}
+	return ret
+}
+
+func tagItem(key, value string) string {
+	return key + ":" + strconv.Quote(value)
+}
@@ -0,0 +1,93 @@
+package structql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/structql/naming"
+	"reflect"
+	"testing"
+)
+
+func TestQuery_Tags(t *testing.T) {
+	type Product struct {
+		ID        int     `json:"id" db:"id"`
+		VendorID  int     `json:"vendorId,omitempty" yaml:"vendorId" db:"vendor_id"`
+		Name      string  `json:"name,omitempty" yaml:",inline" db:"name"`
+		Revenue   float64 `json:"revenue" db:"revenue"`
+		Comments  string  `json:"-"`
+		Internal  string
+		UnitPrice float64 `json:"unitPrice"`
+	}
+	type Vendor struct {
+		Products []*Product
+	}
+
+	var testCases = []struct {
+		description string
+		query       string
+		values      []interface{}
+		expect      map[string]reflect.StructTag
+	}{
+		{
+			description: "source tags without alias",
+			query:       "SELECT ID, VendorID, Internal FROM `/Products`",
+			expect: map[string]reflect.StructTag{
+				"ID":       `json:"id" db:"id"`,
+				"VendorID": `json:"vendorId,omitempty" yaml:"vendorId" db:"vendor_id"`,
+				"Internal": ``,
+			},
+		},
+		{
+			description: "alias with detected convention and preserved options",
+			query:       "SELECT VendorID AS SupplierID, Name AS ProductName, Comments AS Note, Revenue AS TotalRevenue FROM `/Products`",
+			expect: map[string]reflect.StructTag{
+				"SupplierID":   `json:"supplierID,omitempty" yaml:"supplierID" db:"supplier_id"`,
+				"ProductName":  `json:"productName,omitempty" yaml:",inline" db:"product_name"`,
+				"Note":         `json:"-"`,
+				"TotalRevenue": `json:"totalRevenue" db:"total_revenue"`,
+			},
+		},
+		{
+			description: "aggregate and computed fields",
+			query:       "SELECT VendorID, SUM(Revenue) AS TotalRevenue, ARRAY_AGG(ID) AS ProductIDs FROM `/Products` GROUP BY VendorID",
+			expect: map[string]reflect.StructTag{
+				"VendorID":     `json:"vendorId,omitempty" yaml:"vendorId" db:"vendor_id"`,
+				"TotalRevenue": `json:"totalRevenue" db:"total_revenue" yaml:"totalRevenue"`,
+				"ProductIDs":   `json:"productIDs" db:"product_ids"`,
+			},
+		},
+		{
+			description: "window and computed fields",
+			query:       "SELECT ID, RANK() OVER (ORDER BY Revenue DESC) AS RevenueRank, UPPER(Name) AS UpperName FROM `/Products`",
+			expect: map[string]reflect.StructTag{
+				"ID":          `json:"id" db:"id"`,
+				"RevenueRank": `json:"revenueRank" db:"revenue_rank" yaml:"revenueRank"`,
+				"UpperName":   `json:"upperName" db:"upper_name" yaml:"upperName"`,
+			},
+		},
+		{
+			description: "configured strategies",
+			query:       "SELECT VendorID AS SupplierID, Internal, COUNT(*) AS Cnt FROM `/Products` GROUP BY VendorID",
+			values:      []interface{}{WithTag("db"), WithTagCase("yaml", naming.Snake), WithTagCase("bson", naming.LowerCamel)},
+			expect: map[string]reflect.StructTag{
+				"SupplierID": `yaml:"supplier_id" db:"supplier_id" bson:"supplierID"`,
+				"Internal":   `yaml:"internal" bson:"internal"`,
+				"Cnt":        `db:"cnt" yaml:"cnt" bson:"cnt"`,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		query, err := NewQuery(testCase.query, reflect.TypeOf(&Vendor{}), nil, testCase.values...)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		destType := query.StructType()
+		for name, expect := range testCase.expect {
+			aField, ok := destType.FieldByName(name)
+			if !assert.True(t, ok, testCase.description+" "+name) {
+				continue
+			}
+			assert.EqualValues(t, expect, aField.Tag, testCase.description+" "+name)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package transform
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// As returns a function extracting the named field of the first result row as T, i.e. a slice field produced by ARRAY_AGG,
+// target is a query result type: T, *T, []T, []*T, *[]T or *[]*T of struct T, no rows return zero value
+func As[T any](target reflect.Type, name string) (func(value interface{}) (T, error), error) {
+	aShape, err := newShape(target)
+	if err != nil {
+		return nil, err
+	}
+	aField, err := aShape.field(name)
+	if err != nil {
+		return nil, err
+	}
+	fieldValue, err := fieldConverter[T](aField)
+	if err != nil {
+		return nil, err
+	}
+	return func(value interface{}) (T, error) {
+		var ret T
+		ptr, err := aShape.first(value)
+		if err != nil || ptr == nil {
+			return ret, err
+		}
+		return fieldValue(ptr), nil
+	}, nil
+}
+
+// AsMap returns a function building key column to value column map from the result rows, the last row wins for duplicated keys
+func AsMap[K comparable, V any](target reflect.Type, key, value string) (func(value interface{}) (map[K]V, error), error) {
+	aShape, err := newShape(target)
+	if err != nil {
+		return nil, err
+	}
+	keyValue, err := columnConverter[K](aShape, key)
+	if err != nil {
+		return nil, err
+	}
+	valueValue, err := columnConverter[V](aShape, value)
+	if err != nil {
+		return nil, err
+	}
+	return func(value interface{}) (map[K]V, error) {
+		ret := map[K]V{}
+		err := aShape.each(value, func(ptr unsafe.Pointer) error {
+			ret[keyValue(ptr)] = valueValue(ptr)
+			return nil
+		})
+		return ret, err
+	}, nil
+}
+
+// AsIndex returns a function building key column to row map from the result rows, R is the row struct or the row struct pointer,
+// the last row wins for duplicated keys
+func AsIndex[K comparable, R any](target reflect.Type, key string) (func(value interface{}) (map[K]R, error), error) {
+	aShape, err := newShape(target)
+	if err != nil {
+		return nil, err
+	}
+	keyValue, err := columnConverter[K](aShape, key)
+	if err != nil {
+		return nil, err
+	}
+	rowValue, err := rowConverter[R](aShape.rowType)
+	if err != nil {
+		return nil, err
+	}
+	return func(value interface{}) (map[K]R, error) {
+		ret := map[K]R{}
+		err := aShape.each(value, func(ptr unsafe.Pointer) error {
+			ret[keyValue(ptr)] = rowValue(ptr)
+			return nil
+		})
+		return ret, err
+	}, nil
+}
+
+// AsSet returns a function building a set of key column values from the result rows
+func AsSet[K comparable](target reflect.Type, key string) (func(value interface{}) (map[K]bool, error), error) {
+	aShape, err := newShape(target)
+	if err != nil {
+		return nil, err
+	}
+	keyValue, err := columnConverter[K](aShape, key)
+	if err != nil {
+		return nil, err
+	}
+	return func(value interface{}) (map[K]bool, error) {
+		ret := map[K]bool{}
+		err := aShape.each(value, func(ptr unsafe.Pointer) error {
+			ret[keyValue(ptr)] = true
+			return nil
+		})
+		return ret, err
+	}, nil
+}
+
+// columnConverter returns function reading named row field as T
+func columnConverter[T any](aShape *shape, name string) (func(ptr unsafe.Pointer) T, error) {
+	aField, err := aShape.field(name)
+	if err != nil {
+		return nil, err
+	}
+	return fieldConverter[T](aField)
+}
@@ -0,0 +1,153 @@
+package transform
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/structql/errs"
+	"reflect"
+	"testing"
+)
+
+func TestAs(t *testing.T) {
+	type Status int
+	type Record struct {
+		ID     int
+		Name   string
+		Status Status
+		Rating *int
+		Ids    []int
+	}
+	rating := 4
+	records := []*Record{{ID: 1, Name: "a", Status: 2, Rating: &rating, Ids: []int{1, 2}}, nil, {ID: 2, Name: "b"}, {ID: 1, Name: "c"}}
+
+	var testCases = []struct {
+		description string
+		source      interface{}
+		fn          func(target reflect.Type) (func(value interface{}) (interface{}, error), error)
+		expect      interface{}
+		hasError    bool
+	}{
+		{
+			description: "slice field of the first row",
+			source:      &records,
+			fn:          wrap(As[[]int], "Ids"),
+			expect:      []int{1, 2},
+		},
+		{
+			description: "named type field converted",
+			source:      records,
+			fn:          wrap(As[int64], "Status"),
+			expect:      int64(2),
+		},
+		{
+			description: "pointer field dereferenced",
+			source:      records[0],
+			fn:          wrap(As[int], "Rating"),
+			expect:      4,
+		},
+		{
+			description: "no rows",
+			source:      []Record{},
+			fn:          wrap(As[string], "Name"),
+			expect:      "",
+		},
+		{
+			description: "type mismatch",
+			source:      records,
+			fn:          wrap(As[string], "Ids"),
+			hasError:    true,
+		},
+		{
+			description: "unknown field",
+			source:      records,
+			fn:          wrap(As[string], "Code"),
+			hasError:    true,
+		},
+		{
+			description: "unsupported target",
+			source:      []int{1},
+			fn:          wrap(As[int], "ID"),
+			hasError:    true,
+		},
+		{
+			description: "map",
+			source:      records,
+			fn: func(target reflect.Type) (func(value interface{}) (interface{}, error), error) {
+				fn, err := AsMap[int, string](target, "ID", "Name")
+				return func(value interface{}) (interface{}, error) { return fn(value) }, err
+			},
+			expect: map[int]string{1: "c", 2: "b"},
+		},
+		{
+			description: "index of row pointers",
+			source:      []Record{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}},
+			fn: func(target reflect.Type) (func(value interface{}) (interface{}, error), error) {
+				fn, err := AsIndex[string, *Record](target, "Name")
+				return func(value interface{}) (interface{}, error) { return fn(value) }, err
+			},
+			expect: map[string]*Record{"a": {ID: 1, Name: "a"}, "b": {ID: 2, Name: "b"}},
+		},
+		{
+			description: "index of rows",
+			source:      &records,
+			fn: func(target reflect.Type) (func(value interface{}) (interface{}, error), error) {
+				fn, err := AsIndex[int, Record](target, "ID")
+				return func(value interface{}) (interface{}, error) { return fn(value) }, err
+			},
+			expect: map[int]Record{1: {ID: 1, Name: "c"}, 2: {ID: 2, Name: "b"}},
+		},
+		{
+			description: "index row type mismatch",
+			source:      records,
+			fn: func(target reflect.Type) (func(value interface{}) (interface{}, error), error) {
+				fn, err := AsIndex[int, string](target, "ID")
+				return func(value interface{}) (interface{}, error) { return fn(value) }, err
+			},
+			hasError: true,
+		},
+		{
+			description: "set",
+			source:      records,
+			fn: func(target reflect.Type) (func(value interface{}) (interface{}, error), error) {
+				fn, err := AsSet[int](target, "ID")
+				return func(value interface{}) (interface{}, error) { return fn(value) }, err
+			},
+			expect: map[int]bool{1: true, 2: true},
+		},
+	}
+
+	for _, testCase := range testCases {
+		fn, err := testCase.fn(reflect.TypeOf(testCase.source))
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		actual, err := fn(testCase.source)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+
+	fn, err := AsSet[int](reflect.TypeOf(records), "ID")
+	assert.Nil(t, err)
+	_, err = fn([]Record{})
+	assert.True(t, errors.Is(err, errs.ErrUnsupportedType), "value type mismatch")
+	fn, err = AsSet[int](reflect.TypeOf(records), "ID")
+	assert.Nil(t, err)
+	set, err := fn(&records)
+	assert.Nil(t, err, "slice pointer value of slice target")
+	assert.EqualValues(t, map[int]bool{1: true, 2: true}, set)
+	_, err = As[string](reflect.TypeOf(records), "Ids")
+	assert.True(t, errors.Is(err, errs.ErrConversion), "field type mismatch")
+}
+
+func wrap[T any](fn func(target reflect.Type, name string) (func(value interface{}) (T, error), error), name string) func(target reflect.Type) (func(value interface{}) (interface{}, error), error) {
+	return func(target reflect.Type) (func(value interface{}) (interface{}, error), error) {
+		ret, err := fn(target, name)
+		return func(value interface{}) (interface{}, error) { return ret(value) }, err
+	}
+}
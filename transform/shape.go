@@ -0,0 +1,152 @@
+package transform
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/viant/structql/errs"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+// shape represents query result shape: T, *T, []T, []*T, *[]T or *[]*T of struct T,
+// values of a type and a pointer to the type share the shape
+type shape struct {
+	target  reflect.Type
+	rowType reflect.Type
+	xSlice  *xunsafe.Slice
+}
+
+// each calls visitor with every result row pointer, nil rows are skipped
+func (s *shape) each(value interface{}, visitor func(ptr unsafe.Pointer) error) error {
+	ptr, err := s.pointer(value)
+	if err != nil || ptr == nil {
+		return err
+	}
+	if s.xSlice == nil {
+		return visitor(ptr)
+	}
+	for i := 0; i < s.xSlice.Len(ptr); i++ {
+		if itemPtr := xunsafe.AsPointer(s.xSlice.ValuePointerAt(ptr, i)); itemPtr != nil {
+			if err = visitor(itemPtr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// first returns the first result row pointer, or nil
+func (s *shape) first(value interface{}) (unsafe.Pointer, error) {
+	ptr, err := s.pointer(value)
+	if err != nil || ptr == nil || s.xSlice == nil {
+		return ptr, err
+	}
+	for i := 0; i < s.xSlice.Len(ptr); i++ {
+		if itemPtr := xunsafe.AsPointer(s.xSlice.ValuePointerAt(ptr, i)); itemPtr != nil {
+			return itemPtr, nil
+		}
+	}
+	return nil, nil
+}
+
+// pointer returns value pointer, value has to be of the target type or a pointer to the target type
+func (s *shape) pointer(value interface{}) (unsafe.Pointer, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if actual := reflect.TypeOf(value); shapeType(actual) != s.target {
+		return nil, errs.UnsupportedType("invalid value type: %s, expected %s", actual.String(), s.target.String())
+	}
+	return xunsafe.AsPointer(value), nil
+}
+
+// field returns row field
+func (s *shape) field(name string) (*xunsafe.Field, error) {
+	ret := xunsafe.FieldByName(s.rowType, name)
+	if ret == nil {
+		return nil, errs.UnknownField("failed to lookup field: %s on %s", name, s.rowType.String())
+	}
+	return ret, nil
+}
+
+func newShape(target reflect.Type) (*shape, error) {
+	if target == nil {
+		return nil, errs.UnsupportedType("unsupported type: nil")
+	}
+	ret := &shape{target: shapeType(target)}
+	rType := ret.target
+	if rType.Kind() == reflect.Slice {
+		ret.xSlice = xunsafe.NewSlice(rType)
+		rType = rType.Elem()
+	}
+	if rType.Kind() == reflect.Ptr {
+		rType = rType.Elem()
+	}
+	if rType.Kind() != reflect.Struct {
+		return nil, errs.UnsupportedType("unsupported type: %s, expected struct, struct pointer or slice", target.String())
+	}
+	ret.rowType = rType
+	return ret, nil
+}
+
+// shapeType returns slice or struct type of the value or of the pointer value
+func shapeType(rType reflect.Type) reflect.Type {
+	if rType.Kind() == reflect.Ptr {
+		switch rType.Elem().Kind() {
+		case reflect.Slice, reflect.Struct:
+			return rType.Elem()
+		}
+	}
+	return rType
+}
+
+// fieldConverter returns function reading field value as T, named types of the same kind are converted,
+// pointer fields are dereferenced with nil returned as zero value
+func fieldConverter[T any](aField *xunsafe.Field) (func(ptr unsafe.Pointer) T, error) {
+	var zero T
+	target := reflect.TypeOf(&zero).Elem()
+	fieldType := aField.Type
+	if fieldType.AssignableTo(target) {
+		return func(ptr unsafe.Pointer) T {
+			if value, ok := aField.Interface(ptr).(T); ok {
+				return value
+			}
+			return zero
+		}, nil
+	}
+	isPtr := fieldType.Kind() == reflect.Ptr
+	if isPtr {
+		fieldType = fieldType.Elem()
+	}
+	if !fieldType.ConvertibleTo(target) || scalar.KindOf(fieldType).IsNumeric() != scalar.KindOf(target).IsNumeric() {
+		return nil, errs.Conversion("failed to convert field: %s %s to %s", aField.Name, aField.Type.String(), target.String())
+	}
+	return func(ptr unsafe.Pointer) T {
+		value := reflect.ValueOf(aField.Interface(ptr))
+		if isPtr {
+			if value.IsNil() {
+				return zero
+			}
+			value = value.Elem()
+		}
+		return value.Convert(target).Interface().(T)
+	}, nil
+}
+
+// rowConverter returns function reading row as R, where R is the row struct or the row struct pointer
+func rowConverter[R any](rowType reflect.Type) (func(ptr unsafe.Pointer) R, error) {
+	var zero R
+	target := reflect.TypeOf(&zero).Elem()
+	switch {
+	case target == rowType:
+		return func(ptr unsafe.Pointer) R {
+			return reflect.NewAt(rowType, ptr).Elem().Interface().(R)
+		}, nil
+	case target == reflect.PtrTo(rowType):
+		return func(ptr unsafe.Pointer) R {
+			return reflect.NewAt(rowType, ptr).Interface().(R)
+		}, nil
+	}
+	return nil, errs.Conversion("failed to convert row: %s to %s", rowType.String(), target.String())
+}
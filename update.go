@@ -0,0 +1,273 @@
+package structql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/structql/errs"
+	node "github.com/viant/structql/node"
+	sparser "github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+type (
+	// Update represents UPDATE statement modifying source objects selected by the target path in place
+	Update struct {
+		query       string
+		node        *Node
+		assignments []*assignment
+		Binding     *node.Binding
+		limits      Limits
+	}
+
+	// assignment represents SET column = expression
+	assignment struct {
+		field
+		expression sparser.Expression
+	}
+)
+
+// Exec updates matched source elements, it returns number of affected elements,
+// source has to be a pointer or a slice so that changes are visible to the caller
+func (u *Update) Exec(source interface{}) (int, error) {
+	return u.ExecContext(context.Background(), source)
+}
+
+// ExecContext updates matched source elements, the walk is stopped with context error when context is done
+// or with LimitError when visited nodes cap is exceeded
+func (u *Update) ExecContext(ctx context.Context, source interface{}) (affected int, err error) {
+	defer scalar.Recover(&err)
+	if err = checkMutableSource(source); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if affected > 0 { //indexes built over changed elements are stale
+			mutated(unwrapStruct(u.node.LeafType()))
+		}
+	}()
+	var items []unsafe.Pointer
+	var values []reflect.Value
+	err = visitItems(newGuard(ctx, u.limits), u.node, source, func(itemPtr unsafe.Pointer) error {
+		for _, assign := range u.assignments { //all expressions see original values
+			value, err := assign.value(itemPtr)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+		items = append(items, itemPtr)
+		return nil
+	})
+	if err != nil { //no element is changed unless all values were computed
+		return 0, err
+	}
+	for k, itemPtr := range items {
+		for i, assign := range u.assignments {
+			assign.assign(itemPtr, values[k*len(u.assignments)+i])
+		}
+	}
+	return len(items), nil
+}
+
+// value computes assigned value converted to the field type, invalid value represents NULL
+func (a *assignment) value(itemPtr unsafe.Pointer) (reflect.Value, error) {
+	value, ok := a.expression(itemPtr)
+	if !ok {
+		return reflect.Value{}, nil
+	}
+	rValue := reflect.ValueOf(value)
+	for rValue.Kind() == reflect.Ptr && !rValue.IsNil() {
+		rValue = rValue.Elem()
+	}
+	if !rValue.IsValid() || rValue.Kind() == reflect.Ptr {
+		return reflect.Value{}, nil
+	}
+	destType := a.dest.Type
+	if destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
+	}
+	if !assignable(rValue.Type(), destType) {
+		return reflect.Value{}, errs.Conversion("failed to assign %s value to '%s' %s", rValue.Type().String(), a.dest.Name, a.dest.Type.String())
+	}
+	ret := reflect.New(destType).Elem()
+	if overflows(rValue, ret) {
+		return reflect.Value{}, errs.Conversion("failed to assign %v value to '%s' %s: value out of range", rValue.Interface(), a.dest.Name, a.dest.Type.String())
+	}
+	ret.Set(rValue.Convert(destType))
+	return ret, nil
+}
+
+// assign sets value computed by the assignment, NULL value sets field zero value
+func (a *assignment) assign(itemPtr unsafe.Pointer, value reflect.Value) {
+	dest := reflect.NewAt(a.dest.Type, a.dest.Pointer(itemPtr)).Elem()
+	switch {
+	case !value.IsValid():
+		dest.Set(reflect.Zero(a.dest.Type))
+	case a.dest.Type.Kind() == reflect.Ptr:
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		dest.Set(ptr)
+	default:
+		dest.Set(value)
+	}
+}
+
+// checkAssignable returns error if values of the expression type can not be assigned to the field,
+// NULL (nil type) and types known only at runtime (interface{}) are checked by value
+func checkAssignable(valueType reflect.Type, dest *xunsafe.Field) error {
+	if valueType == nil || valueType.Kind() == reflect.Interface {
+		return nil
+	}
+	destType := dest.Type
+	if destType.Kind() == reflect.Ptr {
+		destType = destType.Elem()
+	}
+	if !assignable(valueType, destType) {
+		return errs.Conversion("failed to assign %s value to '%s' %s", valueType.String(), dest.Name, dest.Type.String())
+	}
+	return nil
+}
+
+// assignable returns true if value type can be converted to dest type without changing value kind,
+// int value can be assigned to float field, float value is not assigned to int field as it would be truncated
+func assignable(valueType, destType reflect.Type) bool {
+	if valueType == destType {
+		return true
+	}
+	valueKind, destKind := scalar.KindOf(valueType), scalar.KindOf(destType)
+	switch {
+	case valueKind == destKind:
+	case valueKind == scalar.Int && destKind == scalar.Float:
+	default:
+		return false
+	}
+	return valueType.ConvertibleTo(destType)
+}
+
+// overflows returns true if numeric value can not be represented by dest value type
+func overflows(value, dest reflect.Value) bool {
+	switch dest.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return dest.OverflowInt(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return value.Uint() > math.MaxInt64 || dest.OverflowInt(int64(value.Uint()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return value.Int() < 0 || dest.OverflowUint(uint64(value.Int()))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return dest.OverflowUint(value.Uint())
+		}
+	case reflect.Float32:
+		if value.Kind() == reflect.Float64 {
+			return dest.OverflowFloat(value.Float())
+		}
+	}
+	return false
+}
+
+// checkMutableSource returns error if source changes would not be visible to the caller
+func checkMutableSource(source interface{}) error {
+	switch reflect.TypeOf(source).Kind() {
+	case reflect.Ptr, reflect.Slice:
+		return nil
+	}
+	return errs.UnsupportedType("invalid source: %T, expected pointer or slice", source)
+}
+
+// visitItems walks the node calling visitor with addressable pointers of matched leaf items
+func visitItems(aGuard *guard, aNode *Node, value interface{}, visitor func(itemPtr unsafe.Pointer) error) error {
+	if err := aGuard.visit(); err != nil {
+		return err
+	}
+	if !aNode.When(value) {
+		return nil
+	}
+	ptr := xunsafe.AsPointer(value)
+	if ptr == nil {
+		return nil
+	}
+	if aNode.IsLeaf {
+		return visitor(ptr)
+	}
+	switch aNode.kind {
+	case nodeKindObject:
+		return visitItems(aGuard, aNode.child, aNode.fieldItem(ptr), visitor)
+	case nodeKindArray:
+		from, to, at := aNode.positions(ptr)
+		for i := from; i < to; i++ {
+			if err := visitItems(aGuard, aNode.child, aNode.xSlice.ValuePointerAt(ptr, at(i)), visitor); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldItem returns object node field value, struct field is returned as pointer so that changes are applied to the owner
+func (n *Node) fieldItem(ptr unsafe.Pointer) interface{} {
+	if n.xField.Type.Kind() == reflect.Struct {
+		return reflect.NewAt(n.xField.Type, n.xField.Pointer(ptr)).Interface()
+	}
+	return n.xField.Interface(ptr)
+}
+
+// NewUpdate returns UPDATE statement for the source type, i.e.
+// UPDATE `/Products[Active=true]/Performance` SET Revenue = Revenue * 1.1 WHERE ProductID = ?
+func NewUpdate(SQL string, source reflect.Type, values ...interface{}) (*Update, error) {
+	if unwrapStruct(source) == nil {
+		return nil, errs.UnsupportedType("invalid source type: %s", source.String())
+	}
+	opts, values, err := newOptions(values)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Update{query: SQL, Binding: &node.Binding{}, limits: opts.limits}
+	value := &node.Values{Values: values, Bindings: ret.Binding, Functions: opts.functions}
+	stmt, err := sparser.ParseUpdate(SQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %w, %v", err, SQL)
+	}
+	if ret.node, err = newSelectorNode(source, stmt.Target.X, value, opts); err != nil {
+		return nil, err
+	}
+	itemType := unwrapStruct(ret.node.LeafType())
+	if itemType == nil {
+		return nil, errs.UnsupportedType("invalid update target: %s, expected struct", ret.node.LeafType().String())
+	}
+	lookup := node.LookupFieldType("", itemType)
+	for _, item := range stmt.Set {
+		column, ok := item.Column.(*expr.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported update column: %v", sqlparser.Stringify(item.Column))
+		}
+		aField := xunsafe.FieldByName(itemType, column.Name)
+		if aField == nil {
+			return nil, errs.UnknownField("failed to lookup update field: '%s' at %s", column.Name, itemType.String())
+		}
+		expression, valueType, err := sparser.AsExpression(item.Expr, lookup, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid update column '%s' expression: %w", column.Name, err)
+		}
+		if err = checkAssignable(valueType, aField); err != nil {
+			return nil, err
+		}
+		ret.assignments = append(ret.assignments, &assignment{field: field{dest: aField}, expression: expression})
+	}
+	if err = ret.node.compileQualify(stmt.Qualify, value, opts); err != nil {
+		return nil, err
+	}
+	if err = value.Validate(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
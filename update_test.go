@@ -0,0 +1,158 @@
+package structql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"reflect"
+	"testing"
+)
+
+func TestNewUpdate(t *testing.T) {
+	type Metric struct {
+		ProductID int
+		Revenue   float64
+		Units     int32
+		Note      *string
+	}
+	type Product struct {
+		ID          int
+		Active      bool
+		Performance []*Metric
+		Summary     Metric
+	}
+	type Vendor struct {
+		Products []Product
+	}
+	note := "n"
+	newVendor := func() *Vendor {
+		return &Vendor{Products: []Product{
+			{ID: 1, Active: true, Performance: []*Metric{{ProductID: 1, Revenue: 100, Units: 2}, {ProductID: 2, Revenue: 10, Units: 1, Note: &note}}},
+			{ID: 2, Active: false, Performance: []*Metric{{ProductID: 1, Revenue: 50, Units: 5}}},
+			{ID: 3, Active: true, Performance: []*Metric{{ProductID: 1, Revenue: 20, Units: 3}}, Summary: Metric{Revenue: 1}},
+		}}
+	}
+
+	var testCases = []struct {
+		description    string
+		query          string
+		values         []interface{}
+		expectAffected int
+		expect         string
+		hasError       bool
+		hasExecError   bool
+	}{
+		{
+			description:    "arithmetic with criteria",
+			query:          "UPDATE `/Products[Active=true]/Performance` SET Revenue = Revenue * 1.5 WHERE ProductID = ?",
+			values:         []interface{}{1},
+			expectAffected: 2,
+			expect:         `[{"ID":1,"Performance":[{"Revenue":150},{"Revenue":10}]},{"ID":2,"Performance":[{"Revenue":50}]},{"ID":3,"Performance":[{"Revenue":30}]}]`,
+		},
+		{
+			description:    "conversion and pointer allocation",
+			query:          "UPDATE `/Products/Performance` SET Units = Units + ?, Note = ? WHERE Note IS NULL",
+			values:         []interface{}{int64(10), "updated"},
+			expectAffected: 3,
+			expect:         `[{"Performance":[{"Units":12,"Note":"updated"},{"Units":1,"Note":"n"}]},{"Performance":[{"Units":15,"Note":"updated"}]},{"Performance":[{"Units":13,"Note":"updated"}]}]`,
+		},
+		{
+			description:    "original values with null",
+			query:          "UPDATE `/Products/Performance` SET Units = ProductID, Revenue = Units, Note = NULL WHERE ProductID = 2",
+			expectAffected: 1,
+			expect:         `[{"Performance":[{"Units":2,"Revenue":100},{"Units":2,"Revenue":1}]}]`,
+		},
+		{
+			description:    "struct field",
+			query:          "UPDATE `/Products[Active=true]/Summary` SET Revenue = Revenue + 1, Units = LENGTH('abc')",
+			expectAffected: 2,
+			expect:         `[{"Summary":{"Revenue":1,"Units":3}},{"Summary":{"Revenue":0,"Units":0}},{"Summary":{"Revenue":2,"Units":3}}]`,
+		},
+		{
+			description:    "no match",
+			query:          "UPDATE `/Products[ID=?]/Performance` SET Revenue = 0",
+			values:         []interface{}{10},
+			expectAffected: 0,
+			expect:         `[{"Performance":[{"Revenue":100},{"Revenue":10}]}]`,
+		},
+		{
+			description: "unknown column",
+			query:       "UPDATE `/Products/Performance` SET Price = 1",
+			hasError:    true,
+		},
+		{
+			description: "unknown expression field",
+			query:       "UPDATE `/Products/Performance` SET Revenue = Price",
+			hasError:    true,
+		},
+		{
+			description: "missing placeholder value",
+			query:       "UPDATE `/Products/Performance` SET Revenue = ?",
+			hasError:    true,
+		},
+		{
+			description: "incompatible value",
+			query:       "UPDATE `/Products/Performance` SET Revenue = 1, Units = 'abc'",
+			hasError:    true,
+		},
+		{
+			description: "truncated float value",
+			query:       "UPDATE `/Products/Performance` SET Units = 1.7",
+			hasError:    true,
+		},
+		{
+			description: "truncated float expression",
+			query:       "UPDATE `/Products/Performance` SET Units = Revenue",
+			hasError:    true,
+		},
+		{
+			description:  "overflowing value leaves data unchanged",
+			query:        "UPDATE `/Products/Performance` SET Revenue = 0, Units = Units + ?",
+			values:       []interface{}{2147483645},
+			hasExecError: true,
+		},
+		{
+			description:  "exceeded limit leaves data unchanged",
+			query:        "UPDATE `/Products/Performance` SET Revenue = 0",
+			values:       []interface{}{WithMaxNodes(5)},
+			hasExecError: true,
+		},
+	}
+
+	for _, evaluator := range []Evaluator{IgoEvaluator, NativeEvaluator} {
+		for _, testCase := range testCases {
+			description := testCase.description + " (" + string(evaluator) + ")"
+			update, err := NewUpdate(testCase.query, reflect.TypeOf(&Vendor{}), append(testCase.values, WithEvaluator(evaluator))...)
+			if testCase.hasError {
+				assert.NotNil(t, err, description)
+				continue
+			}
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			vendor := newVendor()
+			affected, err := update.Exec(vendor)
+			if testCase.hasExecError {
+				assert.NotNil(t, err, description)
+				assert.EqualValues(t, newVendor(), vendor, description)
+				continue
+			}
+			if !assert.Nil(t, err, description) {
+				continue
+			}
+			assert.EqualValues(t, testCase.expectAffected, affected, description)
+			assertly.AssertValues(t, testCase.expect, vendor.Products, description)
+		}
+	}
+
+	update, err := NewUpdate("UPDATE `/` SET Revenue = 1", reflect.TypeOf(Metric{}))
+	assert.Nil(t, err)
+	_, err = update.Exec(Metric{})
+	assert.NotNil(t, err, "non pointer source")
+	metrics := []Metric{{Revenue: 3}, {Revenue: 4}}
+	update, err = NewUpdate("UPDATE `/` SET Revenue = 1", reflect.TypeOf(metrics))
+	assert.Nil(t, err)
+	affected, err := update.Exec(metrics)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, affected)
+	assert.EqualValues(t, []Metric{{Revenue: 1}, {Revenue: 1}}, metrics)
+}
@@ -3,6 +3,7 @@ package structql
 import (
 	"fmt"
 	"github.com/viant/sqlparser"
+	"github.com/viant/structql/errs"
 	"github.com/viant/structql/node"
 	sparser "github.com/viant/structql/parser"
 	"reflect"
@@ -13,10 +14,18 @@ import (
 func IsStructTypeQuery(query string, source reflect.Type, values ...interface{}) (bool, error) {
 	var err error
 	if unwrapStruct(source) == nil {
-		return false, fmt.Errorf("invalid source type: %s", source.String())
+		return false, errs.UnsupportedType("invalid source type: %s", source.String())
+	}
+	_, values, err = newOptions(values)
+	if err != nil {
+		return false, err
 	}
 	ret := &Query{query: query, source: source}
-	if ret.sel, err = sqlparser.ParseQuery(query); err != nil {
+	statement, _, err := sparser.SplitWindows(query)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse %w, %v", err, query)
+	}
+	if ret.sel, err = sparser.ParseQuery(statement); err != nil {
 		return false, fmt.Errorf("failed to parse %w, %v", err, query)
 	}
 	value := &node.Values{Values: values, Bindings: ret.Binding}
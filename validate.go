@@ -0,0 +1,448 @@
+package structql
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	snode "github.com/viant/sqlparser/node"
+	"github.com/viant/sqlparser/query"
+	"github.com/viant/structql/node"
+	sparser "github.com/viant/structql/parser"
+	"github.com/viant/xunsafe"
+)
+
+// diagnostic kinds
+const (
+	SyntaxDiagnostic       DiagnosticKind = "syntax"
+	UnknownFieldDiagnostic DiagnosticKind = "unknownField"
+	TypeDiagnostic         DiagnosticKind = "type"
+	CompileDiagnostic      DiagnosticKind = "compile"
+)
+
+const maxSuggestions = 3
+
+var (
+	groupByExpr = regexp.MustCompile(`(?i)\bgroup\s+by\b`)
+	orderByExpr = regexp.MustCompile(`(?i)\border\s+by\b`)
+)
+
+type (
+	// DiagnosticKind represents validation problem kind
+	DiagnosticKind string
+
+	// Diagnostic represents query validation problem
+	Diagnostic struct {
+		Kind        DiagnosticKind
+		Message     string
+		Offset      int //byte offset in SQL, -1 if unknown
+		Line        int //1-based line, 0 if unknown
+		Column      int //1-based byte column, 0 if unknown
+		Suggestions []string
+	}
+
+	// validator collects query diagnostics
+	validator struct {
+		SQL         string
+		diagnostics []*Diagnostic
+	}
+)
+
+// Error returns diagnostic message with position
+func (d *Diagnostic) Error() string {
+	ret := string(d.Kind) + ": " + d.Message
+	if d.Line > 0 {
+		ret += " at line " + strconv.Itoa(d.Line) + ", column " + strconv.Itoa(d.Column)
+	}
+	if len(d.Suggestions) > 0 {
+		ret += ", did you mean: " + strings.Join(d.Suggestions, ", ") + "?"
+	}
+	return ret
+}
+
+// Validate validates query against source and optional dest type, it returns diagnostics or nil if query is valid
+func Validate(SQL string, source, dest reflect.Type, values ...interface{}) []*Diagnostic {
+	v := &validator{SQL: SQL}
+	if unwrapStruct(source) == nil {
+		v.add(TypeDiagnostic, fmt.Sprintf("invalid source type: %v", source), -1)
+		return v.diagnostics
+	}
+	statement, windows, err := sparser.SplitWindows(SQL)
+	if err != nil {
+		v.add(SyntaxDiagnostic, err.Error(), errorOffset(err))
+		return v.diagnostics
+	}
+	sel, err := sparser.ParseQuery(statement)
+	if err != nil {
+		v.add(SyntaxDiagnostic, err.Error(), v.syntaxOffset(errorOffset(err)))
+		return v.diagnostics
+	}
+	from := strings.Trim(sqlparser.Stringify(sel.From.X), "`")
+	fromOffset := v.locate(from, 0, false)
+	selector, err := sparser.ParseSelector(from)
+	if err != nil {
+		offset := errorOffset(err)
+		if offset != -1 && fromOffset != -1 {
+			offset += fromOffset
+		}
+		v.add(SyntaxDiagnostic, err.Error(), offset)
+		return v.diagnostics
+	}
+	leaf := v.validateSelector(source, selector, fromOffset)
+	if len(v.diagnostics) > 0 || leaf == nil {
+		return v.diagnostics
+	}
+	v.validateColumns(sel, leaf, unwrapStruct(dest))
+	v.validateWindows(windows, len(sel.List), leaf)
+	if len(v.diagnostics) > 0 {
+		return v.diagnostics
+	}
+	if _, err = NewQuery(SQL, source, dest, values...); err != nil {
+		v.add(CompileDiagnostic, err.Error(), errorOffset(err))
+	}
+	return v.diagnostics
+}
+
+// validateSelector validates FROM selector path, it returns leaf struct type
+func (v *validator) validateSelector(source reflect.Type, selector *node.Selector, fromOffset int) reflect.Type {
+	ownerType := source
+	parentName := ""
+	for sel := selector; sel != nil; sel = sel.Child {
+		ownerType = elemType(ownerType)
+		structType := unwrapStruct(ownerType)
+		if sel.Criteria != nil && structType != nil {
+			holder := sel.Holder
+			if holder == "" {
+				holder = parentName
+			}
+			v.validateRefs(fieldRefs(sel.Criteria, holder, nil), structType, shift(fromOffset, sel.CriteriaOffset))
+		}
+		parentName = sel.Name
+		if sel.Name == "" {
+			continue
+		}
+		offset := shift(fromOffset, sel.Offset)
+		if structType == nil || structType != ownerType {
+			v.add(TypeDiagnostic, fmt.Sprintf("invalid selector: '%s', %s is not a struct", sel.Name, ownerType.String()), offset)
+			return nil
+		}
+		aField := xunsafe.FieldByName(structType, sel.Name)
+		if aField == nil {
+			v.addUnknown(fmt.Sprintf("failed to lookup field: '%s' at %s", sel.Name, structType.String()), offset, sel.Name, structType)
+			return nil
+		}
+		ownerType = aField.Type
+	}
+	return unwrapStruct(ownerType)
+}
+
+// elemType returns slice item or pointer element type
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+// validateColumns validates field references of query clauses and dest fields
+func (v *validator) validateColumns(sel *query.Select, source, dest reflect.Type) {
+	aliases := map[string]bool{}
+	cursor := 0
+	for _, item := range sel.List {
+		if _, ok := item.Expr.(*expr.Star); ok {
+			continue
+		}
+		if call, ok := item.Expr.(*expr.Call); ok && strings.EqualFold(sqlparser.Stringify(call.X), "COUNT") {
+			if _, ok := call.Args[0].(*expr.Star); ok && len(call.Args) == 1 {
+				continue
+			}
+		}
+		refs := fieldRefs(item.Expr, sel.From.Alias, nil)
+		itemCursor := cursor
+		cursor = v.validateRefs(refs, source, cursor)
+		name := item.Alias
+		if name == "" && len(refs) == 1 {
+			if _, ok := item.Expr.(*expr.Call); !ok {
+				name = refs[0]
+			}
+		}
+		if name == "" {
+			continue
+		}
+		aliases[strings.ToLower(name)] = true
+		if dest != nil && xunsafe.FieldByName(dest, name) == nil {
+			offset := v.locate(name, itemCursor, true)
+			if offset != -1 && offset+len(name) > cursor {
+				cursor = offset + len(name)
+			}
+			v.addUnknown(fmt.Sprintf("failed to lookup dest field: '%s' at %s", name, dest.String()), offset, name, dest)
+		}
+	}
+	if sel.Qualify != nil {
+		_, criteria, offset := sparser.SplitCriteria(v.SQL)
+		if criteria == "" {
+			offset = 0
+		}
+		v.validateRefs(fieldRefs(sel.Qualify, sel.From.Alias, nil), source, offset)
+	}
+	for _, clause := range []struct {
+		list  query.List
+		match *regexp.Regexp
+	}{{sel.GroupBy, groupByExpr}, {sel.OrderBy, orderByExpr}} {
+		offset := 0
+		if loc := clause.match.FindStringIndex(v.SQL); loc != nil {
+			offset = loc[1]
+		}
+		for _, item := range clause.list {
+			var refs []string
+			for _, ref := range fieldRefs(item.Expr, sel.From.Alias, nil) {
+				if !aliases[strings.ToLower(ref)] {
+					refs = append(refs, ref)
+				}
+			}
+			offset = v.validateRefs(refs, source, offset)
+		}
+	}
+}
+
+// validateWindows validates window PARTITION BY and ORDER BY field references
+func (v *validator) validateWindows(windows map[int]*sparser.Window, items int, source reflect.Type) {
+	cursor := 0
+	for i := 0; i < items; i++ {
+		window, ok := windows[i]
+		if !ok {
+			continue
+		}
+		refs := append([]string{}, window.Partition...)
+		for _, item := range window.Order {
+			refs = append(refs, item.Column)
+		}
+		cursor = v.validateRefs(refs, source, cursor)
+	}
+}
+
+// validateRefs validates field references, collection references i.e. Items[Price > 100] are validated
+// with their criteria against slice item type, it returns updated search cursor
+func (v *validator) validateRefs(refs []string, source reflect.Type, cursor int) int {
+	for _, ref := range refs {
+		name, criteria := ref, ""
+		if index := strings.Index(ref, "["); index != -1 && strings.HasSuffix(ref, "]") {
+			name, criteria = ref[:index], ref[index+1:len(ref)-1]
+		}
+		offset := -1
+		if cursor != -1 {
+			if offset = v.locate(name, cursor, true); offset != -1 {
+				cursor = offset + len(name)
+			}
+		}
+		aField := xunsafe.FieldByName(source, name)
+		if aField == nil {
+			v.addUnknown(fmt.Sprintf("failed to lookup field: '%s' at %s", name, source.String()), offset, name, source)
+			continue
+		}
+		if criteria != "" {
+			if end := v.validateCollection(aField, criteria, offset); end != -1 {
+				cursor = end
+			}
+		}
+	}
+	return cursor
+}
+
+// validateCollection validates collection criteria field references against slice item type, it returns updated search cursor
+func (v *validator) validateCollection(aField *xunsafe.Field, criteria string, offset int) int {
+	criteriaOffset := shift(offset, len(aField.Name)+1)
+	fType := aField.Type
+	if fType.Kind() == reflect.Ptr {
+		fType = fType.Elem()
+	}
+	if fType.Kind() != reflect.Slice || unwrapStruct(fType.Elem()) == nil {
+		v.add(TypeDiagnostic, fmt.Sprintf("invalid collection: '%s', %s is not a struct slice", aField.Name, aField.Type.String()), offset)
+		return criteriaOffset
+	}
+	aNode, err := sparser.ParseCriteria("", []byte(criteria), 0)
+	if err != nil {
+		v.add(SyntaxDiagnostic, err.Error(), shift(criteriaOffset, errorOffset(err)))
+		return criteriaOffset
+	}
+	return v.validateRefs(fieldRefs(aNode, "", nil), unwrapStruct(fType.Elem()), criteriaOffset)
+}
+
+// shift returns offset moved by delta, or -1 if either is unknown
+func shift(offset, delta int) int {
+	if offset == -1 || delta == -1 {
+		return -1
+	}
+	return offset + delta
+}
+
+// fieldRefs returns field names referenced by expression, function names, literals and placeholders are skipped
+func fieldRefs(n snode.Node, holder string, result []string) []string {
+	switch actual := n.(type) {
+	case *expr.Ident:
+		name := actual.Name
+		if holder != "" && strings.HasPrefix(name, holder+".") {
+			name = name[len(holder)+1:]
+		}
+		switch strings.ToLower(name) {
+		case "true", "false", "null":
+			return result
+		}
+		return append(result, name)
+	case *expr.Selector:
+		if holder != "" && actual.Name == holder {
+			return fieldRefs(actual.X, "", result)
+		}
+		return append(result, actual.Name)
+	case *expr.Call:
+		for _, arg := range actual.Args {
+			result = fieldRefs(arg, holder, result)
+		}
+	case *expr.Binary:
+		return fieldRefs(actual.Y, holder, fieldRefs(actual.X, holder, result))
+	case *expr.Unary:
+		return fieldRefs(actual.X, holder, result)
+	case *expr.Parenthesis:
+		return fieldRefs(actual.X, holder, result)
+	case *expr.Range:
+		return fieldRefs(actual.Max, holder, fieldRefs(actual.Min, holder, result))
+	case *expr.Qualify:
+		return fieldRefs(actual.X, holder, result)
+	case []snode.Node:
+		for _, item := range actual {
+			result = fieldRefs(item, holder, result)
+		}
+	}
+	return result
+}
+
+// add adds diagnostic with position
+func (v *validator) add(kind DiagnosticKind, message string, offset int) *Diagnostic {
+	ret := &Diagnostic{Kind: kind, Message: message, Offset: offset}
+	if offset >= 0 && offset <= len(v.SQL) {
+		ret.Line = strings.Count(v.SQL[:offset], "\n") + 1
+		ret.Column = offset - strings.LastIndex(v.SQL[:offset], "\n")
+	} else {
+		ret.Offset = -1
+	}
+	v.diagnostics = append(v.diagnostics, ret)
+	return ret
+}
+
+// addUnknown adds unknown field diagnostic with owner type field suggestions
+func (v *validator) addUnknown(message string, offset int, name string, owner reflect.Type) {
+	v.add(UnknownFieldDiagnostic, message, offset).Suggestions = suggestFields(name, owner)
+}
+
+// syntaxOffset converts query parser error position to SQL offset, WHERE clause is parsed with SQL offsets,
+// the remaining statement without WHERE clause with its own positions
+func (v *validator) syntaxOffset(offset int) int {
+	statement, criteria, criteriaOffset := sparser.SplitCriteria(v.SQL)
+	if offset == -1 || statement == v.SQL {
+		return offset
+	}
+	if _, err := sparser.ParseCriteria("", []byte(criteria), criteriaOffset); err != nil {
+		return offset
+	}
+	whereBegin := criteriaOffset - len("where")
+	if offset <= whereBegin {
+		return offset
+	}
+	return criteriaOffset + len(criteria) + offset - whereBegin - 1
+}
+
+// locate returns offset of text occurrence starting from cursor, or -1, word occurrences skip quoted text
+func (v *validator) locate(text string, cursor int, word bool) int {
+	if text == "" || cursor < 0 || cursor > len(v.SQL) {
+		return -1
+	}
+	if !word {
+		if index := strings.Index(v.SQL[cursor:], text); index != -1 {
+			return cursor + index
+		}
+		return -1
+	}
+	for i := cursor; i+len(text) <= len(v.SQL); i++ {
+		switch v.SQL[i] {
+		case '\'', '"':
+			if end := strings.IndexByte(v.SQL[i+1:], v.SQL[i]); end != -1 {
+				i += end + 1
+			}
+			continue
+		}
+		if !strings.EqualFold(v.SQL[i:i+len(text)], text) {
+			continue
+		}
+		if i > 0 && isWordByte(v.SQL[i-1]) {
+			continue
+		}
+		if end := i + len(text); end < len(v.SQL) && isWordByte(v.SQL[end]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// errorOffset returns syntax error offset, or -1
+func errorOffset(err error) int {
+	var syntaxErr *SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return syntaxErr.Offset
+	}
+	return -1
+}
+
+// suggestFields returns owner field names similar to the supplied name
+func suggestFields(name string, owner reflect.Type) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	threshold := len(name)/3 + 1
+	for i := 0; i < owner.NumField(); i++ {
+		fieldName := owner.Field(i).Name
+		distance := editDistance(strings.ToLower(name), strings.ToLower(fieldName))
+		if distance <= threshold || strings.Contains(strings.ToLower(fieldName), strings.ToLower(name)) {
+			candidates = append(candidates, candidate{name: fieldName, distance: distance})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	var ret []string
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		ret = append(ret, candidates[i].name)
+	}
+	return ret
+}
+
+// editDistance returns Levenshtein distance
+func editDistance(x, y string) int {
+	previous := make([]int, len(y)+1)
+	current := make([]int, len(y)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(x); i++ {
+		current[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(y)]
+}
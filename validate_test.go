@@ -0,0 +1,135 @@
+package structql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	type Product struct {
+		ID       int
+		Name     string
+		Status   int
+		Category string
+	}
+	type Vendor struct {
+		ID       int
+		Products []*Product
+	}
+	type Summary struct {
+		ID    int
+		Title string
+	}
+	type expectDiagnostic struct {
+		Kind        DiagnosticKind
+		Offset      int
+		Line        int
+		Column      int
+		Suggestions []string
+	}
+
+	var testCases = []struct {
+		description string
+		query       string
+		dest        reflect.Type
+		values      []interface{}
+		expect      []expectDiagnostic
+	}{
+		{
+			description: "valid query",
+			query:       "SELECT ID, Name FROM `/Products` WHERE Category = ? ORDER BY Name",
+			values:      []interface{}{"x"},
+		},
+		{
+			description: "unknown projection field",
+			query:       "SELECT ID, Nme FROM `/Products`",
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 11, Line: 1, Column: 12, Suggestions: []string{"Name"}}},
+		},
+		{
+			description: "unknown fields on multiple lines",
+			query:       "SELECT ID\nFROM `/Products`\nWHERE Statos = 1 AND categ = 'Name' AND UPPER(Nam) = 'X'",
+			expect: []expectDiagnostic{
+				{Kind: UnknownFieldDiagnostic, Offset: 33, Line: 3, Column: 7, Suggestions: []string{"Status"}},
+				{Kind: UnknownFieldDiagnostic, Offset: 48, Line: 3, Column: 22, Suggestions: []string{"Category"}},
+				{Kind: UnknownFieldDiagnostic, Offset: 73, Line: 3, Column: 47, Suggestions: []string{"Name"}},
+			},
+		},
+		{
+			description: "unknown selector path field",
+			query:       "SELECT ID FROM `/Product[Status=1]`",
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 17, Line: 1, Column: 18, Suggestions: []string{"Products"}}},
+		},
+		{
+			description: "unknown selector criteria field",
+			query:       "SELECT ID FROM `/Products[Stat=1]`",
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 26, Line: 1, Column: 27, Suggestions: []string{"Status"}}},
+		},
+		{
+			description: "collection criteria",
+			query:       "SELECT ID FROM `/` WHERE ANY(Products[Status > 1]) AND ALL(Products[Category = 'x' OR ID > 1]) OR EXISTS(Products)",
+		},
+		{
+			description: "unknown collection criteria field",
+			query:       "SELECT ID FROM `/` WHERE ANY(Products[Stats > 1])",
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 38, Line: 1, Column: 39, Suggestions: []string{"Status"}}},
+		},
+		{
+			description: "unknown collection field",
+			query:       "SELECT ID FROM `/` WHERE ALL(Product[Status > 1])",
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 29, Line: 1, Column: 30, Suggestions: []string{"Products"}}},
+		},
+		{
+			description: "unknown order by field with alias",
+			query:       "SELECT Name AS n FROM `/Products` ORDER BY n, Categry",
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 46, Line: 1, Column: 47, Suggestions: []string{"Category"}}},
+		},
+		{
+			description: "unknown window field",
+			query:       "SELECT ID, RANK() OVER (PARTITION BY Categry ORDER BY ID) AS Rk FROM `/Products`",
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 37, Line: 1, Column: 38, Suggestions: []string{"Category"}}},
+		},
+		{
+			description: "unknown dest field",
+			query:       "SELECT ID, Name FROM `/Products`",
+			dest:        reflect.TypeOf(Summary{}),
+			expect:      []expectDiagnostic{{Kind: UnknownFieldDiagnostic, Offset: 11, Line: 1, Column: 12}},
+		},
+		{
+			description: "dest alias",
+			query:       "SELECT ID, Name AS Title FROM `/Products`",
+			dest:        reflect.TypeOf(Summary{}),
+		},
+		{
+			description: "syntax error in criteria",
+			query:       "SELECT ID FROM `/Products` WHERE ID = = 1",
+			expect:      []expectDiagnostic{{Kind: SyntaxDiagnostic, Offset: 38, Line: 1, Column: 39}},
+		},
+		{
+			description: "syntax error in selector",
+			query:       "SELECT ID FROM `/Products[ID = = 1]`",
+			expect:      []expectDiagnostic{{Kind: SyntaxDiagnostic, Offset: 31, Line: 1, Column: 32}},
+		},
+		{
+			description: "compile error",
+			query:       "SELECT ID FROM `/Products` WHERE NOPE(ID) = 1",
+			expect:      []expectDiagnostic{{Kind: CompileDiagnostic, Offset: -1}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		diagnostics := Validate(testCase.query, reflect.TypeOf(&Vendor{}), testCase.dest, testCase.values...)
+		var actual []expectDiagnostic
+		for _, diagnostic := range diagnostics {
+			actual = append(actual, expectDiagnostic{Kind: diagnostic.Kind, Offset: diagnostic.Offset, Line: diagnostic.Line, Column: diagnostic.Column, Suggestions: diagnostic.Suggestions})
+			assert.NotEmpty(t, diagnostic.Message, testCase.description)
+		}
+		assert.EqualValues(t, testCase.expect, actual, testCase.description)
+	}
+
+	diagnostics := Validate("SELECT ID FROM `/`", reflect.TypeOf(1), nil)
+	assert.EqualValues(t, 1, len(diagnostics))
+	assert.EqualValues(t, TypeDiagnostic, diagnostics[0].Kind)
+	diagnostics = Validate("SELECT Nme FROM `/Products`", reflect.TypeOf(&Vendor{}), nil)
+	assert.Contains(t, diagnostics[0].Error(), "at line 1, column 8, did you mean: Name?")
+}
@@ -1,6 +1,8 @@
 package structql
 
 import (
+	"context"
+	"github.com/viant/structql/parser/scalar"
 	"github.com/viant/xunsafe"
 )
 
@@ -9,19 +11,35 @@ type Walker struct {
 	root *Node
 }
 
-//Count counts leaf node
+//Count counts leaf node, it returns 0 when criteria function fails, use CountContext to get the error
 func (w *Walker) Count(value interface{}) int {
-	return w.count(w.root, value)
+	ret, _ := w.CountContext(context.Background(), value)
+	return ret
+}
+
+// CountContext counts leaf node, the walk is stopped with context error when context is done
+func (w *Walker) CountContext(ctx context.Context, value interface{}) (ret int, err error) {
+	defer scalar.Recover(&err)
+	return w.count(newGuard(ctx, Limits{}), w.root, value)
 }
 
 //Traverse walks the node
 func (w *Walker) Traverse(aNode *Node, value interface{}, visitor interface{}) error {
+	return w.TraverseContext(context.Background(), aNode, value, visitor)
+}
+
+// TraverseContext walks the node, the walk is stopped with context error when context is done
+func (w *Walker) TraverseContext(ctx context.Context, aNode *Node, value interface{}, visitor interface{}) (err error) {
+	defer scalar.Recover(&err)
 	nodeVisitor, _ := visitor.(NodeVisitor)
 	leafVisitor, _ := visitor.(Visitor)
-	return w.traverse(aNode, value, leafVisitor, nodeVisitor)
+	return w.traverse(newGuard(ctx, Limits{}), aNode, value, leafVisitor, nodeVisitor)
 }
 
-func (w *Walker) traverse(aNode *Node, value interface{}, visitor Visitor, nodeVisitor NodeVisitor) error {
+func (w *Walker) traverse(aGuard *guard, aNode *Node, value interface{}, visitor Visitor, nodeVisitor NodeVisitor) error {
+	if err := aGuard.visit(); err != nil {
+		return err
+	}
 	if !aNode.When(value) {
 		return nil
 	}
@@ -34,7 +52,7 @@ func (w *Walker) traverse(aNode *Node, value interface{}, visitor Visitor, nodeV
 		if visitor == nil {
 			return nil
 		}
-		return visitor(visitor)
+		return visitor(value)
 	}
 
 	ptr := xunsafe.AsPointer(value)
@@ -42,12 +60,12 @@ func (w *Walker) traverse(aNode *Node, value interface{}, visitor Visitor, nodeV
 	switch aNode.kind {
 	case nodeKindObject:
 		item = aNode.xField.Interface(ptr)
-		return w.traverse(aNode.child, item, visitor, nodeVisitor)
+		return w.traverse(aGuard, aNode.child, item, visitor, nodeVisitor)
 	case nodeKindArray:
-		sliceLen := aNode.xSlice.Len(ptr)
-		for i := 0; i < sliceLen; i++ {
-			item := aNode.xSlice.ValuePointerAt(ptr, i)
-			if err := w.traverse(aNode.child, item, visitor, nodeVisitor); err != nil {
+		from, to, at := aNode.positions(ptr)
+		for i := from; i < to; i++ {
+			item := aNode.xSlice.ValuePointerAt(ptr, at(i))
+			if err := w.traverse(aGuard, aNode.child, item, visitor, nodeVisitor); err != nil {
 				return err
 			}
 		}
@@ -55,36 +73,46 @@ func (w *Walker) traverse(aNode *Node, value interface{}, visitor Visitor, nodeV
 	return nil
 }
 
-func (w *Walker) count(aNode *Node, value interface{}) int {
+func (w *Walker) count(aGuard *guard, aNode *Node, value interface{}) (int, error) {
+	if err := aGuard.visit(); err != nil {
+		return 0, err
+	}
 	if !aNode.When(value) {
-		return 0
+		return 0, nil
 	}
 
 	ptr := xunsafe.AsPointer(value)
 	if ptr == nil {
-		return 0
+		return 0, nil
 	}
 
 	var result = 0
 	var item interface{}
 	if aNode.IsLeaf {
-		return 1
+		return 1, nil
 	}
 	switch aNode.kind {
 	case nodeKindObject:
 		item = aNode.xField.Interface(ptr)
-		return w.count(aNode.child, item)
+		return w.count(aGuard, aNode.child, item)
 	case nodeKindArray:
-		sliceLen := aNode.xSlice.Len(ptr)
-		for i := 0; i < sliceLen; i++ {
-			item := aNode.xSlice.ValuePointerAt(ptr, i)
-			result += w.count(aNode.child, item)
+		from, to, at := aNode.positions(ptr)
+		for i := from; i < to; i++ {
+			item := aNode.xSlice.ValuePointerAt(ptr, at(i))
+			count, err := w.count(aGuard, aNode.child, item)
+			if err != nil {
+				return 0, err
+			}
+			result += count
 		}
 	}
-	return result
+	return result, nil
 }
 
 func (w *Walker) mapNode(ctx *Context, aNode *Node, value interface{}) error {
+	if err := ctx.guard.visit(); err != nil {
+		return err
+	}
 	if !aNode.When(value) {
 		return nil
 	}
@@ -95,11 +123,25 @@ func (w *Walker) mapNode(ctx *Context, aNode *Node, value interface{}) error {
 	}
 
 	if aNode.IsLeaf {
+		if !ctx.mapper.aggregate {
+			if err := ctx.guard.row(); err != nil {
+				return err
+			}
+		}
 		destItem := ctx.Next(value)
 		destItemPtr := xunsafe.AsPointer(destItem)
-		if err := ctx.mapper.MapStruct(srcPtr, destItemPtr); err != nil {
+		if err := ctx.mapper.mapStruct(srcPtr, destItemPtr); err != nil {
 			return err
 		}
+		if len(ctx.mapper.windows) > 0 {
+			ctx.sources = append(ctx.sources, srcPtr)
+		}
+		if ctx.mapper.aggregate {
+			return ctx.accumulate(srcPtr)
+		}
+		if ctx.guard.limits.MaxBytes > 0 {
+			return ctx.guard.result(ctx.mapper.resultSize(destItemPtr))
+		}
 		return nil
 	}
 	var srcItem interface{}
@@ -108,20 +150,52 @@ func (w *Walker) mapNode(ctx *Context, aNode *Node, value interface{}) error {
 		srcItem = aNode.xField.Interface(srcPtr)
 		return w.mapNode(ctx, aNode.child, srcItem)
 	case nodeKindArray:
-		sliceLen := aNode.xSlice.Len(srcPtr)
-		for i := 0; i < sliceLen; i++ {
-			item := aNode.xSlice.ValuePointerAt(srcPtr, i)
+		lower, upper := aNode.bounds(aNode.xSlice.Len(srcPtr))
+		empty := lower == upper //aggregate group is created for empty slice range
+		from, to, at := aNode.positions(srcPtr)
+		for i := from; i < to; i++ {
+			item := aNode.xSlice.ValuePointerAt(srcPtr, at(i))
 			if err := w.mapNode(ctx, aNode.child, item); err != nil {
 				return err
 			}
 		}
-		if sliceLen == 0 && ctx.mapper.aggregate {
+		if empty && ctx.mapper.aggregate {
 			ctx.Next(nil)
 		}
 	}
 	return nil
 }
 
+// exists returns true when any leaf node matches, the walk stops at the first match
+func (w *Walker) exists(aGuard *guard, aNode *Node, value interface{}) (bool, error) {
+	if err := aGuard.visit(); err != nil {
+		return false, err
+	}
+	if !aNode.When(value) {
+		return false, nil
+	}
+	ptr := xunsafe.AsPointer(value)
+	if ptr == nil {
+		return false, nil
+	}
+	if aNode.IsLeaf {
+		return true, nil
+	}
+	switch aNode.kind {
+	case nodeKindObject:
+		return w.exists(aGuard, aNode.child, aNode.xField.Interface(ptr))
+	case nodeKindArray:
+		from, to, at := aNode.positions(ptr)
+		for i := from; i < to; i++ {
+			found, err := w.exists(aGuard, aNode.child, aNode.xSlice.ValuePointerAt(ptr, at(i)))
+			if found || err != nil {
+				return found, err
+			}
+		}
+	}
+	return false, nil
+}
+
 //NewWalker creates a struct walker
 func NewWalker(root *Node) *Walker {
 	return &Walker{root: root}
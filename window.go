@@ -0,0 +1,301 @@
+package structql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/viant/sqlparser"
+	"github.com/viant/sqlparser/expr"
+	"github.com/viant/sqlparser/node"
+	"github.com/viant/structql/errs"
+	sparser "github.com/viant/structql/parser"
+	"github.com/viant/structql/parser/scalar"
+	"github.com/viant/xunsafe"
+)
+
+// window functions
+const (
+	rowNumberFunction  = "ROW_NUMBER"
+	rankFunction       = "RANK"
+	denseRankFunction  = "DENSE_RANK"
+	lagFunction        = "LAG"
+	leadFunction       = "LEAD"
+	firstValueFunction = "FIRST_VALUE"
+)
+
+type (
+	// window represents window function projection computed over partitions of matched source items
+	window struct {
+		name         string
+		partition    []*xunsafe.Field
+		order        []*windowOrder
+		frame        *sparser.Frame
+		arg          compute
+		offset       int
+		defaultValue interface{}
+		aggregation  *aggregation
+	}
+
+	windowOrder struct {
+		field *xunsafe.Field
+		desc  bool
+	}
+)
+
+// compute returns window function values for the matched source items, in the source items order
+func (w *window) compute(sources []unsafe.Pointer) ([]interface{}, error) {
+	ret := make([]interface{}, len(sources))
+	for _, partition := range w.partitions(sources) {
+		sort.SliceStable(partition, func(i, j int) bool {
+			return w.compare(sources[partition[i]], sources[partition[j]]) < 0
+		})
+		rank, denseRank := 0, 0
+		var running Aggregator //aggregator reused while frame start is unbounded
+		accumulated := 0
+		for position, index := range partition {
+			peer := position > 0 && w.compare(sources[partition[position-1]], sources[index]) == 0
+			if !peer {
+				rank = position + 1
+				denseRank++
+			}
+			switch w.name {
+			case rowNumberFunction:
+				ret[index] = position + 1
+			case rankFunction:
+				ret[index] = rank
+			case denseRankFunction:
+				ret[index] = denseRank
+			case lagFunction, leadFunction:
+				offset := w.offset
+				if w.name == lagFunction {
+					offset = -offset
+				}
+				ret[index] = w.defaultValue
+				if other := position + offset; other >= 0 && other < len(partition) {
+					ret[index] = w.arg(sources[partition[other]])
+				}
+			case firstValueFunction:
+				if from, to := w.bounds(sources, partition, position); from <= to {
+					ret[index] = w.arg(sources[partition[from]])
+				}
+			default:
+				from, to := w.bounds(sources, partition, position)
+				if running == nil || !w.unboundedStart() { //sliding frame is recomputed for each row
+					running, accumulated = w.aggregation.newAggregator(), from
+				}
+				for ; accumulated <= to; accumulated++ {
+					if err := w.aggregation.accumulate(running, sources[partition[accumulated]]); err != nil {
+						return nil, err
+					}
+				}
+				ret[index] = running.Result()
+			}
+		}
+	}
+	return ret, nil
+}
+
+// unboundedStart returns true if frame starts at the first partition row, frame end does not decrease for the following rows,
+// so that aggregate window values are accumulated incrementally
+func (w *window) unboundedStart() bool {
+	return w.frame == nil || w.frame.Start.Unbounded
+}
+
+// partitions returns source item indexes grouped by partition in the order partitions were first matched
+func (w *window) partitions(sources []unsafe.Pointer) [][]int {
+	if len(w.partition) == 0 {
+		all := make([]int, len(sources))
+		for i := range all {
+			all[i] = i
+		}
+		return [][]int{all}
+	}
+	var ret [][]int
+	positions := map[interface{}]int{}
+	keyType := reflect.ArrayOf(len(w.partition), reflect.TypeOf((*interface{})(nil)).Elem())
+	for i, src := range sources {
+		key := reflect.New(keyType).Elem()
+		for k, aField := range w.partition {
+			if value := fieldValue(aField, src); value != nil {
+				key.Index(k).Set(reflect.ValueOf(value))
+			}
+		}
+		position, ok := positions[key.Interface()]
+		if !ok {
+			position = len(ret)
+			positions[key.Interface()] = position
+			ret = append(ret, nil)
+		}
+		ret[position] = append(ret[position], i)
+	}
+	return ret
+}
+
+// compare compares source items by window ORDER BY, NULL values are ordered first
+func (w *window) compare(x, y unsafe.Pointer) int {
+	for _, item := range w.order {
+		xValue, yValue := fieldValue(item.field, x), fieldValue(item.field, y)
+		ret := 0
+		switch {
+		case xValue == nil && yValue == nil:
+		case xValue == nil:
+			ret = -1
+		case yValue == nil:
+			ret = 1
+		default:
+			ret = compareValues(xValue, yValue)
+		}
+		if item.desc {
+			ret = -ret
+		}
+		if ret != 0 {
+			return ret
+		}
+	}
+	return 0
+}
+
+// bounds returns inclusive frame positions for the current partition position, without ROWS frame
+// the frame ends with the last peer of the current row when ORDER BY is used, or spans the whole partition
+func (w *window) bounds(sources []unsafe.Pointer, partition []int, position int) (int, int) {
+	last := len(partition) - 1
+	if w.frame == nil {
+		if len(w.order) == 0 {
+			return 0, last
+		}
+		to := position
+		for to < last && w.compare(sources[partition[to+1]], sources[partition[position]]) == 0 {
+			to++
+		}
+		return 0, to
+	}
+	from, to := 0, last
+	if !w.frame.Start.Unbounded {
+		from = max(position+w.frame.Start.Offset, 0)
+	}
+	if !w.frame.End.Unbounded {
+		to = min(position+w.frame.End.Offset, last)
+	}
+	return from, to
+}
+
+// compileWindow compiles window function projection, it returns window and result type
+func compileWindow(source reflect.Type, call *expr.Call, spec *sparser.Window, functions *scalar.Registry, aggregates *aggregates) (*window, reflect.Type, error) {
+	name := strings.ToUpper(sqlparser.Stringify(call.X))
+	ret := &window{name: name, frame: spec.Frame}
+	for _, column := range spec.Partition {
+		aField := xunsafe.FieldByName(source, column)
+		if aField == nil {
+			return nil, nil, errs.UnknownField("failed to lookup partition field: '%s' at %s", column, source.String())
+		}
+		keyType := aField.Type
+		if keyType.Kind() == reflect.Ptr {
+			keyType = keyType.Elem()
+		}
+		if !keyType.Comparable() {
+			return nil, nil, errs.UnsupportedType("unsupported partition field type: '%s' %s", column, aField.Type.String())
+		}
+		ret.partition = append(ret.partition, aField)
+	}
+	for _, item := range spec.Order {
+		aField := xunsafe.FieldByName(source, item.Column)
+		if aField == nil {
+			return nil, nil, errs.UnknownField("failed to lookup window order field: '%s' at %s", item.Column, source.String())
+		}
+		switch scalar.KindOf(aField.Type) {
+		case scalar.Int, scalar.Float, scalar.String, scalar.Time:
+		default:
+			return nil, nil, errs.UnsupportedType("unsupported window order field type: '%s' %s", item.Column, aField.Type.String())
+		}
+		ret.order = append(ret.order, &windowOrder{field: aField, desc: item.Desc})
+	}
+	switch name {
+	case rowNumberFunction, rankFunction, denseRankFunction:
+		if len(call.Args) != 0 {
+			return nil, nil, fmt.Errorf("invalid %v arguments count: %v", name, len(call.Args))
+		}
+		return ret, reflect.TypeOf(0), nil
+	case lagFunction, leadFunction, firstValueFunction:
+		maxArgs := 3
+		if name == firstValueFunction {
+			maxArgs = 1
+		}
+		if len(call.Args) == 0 || len(call.Args) > maxArgs {
+			return nil, nil, fmt.Errorf("invalid %v arguments count: %v", name, len(call.Args))
+		}
+		arg, argType, err := compileAggregateArgument(source, call.Args[0], functions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %v argument: %w", name, err)
+		}
+		ret.arg, ret.offset = arg, 1
+		if len(call.Args) > 1 {
+			if ret.offset, err = windowOffset(call.Args[1]); err != nil {
+				return nil, nil, fmt.Errorf("invalid %v offset: %w", name, err)
+			}
+		}
+		if len(call.Args) > 2 {
+			if ret.defaultValue, err = windowDefault(call.Args[2], argType); err != nil {
+				return nil, nil, fmt.Errorf("invalid %v default: %w", name, err)
+			}
+		}
+		return ret, argType, nil
+	}
+	function := aggregates.lookup(name)
+	if function == nil {
+		return nil, nil, fmt.Errorf("unsupported window function: %v", name)
+	}
+	anAggregation, resultType, err := compileAggregation(source, function, call, functions)
+	if err != nil {
+		return nil, nil, err
+	}
+	ret.aggregation = anAggregation
+	return ret, resultType, nil
+}
+
+// windowOffset returns LAG/LEAD offset literal
+func windowOffset(n node.Node) (int, error) {
+	ret, err := strconv.Atoi(sqlparser.Stringify(n))
+	if err != nil || ret < 0 {
+		return 0, fmt.Errorf("%v, expected non negative integer", sqlparser.Stringify(n))
+	}
+	return ret, nil
+}
+
+// windowDefault returns LAG/LEAD default literal converted to the argument type
+func windowDefault(n node.Node, argType reflect.Type) (interface{}, error) {
+	value, _, err := literalArgument(n)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	rValue := reflect.ValueOf(value)
+	if !rValue.CanConvert(argType) || scalar.KindOf(rValue.Type()).IsNumeric() != scalar.KindOf(argType).IsNumeric() {
+		return nil, errs.Conversion("failed to convert %v to %s", sqlparser.Stringify(n), argType.String())
+	}
+	return rValue.Convert(argType).Interface(), nil
+}
+
+// literalArgument returns literal argument value
+func literalArgument(n node.Node) (interface{}, scalar.Kind, error) {
+	switch actual := n.(type) {
+	case *expr.Literal:
+		return literal(actual)
+	case *expr.Unary:
+		if literalValue, ok := actual.X.(*expr.Literal); ok && actual.Op == "-" {
+			value, kind, err := literal(literalValue)
+			if err != nil {
+				return nil, scalar.Any, err
+			}
+			switch kind {
+			case scalar.Int:
+				return -value.(int), kind, nil
+			case scalar.Float:
+				return -value.(float64), kind, nil
+			}
+		}
+	}
+	return nil, scalar.Any, fmt.Errorf("unsupported argument: %v, expected literal", sqlparser.Stringify(n))
+}
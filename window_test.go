@@ -0,0 +1,163 @@
+package structql
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	type Product struct {
+		ID       int
+		VendorID int
+		Revenue  float64
+		Rating   *int
+	}
+	type Metric struct {
+		Day   time.Time
+		Value int
+	}
+	rating := 3
+	products := []*Product{{ID: 1, VendorID: 1, Revenue: 10}, {ID: 2, VendorID: 1, Revenue: 30, Rating: &rating}, {ID: 3, VendorID: 2, Revenue: 5}, {ID: 4, VendorID: 1, Revenue: 30}, {ID: 5, VendorID: 2, Revenue: 7}}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics := []Metric{{Day: day.AddDate(0, 0, 2), Value: 7}, {Day: day, Value: 3}, {Day: day.AddDate(0, 0, 1), Value: 4}}
+
+	var testCases = []struct {
+		description string
+		query       string
+		source      interface{}
+		values      []interface{}
+		expect      string
+		hasError    bool
+	}{
+		{
+			description: "ranking within partition",
+			query:       "SELECT ID, ROW_NUMBER() OVER (PARTITION BY VendorID ORDER BY Revenue DESC) AS Rn, RANK() OVER (PARTITION BY VendorID ORDER BY Revenue DESC) AS Rk, DENSE_RANK() OVER (PARTITION BY VendorID ORDER BY Revenue DESC) AS Dr FROM `/`",
+			source:      products,
+			expect:      `[{"ID":1,"Rn":3,"Rk":3,"Dr":2},{"ID":2,"Rn":1,"Rk":1,"Dr":1},{"ID":3,"Rn":2,"Rk":2,"Dr":2},{"ID":4,"Rn":2,"Rk":1,"Dr":1},{"ID":5,"Rn":1,"Rk":1,"Dr":1}]`,
+		},
+		{
+			description: "computed after filtering",
+			query:       "SELECT ID, ROW_NUMBER() OVER (ORDER BY Revenue) AS Rn FROM `/` WHERE VendorID = ?",
+			source:      products,
+			values:      []interface{}{2},
+			expect:      `[{"ID":3,"Rn":1},{"ID":5,"Rn":2}]`,
+		},
+		{
+			description: "lag and lead",
+			query:       "SELECT ID, LAG(Revenue) OVER (ORDER BY ID) AS Prev, LEAD(Revenue, 2, -1) OVER (ORDER BY ID) AS Next FROM `/`",
+			source:      products,
+			expect:      `[{"ID":1,"Prev":0,"Next":5},{"ID":2,"Prev":10,"Next":30},{"ID":3,"Prev":30,"Next":7},{"ID":4,"Prev":5,"Next":-1},{"ID":5,"Prev":30,"Next":-1}]`,
+		},
+		{
+			description: "delta between consecutive metrics",
+			query:       "SELECT Day, Value, LAG(Value, 1, 0) OVER (ORDER BY Day) AS Prev FROM `/`",
+			source:      metrics,
+			expect:      `[{"Value":7,"Prev":4},{"Value":3,"Prev":0},{"Value":4,"Prev":3}]`,
+		},
+		{
+			description: "first value",
+			query:       "SELECT ID, FIRST_VALUE(ID) OVER (PARTITION BY VendorID ORDER BY Revenue) AS Lowest, FIRST_VALUE(Rating) OVER (PARTITION BY VendorID ORDER BY Revenue DESC, ID) AS Rating FROM `/`",
+			source:      products,
+			expect:      `[{"ID":1,"Lowest":1,"Rating":3},{"ID":2,"Lowest":1,"Rating":3},{"ID":3,"Lowest":3,"Rating":0},{"ID":4,"Lowest":1,"Rating":3},{"ID":5,"Lowest":3,"Rating":0}]`,
+		},
+		{
+			description: "sum frames",
+			query:       "SELECT ID, SUM(Revenue) OVER (PARTITION BY VendorID ORDER BY ID ROWS BETWEEN 1 PRECEDING AND CURRENT ROW) AS Moving, SUM(Revenue) OVER (PARTITION BY VendorID) AS Total, SUM(Revenue) OVER (ORDER BY VendorID) AS Running, COUNT(*) OVER (ORDER BY ID ROWS BETWEEN CURRENT ROW AND UNBOUNDED FOLLOWING) AS Remaining FROM `/`",
+			source:      products,
+			expect:      `[{"ID":1,"Moving":10,"Total":70,"Running":70,"Remaining":5},{"ID":2,"Moving":40,"Total":70,"Running":70,"Remaining":4},{"ID":3,"Moving":5,"Total":12,"Running":82,"Remaining":3},{"ID":4,"Moving":60,"Total":70,"Running":70,"Remaining":2},{"ID":5,"Moving":12,"Total":12,"Running":82,"Remaining":1}]`,
+		},
+		{
+			description: "unknown partition field",
+			query:       "SELECT ID, ROW_NUMBER() OVER (PARTITION BY Vendor) AS Rn FROM `/`",
+			source:      products,
+			hasError:    true,
+		},
+		{
+			description: "unsupported window function",
+			query:       "SELECT ID, UPPER(ID) OVER (ORDER BY ID) AS Rn FROM `/`",
+			source:      products,
+			hasError:    true,
+		},
+		{
+			description: "window with aggregation",
+			query:       "SELECT VendorID, COUNT(*) AS Cnt, RANK() OVER (ORDER BY VendorID) AS Rk FROM `/` GROUP BY VendorID",
+			source:      products,
+			hasError:    true,
+		},
+		{
+			description: "invalid frame",
+			query:       "SELECT ID, SUM(Revenue) OVER (ORDER BY ID ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) AS S FROM `/`",
+			source:      products,
+			hasError:    true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		query, err := NewQuery(testCase.query, reflect.TypeOf(testCase.source), nil, testCase.values...)
+		if testCase.hasError {
+			assert.NotNil(t, err, testCase.description)
+			continue
+		}
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		actual, err := query.Select(testCase.source)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assertly.AssertValues(t, testCase.expect, actual, testCase.description)
+	}
+
+	for _, testCase := range []struct {
+		description string
+		frame       string
+		expect      string
+		expectCalls int
+	}{
+		{description: "running sum", frame: "ORDER BY ID", expect: `[{"S":10},{"S":40},{"S":45},{"S":75},{"S":82}]`, expectCalls: 5},
+		{description: "unbounded preceding frame", frame: "ORDER BY ID ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW", expect: `[{"S":10},{"S":40},{"S":45},{"S":75},{"S":82}]`, expectCalls: 5},
+		{description: "sliding frame", frame: "ORDER BY ID ROWS BETWEEN 1 PRECEDING AND CURRENT ROW", expect: `[{"S":10},{"S":40},{"S":35},{"S":35},{"S":37}]`, expectCalls: 9},
+	} {
+		calls := 0
+		query, err := NewQuery("SELECT CSUM(Revenue) OVER ("+testCase.frame+") AS S FROM `/`", reflect.TypeOf(products), nil, WithAggregate("CSUM", reflect.TypeOf(0.0), func() Aggregator {
+			return &countingSum{calls: &calls}
+		}))
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		actual, err := query.Select(products)
+		if !assert.Nil(t, err, testCase.description) {
+			continue
+		}
+		assertly.AssertValues(t, testCase.expect, actual, testCase.description)
+		assert.EqualValues(t, testCase.expectCalls, calls, testCase.description)
+	}
+}
+
+// countingSum sums float64 values and counts Accumulate calls
+type countingSum struct {
+	calls *int
+	sum   float64
+}
+
+func (a *countingSum) Init() {
+	a.sum = 0
+}
+
+func (a *countingSum) Accumulate(args ...interface{}) error {
+	*a.calls++
+	a.sum += args[0].(float64)
+	return nil
+}
+
+func (a *countingSum) Merge(other Aggregator) error {
+	a.sum += other.(*countingSum).sum
+	return nil
+}
+
+func (a *countingSum) Result() interface{} {
+	return a.sum
+}